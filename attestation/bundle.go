@@ -0,0 +1,215 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	crt "github.com/ibm-hyper-protect/contract-go/v2/common/cert"
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	// Version of the evidence bundle format
+	EvidenceBundleVersion = 1
+)
+
+// EvidenceBundle is a self-contained record of attestation evidence and its verification,
+// serialized as JSON. Binary values (signature and CRLs) are base64 encoded.
+type EvidenceBundle struct {
+	// Bundle format version, EvidenceBundleVersion
+	Version int `json:"version"`
+	// Time the bundle and its CRL snapshot were created
+	CreatedAt time.Time `json:"createdAt"`
+	// Encrypted attestation records (se-checksums.txt.enc)
+	EncryptedRecords string `json:"encryptedRecords"`
+	// Decrypted attestation records (se-checksums.txt)
+	Records string `json:"records"`
+	// Signature of the decrypted records (se-signature.bin)
+	Signature []byte `json:"signature"`
+	// IBM attestation certificate
+	AttestationCert string `json:"attestationCert"`
+	// IBM intermediate certificate
+	IbmIntermediateCert string `json:"ibmIntermediateCert"`
+	// DigiCert intermediate certificate
+	DigicertIntermediateCert string `json:"digicertIntermediateCert"`
+	// DigiCert root certificate
+	DigicertRootCert string `json:"digicertRootCert"`
+	// CRLs of the certificate chain keyed by distribution point URL
+	CRLs crt.CRLSnapshot `json:"crls"`
+	// Expected measurements the records were compared with
+	Expected *AttestationRecords `json:"expected,omitempty"`
+	// Result of the verification run when the bundle was created
+	Verdict *Verdict `json:"verdict"`
+}
+
+// HpcrCreateEvidenceBundle verifies attestation evidence and packages it into a self-contained JSON
+// evidence bundle for auditors.
+//
+// The bundle contains the encrypted and decrypted records, the signature, the attestation, intermediate
+// and root certificates, a snapshot of the CRLs of the certificate chain, the expected measurements, the
+// verification verdict and timestamps. The verification runs against the CRL snapshot, so the recorded
+// verdict is the one an offline re-verification with [HpcrVerifyEvidenceBundle] reproduces. The bundle
+// is created even if verification fails; check the returned verdict.
+//
+// Parameters:
+//   - evidence: Attestation data received from the instance
+//   - policy: Private key, trust anchors and expected measurements (see [Verify])
+//
+// Returns:
+//   - Evidence bundle JSON
+//   - Verdict of the verification recorded in the bundle
+//   - Error if a certificate is missing, the CRL snapshot cannot be captured or the bundle cannot be encoded
+func HpcrCreateEvidenceBundle(evidence Evidence, policy Policy) (string, *Verdict, error) {
+	if gen.CheckIfEmpty(evidence.AttestationCert, policy.IbmIntermediateCert, policy.DigicertIntermediateCert, policy.DigicertRootCert) {
+		return "", nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	createdAt := time.Now().UTC()
+	crls, err := crt.SnapshotCRLs(policy.DigicertIntermediateCert, policy.IbmIntermediateCert, evidence.AttestationCert)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to capture CRL snapshot - %v", err)
+	}
+
+	verdict, records, _ := verify(evidence, policy, "", crls)
+
+	bundle := EvidenceBundle{
+		Version:                  EvidenceBundleVersion,
+		CreatedAt:                createdAt,
+		EncryptedRecords:         evidence.EncryptedRecords,
+		Records:                  records,
+		Signature:                []byte(evidence.Signature),
+		AttestationCert:          evidence.AttestationCert,
+		IbmIntermediateCert:      policy.IbmIntermediateCert,
+		DigicertIntermediateCert: policy.DigicertIntermediateCert,
+		DigicertRootCert:         policy.DigicertRootCert,
+		CRLs:                     crls,
+		Expected:                 policy.Expected,
+		Verdict:                  verdict,
+	}
+
+	bundleJson, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode evidence bundle - %v", err)
+	}
+
+	return string(bundleJson), verdict, nil
+}
+
+// HpcrVerifyEvidenceBundle re-verifies an evidence bundle offline.
+//
+// All steps of [Verify] are repeated: certificate revocation is checked against the bundled CRL
+// snapshot and nothing is downloaded. The trust anchor and the expected measurements are taken from
+// the policy of the caller, never from the bundle, so a forged bundle carrying its own root, chain,
+// CRLs and expected values does not verify. The DigiCert root defaults to the embedded DigiCert
+// Trusted Root G4, and the bundle is rejected if its root certificate or its expected measurements
+// differ from those of the policy. The intermediate certificates of the policy are used when set,
+// otherwise those of the bundle, which must chain to the trust anchor.
+//
+// Without a private key in the policy the decrypted records from the bundle are used, and the
+// signature step authenticates them. With a private key the encrypted records are decrypted again and
+// must match the bundled records. Certificate and CRL validity periods are checked at Policy.At or the
+// current time, and a bundle created after that time is rejected. To verify an archived bundle after
+// its CRL snapshot has expired, set Policy.AtBundleCreation to check them at the creation time of the
+// bundle instead; that time is taken from the bundle, so only do so for bundles from trusted storage.
+//
+// Parameters:
+//   - bundle: Evidence bundle JSON (output from [HpcrCreateEvidenceBundle])
+//   - policy: Optional private key and password, trust anchor, intermediates and expected measurements
+//
+// Returns:
+//   - Verdict of the offline verification
+//   - Error if the bundle is invalid, does not match the policy or a verification step did not pass
+func HpcrVerifyEvidenceBundle(bundle string, policy Policy) (*Verdict, error) {
+	if gen.CheckIfEmpty(bundle) {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	var parsed EvidenceBundle
+	if err := json.Unmarshal([]byte(bundle), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse evidence bundle - %v", err)
+	}
+
+	if parsed.Version != EvidenceBundleVersion {
+		return nil, fmt.Errorf("unsupported evidence bundle version %d", parsed.Version)
+	}
+
+	if len(parsed.CRLs) == 0 {
+		return nil, fmt.Errorf("evidence bundle does not contain a CRL snapshot")
+	}
+
+	if policy.DigicertRootCert == "" {
+		policy.DigicertRootCert = crt.DigicertTrustedRootG4
+	}
+	if parsed.DigicertRootCert != "" && !sameCertificate(parsed.DigicertRootCert, policy.DigicertRootCert) {
+		return nil, fmt.Errorf("DigiCert root certificate of the evidence bundle does not match the trust anchor")
+	}
+
+	if parsed.Expected != nil && policy.Expected != nil && !reflect.DeepEqual(parsed.Expected, policy.Expected) {
+		return nil, fmt.Errorf("expected measurements of the evidence bundle do not match the expected measurements of the policy")
+	}
+
+	if policy.IbmIntermediateCert == "" {
+		policy.IbmIntermediateCert = parsed.IbmIntermediateCert
+	}
+	if policy.DigicertIntermediateCert == "" {
+		policy.DigicertIntermediateCert = parsed.DigicertIntermediateCert
+	}
+	if policy.At.IsZero() {
+		policy.At = time.Now()
+	}
+	if parsed.CreatedAt.After(policy.At) {
+		return nil, fmt.Errorf("evidence bundle created at %s is later than the verification time %s",
+			parsed.CreatedAt.UTC().Format(time.RFC3339), policy.At.UTC().Format(time.RFC3339))
+	}
+	if policy.AtBundleCreation {
+		if parsed.CreatedAt.IsZero() {
+			return nil, fmt.Errorf("evidence bundle does not have a creation time")
+		}
+		policy.At = parsed.CreatedAt
+	}
+
+	evidence := Evidence{
+		EncryptedRecords: parsed.EncryptedRecords,
+		Signature:        string(parsed.Signature),
+		AttestationCert:  parsed.AttestationCert,
+	}
+
+	verdict, _, err := verify(evidence, policy, parsed.Records, parsed.CRLs)
+	return verdict, err
+}
+
+// sameCertificate reports whether two PEM-formatted certificates are the same certificate.
+func sameCertificate(a, b string) bool {
+	blockA, _ := pem.Decode([]byte(strings.TrimSpace(a)))
+	blockB, _ := pem.Decode([]byte(strings.TrimSpace(b)))
+	if blockA == nil || blockB == nil {
+		return false
+	}
+
+	certA, errA := x509.ParseCertificate(blockA.Bytes)
+	certB, errB := x509.ParseCertificate(blockB.Bytes)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return certA.Equal(certB)
+}
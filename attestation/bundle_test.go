@@ -0,0 +1,173 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testcase to check if HpcrCreateEvidenceBundle() packages the evidence and HpcrVerifyEvidenceBundle() re-verifies it offline
+func TestHpcrEvidenceBundle(t *testing.T) {
+	fixture := newVerifyFixture(t, false)
+
+	bundle, verdict, err := HpcrCreateEvidenceBundle(fixture.evidence, fixture.policy)
+	require.NoError(t, err)
+	assert.True(t, verdict.Verified)
+
+	var parsed EvidenceBundle
+	require.NoError(t, json.Unmarshal([]byte(bundle), &parsed))
+	assert.Equal(t, EvidenceBundleVersion, parsed.Version)
+	assert.Contains(t, parsed.Records, EntryBaseImage)
+	assert.Equal(t, fixture.evidence.Signature, string(parsed.Signature))
+	assert.Len(t, parsed.CRLs, 3)
+	assert.True(t, parsed.Verdict.Verified)
+	assert.False(t, parsed.CreatedAt.IsZero())
+
+	// remove the published CRLs so that re-verification can only use the snapshot
+	require.NoError(t, os.RemoveAll(fixture.crlDir))
+
+	auditor := Policy{DigicertRootCert: fixture.policy.DigicertRootCert, Expected: fixture.policy.Expected}
+	verdict, err = HpcrVerifyEvidenceBundle(bundle, auditor)
+	require.NoError(t, err)
+	assert.True(t, verdict.Verified)
+	assert.False(t, verdict.VerifiedAt.Before(parsed.CreatedAt))
+	step, _ := verdict.Step(StepDecrypt)
+	assert.Equal(t, "decrypted records taken from the evidence bundle", step.Message)
+
+	auditor.PrivateKey = fixture.policy.PrivateKey
+	verdict, err = HpcrVerifyEvidenceBundle(bundle, auditor)
+	require.NoError(t, err)
+	step, _ = verdict.Step(StepDecrypt)
+	assert.Equal(t, "attestation records decrypted", step.Message)
+}
+
+// Testcase to check if HpcrVerifyEvidenceBundle() takes the trust anchor and expected measurements from the caller
+func TestHpcrVerifyEvidenceBundleTrust(t *testing.T) {
+	fixture := newVerifyFixture(t, false)
+
+	bundle, _, err := HpcrCreateEvidenceBundle(fixture.evidence, fixture.policy)
+	require.NoError(t, err)
+
+	// a bundle with its own root does not verify against the embedded DigiCert root
+	_, err = HpcrVerifyEvidenceBundle(bundle, Policy{Expected: fixture.policy.Expected})
+	assert.EqualError(t, err, "DigiCert root certificate of the evidence bundle does not match the trust anchor")
+
+	other := newVerifyFixture(t, false)
+	_, err = HpcrVerifyEvidenceBundle(bundle, Policy{DigicertRootCert: other.policy.DigicertRootCert, Expected: fixture.policy.Expected})
+	assert.EqualError(t, err, "DigiCert root certificate of the evidence bundle does not match the trust anchor")
+
+	// a forged chain that claims the trusted root still has to chain to it
+	var parsed EvidenceBundle
+	require.NoError(t, json.Unmarshal([]byte(bundle), &parsed))
+	parsed.DigicertIntermediateCert = other.policy.DigicertIntermediateCert
+	forged, err := json.Marshal(parsed)
+	require.NoError(t, err)
+	verdict, err := HpcrVerifyEvidenceBundle(string(forged), Policy{DigicertRootCert: fixture.policy.DigicertRootCert, Expected: fixture.policy.Expected})
+	assert.Error(t, err)
+	assert.Equal(t, StepFailed, stepStatuses(verdict)[StepCertificateChain])
+
+	// the expected measurements of the bundle are not trusted
+	_, err = HpcrVerifyEvidenceBundle(bundle, Policy{DigicertRootCert: fixture.policy.DigicertRootCert,
+		Expected: &AttestationRecords{HashAlgorithm: HashAlgorithmSha256, Entries: fixture.policy.Expected.Entries[:1]}})
+	assert.EqualError(t, err, "expected measurements of the evidence bundle do not match the expected measurements of the policy")
+
+	verdict, err = HpcrVerifyEvidenceBundle(bundle, Policy{DigicertRootCert: fixture.policy.DigicertRootCert})
+	assert.Error(t, err)
+	assert.Equal(t, StepFailed, stepStatuses(verdict)[StepMeasurements])
+}
+
+// Testcase to check if HpcrVerifyEvidenceBundle() checks validity periods at the verification time unless the creation time is requested
+func TestHpcrVerifyEvidenceBundleValidationTime(t *testing.T) {
+	fixture := newVerifyFixture(t, false)
+
+	bundle, _, err := HpcrCreateEvidenceBundle(fixture.evidence, fixture.policy)
+	require.NoError(t, err)
+
+	auditor := Policy{DigicertRootCert: fixture.policy.DigicertRootCert, Expected: fixture.policy.Expected}
+	verdict, err := HpcrVerifyEvidenceBundle(bundle, auditor)
+	require.NoError(t, err)
+	assert.True(t, verdict.Verified)
+
+	// after the nextUpdate of the snapshot CRLs
+	auditor.At = time.Now().Add(48 * time.Hour)
+	verdict, err = HpcrVerifyEvidenceBundle(bundle, auditor)
+	assert.Error(t, err)
+	assert.False(t, verdict.Verified)
+
+	// archived bundles can be checked at their creation time on request
+	auditor.AtBundleCreation = true
+	verdict, err = HpcrVerifyEvidenceBundle(bundle, auditor)
+	require.NoError(t, err)
+	assert.True(t, verdict.Verified)
+
+	// a creation time after the verification time is rejected
+	var parsed EvidenceBundle
+	require.NoError(t, json.Unmarshal([]byte(bundle), &parsed))
+	parsed.CreatedAt = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	future, err := json.Marshal(parsed)
+	require.NoError(t, err)
+	_, err = HpcrVerifyEvidenceBundle(string(future), Policy{DigicertRootCert: fixture.policy.DigicertRootCert, Expected: fixture.policy.Expected, AtBundleCreation: true})
+	assert.ErrorContains(t, err, "evidence bundle created at 2100-01-01T00:00:00Z is later than the verification time")
+}
+
+// Testcase to check if HpcrVerifyEvidenceBundle() detects tampered bundles
+func TestHpcrVerifyEvidenceBundleTampered(t *testing.T) {
+	fixture := newVerifyFixture(t, false)
+
+	bundle, _, err := HpcrCreateEvidenceBundle(fixture.evidence, fixture.policy)
+	require.NoError(t, err)
+
+	var parsed EvidenceBundle
+	require.NoError(t, json.Unmarshal([]byte(bundle), &parsed))
+	parsed.Records += "0000000000000000000000000000000000000000000000000000000000000000 extra\n"
+	tampered, err := json.Marshal(parsed)
+	require.NoError(t, err)
+
+	auditor := Policy{DigicertRootCert: fixture.policy.DigicertRootCert, Expected: fixture.policy.Expected}
+	verdict, err := HpcrVerifyEvidenceBundle(string(tampered), auditor)
+	assert.EqualError(t, err, "attestation verification failed - steps not passed: signature")
+	assert.False(t, verdict.Verified)
+
+	auditor.PrivateKey = fixture.policy.PrivateKey
+	verdict, err = HpcrVerifyEvidenceBundle(string(tampered), auditor)
+	assert.Error(t, err)
+	step, _ := verdict.Step(StepDecrypt)
+	assert.Equal(t, "decrypted records do not match the records in the evidence bundle", step.Message)
+}
+
+// Testcase to check if the evidence bundle functions handle invalid input
+func TestHpcrEvidenceBundleInvalid(t *testing.T) {
+	_, _, err := HpcrCreateEvidenceBundle(Evidence{}, Policy{})
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, err = HpcrVerifyEvidenceBundle("", Policy{})
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, err = HpcrVerifyEvidenceBundle("not json", Policy{})
+	assert.ErrorContains(t, err, "failed to parse evidence bundle")
+
+	_, err = HpcrVerifyEvidenceBundle(`{"version": 2}`, Policy{})
+	assert.EqualError(t, err, "unsupported evidence bundle version 2")
+
+	_, err = HpcrVerifyEvidenceBundle(`{"version": 1}`, Policy{})
+	assert.EqualError(t, err, "evidence bundle does not contain a CRL snapshot")
+}
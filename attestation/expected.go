@@ -0,0 +1,169 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	// Reasons reported for entries that do not match the expected measurements
+	MismatchMissing = "missing"
+	MismatchDigest  = "digest-mismatch"
+)
+
+// contractSectionEntries lists the top level contract sections that are measured individually.
+var contractSectionEntries = []string{EntryAttestationPublicKey, EntryEnv, EntryWorkload}
+
+// AttestationComparison is the result of comparing expected measurements with attestation records.
+type AttestationComparison struct {
+	// True if every expected entry is present in the records with the same digest
+	Match bool `json:"match" yaml:"match"`
+	// Expected entries that are missing from the records or carry a different digest
+	Mismatches []AttestationMismatch `json:"mismatches,omitempty" yaml:"mismatches,omitempty"`
+}
+
+// AttestationMismatch describes an expected entry that does not match the attestation records.
+type AttestationMismatch struct {
+	// Entry name, e.g. "cidata/user-data"
+	Name string `json:"name" yaml:"name"`
+	// MismatchMissing or MismatchDigest
+	Reason string `json:"reason" yaml:"reason"`
+	// Digest computed from the contract or given by the caller
+	Expected string `json:"expected" yaml:"expected"`
+	// Digest found in the attestation records, empty if the entry is missing
+	Actual string `json:"actual,omitempty" yaml:"actual,omitempty"`
+}
+
+// HpcrExpectedAttestationRecords computes the attestation entries expected for a contract.
+//
+// The instance measures the user-data it was started with, which is the final contract, as well as
+// each of its top level sections. The function therefore returns the "baseimage" entry given by the
+// caller, the "cidata/user-data" digest of the exact contract bytes, and the "attestationPublicKey",
+// "env" and "workload" digests of the section values present in the contract. Pass the contract exactly
+// as it was handed to the instance; any change, including whitespace, changes the user-data digest.
+// The hash algorithm is taken from the length of the base image checksum.
+//
+// Parameters:
+//   - contract: Final contract as deployed, usually the output of HpcrContractSignedEncrypted
+//   - baseImageChecksum: Hex checksum of the base image, as published with the image
+//
+// Returns:
+//   - Expected attestation records containing the predicted entries
+//   - Error if a parameter is missing, the checksum is invalid or the contract cannot be parsed
+func HpcrExpectedAttestationRecords(contract, baseImageChecksum string) (*AttestationRecords, error) {
+	if gen.CheckIfEmpty(contract, baseImageChecksum) {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	baseImage, ok, err := parseAttestationEntry(strings.TrimSpace(baseImageChecksum) + " " + EntryBaseImage)
+	if err != nil || !ok {
+		return nil, fmt.Errorf("invalid base image checksum - expected a sha256, sha384 or sha512 hex digest")
+	}
+	algorithm := hashAlgorithmsByDigestLength[len(baseImage.Digest)]
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(contract), &document); err != nil {
+		return nil, fmt.Errorf("failed to parse contract - %v", err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse contract - contract is not a YAML map")
+	}
+
+	expected := &AttestationRecords{
+		HashAlgorithm: algorithm,
+		Entries: []AttestationEntry{
+			baseImage,
+			{Name: EntryCidataUserData, Digest: digestHex(algorithm, contract)},
+		},
+	}
+
+	root := document.Content[0]
+	for _, section := range contractSectionEntries {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value != section {
+				continue
+			}
+			if root.Content[i+1].Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("failed to parse contract - %s section is not a string", section)
+			}
+			expected.Entries = append(expected.Entries, AttestationEntry{Name: section, Digest: digestHex(algorithm, root.Content[i+1].Value)})
+			break
+		}
+	}
+
+	return expected, nil
+}
+
+// HpcrCompareAttestationRecords compares expected measurements with the decrypted attestation records.
+//
+// Every expected entry must be present in the records with the same digest. Entries in the records
+// that are not expected, such as "root.tar.gz" or "cidata/meta-data", are ignored.
+//
+// Parameters:
+//   - expected: Expected records, usually from [HpcrExpectedAttestationRecords]
+//   - actual: Records decrypted from the instance, from [HpcrParseAttestationRecords] or [HpcrGetParsedAttestationRecords]
+//
+// Returns:
+//   - Comparison result listing every mismatching entry
+//   - Error if a parameter is missing or the records use different hash algorithms
+func HpcrCompareAttestationRecords(expected, actual *AttestationRecords) (*AttestationComparison, error) {
+	if expected == nil || actual == nil {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	if expected.HashAlgorithm != actual.HashAlgorithm {
+		return nil, fmt.Errorf("hash algorithm mismatch - expected %s, attestation records use %s", expected.HashAlgorithm, actual.HashAlgorithm)
+	}
+
+	comparison := &AttestationComparison{Match: true}
+	for _, entry := range expected.Entries {
+		actualDigest, ok := actual.Digest(entry.Name)
+		switch {
+		case !ok:
+			comparison.Mismatches = append(comparison.Mismatches, AttestationMismatch{Name: entry.Name, Reason: MismatchMissing, Expected: entry.Digest})
+		case !strings.EqualFold(actualDigest, entry.Digest):
+			comparison.Mismatches = append(comparison.Mismatches, AttestationMismatch{Name: entry.Name, Reason: MismatchDigest, Expected: entry.Digest, Actual: actualDigest})
+		}
+	}
+	comparison.Match = len(comparison.Mismatches) == 0
+
+	return comparison, nil
+}
+
+// digestHex returns the hex digest of data using the given hash algorithm.
+func digestHex(algorithm, data string) string {
+	var hasher hash.Hash
+	switch algorithm {
+	case HashAlgorithmSha384:
+		hasher = sha512.New384()
+	case HashAlgorithmSha512:
+		hasher = sha512.New()
+	default:
+		hasher = sha256.New()
+	}
+	hasher.Write([]byte(data))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	sampleBaseImageChecksum = "4330056bbbf5d53d48fa167f0f46bf4501b8ee42bd926e1a8b6c25d210cd1baf"
+	sampleExpectedContract  = "env: hyper-protect-basic.env-password.env-data\nworkload: hyper-protect-basic.workload-password.workload-data\nenvWorkloadSignature: signature\n"
+)
+
+// buildAttestationRecordsForTest renders se-checksums.txt content for the sample contract.
+func buildAttestationRecordsForTest(contract string) string {
+	return fmt.Sprintf("24.3.3\nMachine Type/Plant/Serial: 8562/02/4C598\n"+
+		"0bf377eea1136f03cbd5fbb1f2d12bb9a40a3e9f97a8438196b549fdafd0786e root.tar.gz\n"+
+		"%s baseimage\n"+
+		"1b8de43e9b9cecf0a050f238ce10222ac43ac782242e8a88728f872c795a2c9c cidata/meta-data\n"+
+		"%s cidata/user-data\n%s env\n%s workload\n",
+		sampleBaseImageChecksum, gen.GenerateSha256(contract),
+		gen.GenerateSha256("hyper-protect-basic.env-password.env-data"),
+		gen.GenerateSha256("hyper-protect-basic.workload-password.workload-data"))
+}
+
+// Testcase to check if HpcrExpectedAttestationRecords() computes the entries expected for a contract
+func TestHpcrExpectedAttestationRecords(t *testing.T) {
+	expected, err := HpcrExpectedAttestationRecords(sampleExpectedContract, strings.ToUpper(sampleBaseImageChecksum))
+	if err != nil {
+		t.Fatalf("failed to compute expected attestation records - %v", err)
+	}
+
+	assert.Equal(t, HashAlgorithmSha256, expected.HashAlgorithm)
+	assert.Equal(t, []string{EntryBaseImage, EntryCidataUserData, EntryEnv, EntryWorkload}, expected.Names())
+
+	digest, _ := expected.Digest(EntryBaseImage)
+	assert.Equal(t, sampleBaseImageChecksum, digest)
+	digest, _ = expected.Digest(EntryCidataUserData)
+	assert.Equal(t, gen.GenerateSha256(sampleExpectedContract), digest)
+	digest, _ = expected.Digest(EntryEnv)
+	assert.Equal(t, gen.GenerateSha256("hyper-protect-basic.env-password.env-data"), digest)
+
+	expected, err = HpcrExpectedAttestationRecords(sampleExpectedContract, strings.Repeat("a", 128))
+	if err != nil {
+		t.Fatalf("failed to compute expected attestation records - %v", err)
+	}
+
+	assert.Equal(t, HashAlgorithmSha512, expected.HashAlgorithm)
+	digest, _ = expected.Digest(EntryWorkload)
+	assert.Len(t, digest, 128)
+}
+
+// Testcase to check if HpcrExpectedAttestationRecords() handles invalid input
+func TestHpcrExpectedAttestationRecordsInvalid(t *testing.T) {
+	_, err := HpcrExpectedAttestationRecords("", sampleBaseImageChecksum)
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, err = HpcrExpectedAttestationRecords(sampleExpectedContract, "not-a-checksum")
+	assert.EqualError(t, err, "invalid base image checksum - expected a sha256, sha384 or sha512 hex digest")
+
+	_, err = HpcrExpectedAttestationRecords("- a\n- b\n", sampleBaseImageChecksum)
+	assert.EqualError(t, err, "failed to parse contract - contract is not a YAML map")
+
+	_, err = HpcrExpectedAttestationRecords("env:\n  type: env\n", sampleBaseImageChecksum)
+	assert.EqualError(t, err, "failed to parse contract - env section is not a string")
+}
+
+// Testcase to check if HpcrCompareAttestationRecords() matches records produced for the same contract
+func TestHpcrCompareAttestationRecords(t *testing.T) {
+	expected, err := HpcrExpectedAttestationRecords(sampleExpectedContract, sampleBaseImageChecksum)
+	if err != nil {
+		t.Fatalf("failed to compute expected attestation records - %v", err)
+	}
+
+	actual, err := HpcrParseAttestationRecords(buildAttestationRecordsForTest(sampleExpectedContract))
+	if err != nil {
+		t.Fatalf("failed to parse attestation records - %v", err)
+	}
+
+	comparison, err := HpcrCompareAttestationRecords(expected, actual)
+	assert.NoError(t, err)
+	assert.True(t, comparison.Match)
+	assert.Empty(t, comparison.Mismatches)
+}
+
+// Testcase to check if HpcrCompareAttestationRecords() reports changed and missing entries
+func TestHpcrCompareAttestationRecordsMismatch(t *testing.T) {
+	contract := sampleExpectedContract + "attestationPublicKey: hyper-protect-basic.key-password.key-data\n"
+	expected, err := HpcrExpectedAttestationRecords(contract, sampleBaseImageChecksum)
+	if err != nil {
+		t.Fatalf("failed to compute expected attestation records - %v", err)
+	}
+
+	actual, err := HpcrParseAttestationRecords(buildAttestationRecordsForTest(sampleExpectedContract))
+	if err != nil {
+		t.Fatalf("failed to parse attestation records - %v", err)
+	}
+
+	comparison, err := HpcrCompareAttestationRecords(expected, actual)
+	assert.NoError(t, err)
+	assert.False(t, comparison.Match)
+	assert.Equal(t, []AttestationMismatch{
+		{Name: EntryCidataUserData, Reason: MismatchDigest, Expected: gen.GenerateSha256(contract), Actual: gen.GenerateSha256(sampleExpectedContract)},
+		{Name: EntryAttestationPublicKey, Reason: MismatchMissing, Expected: gen.GenerateSha256("hyper-protect-basic.key-password.key-data")},
+	}, comparison.Mismatches)
+}
+
+// Testcase to check if HpcrCompareAttestationRecords() handles invalid input
+func TestHpcrCompareAttestationRecordsInvalid(t *testing.T) {
+	_, err := HpcrCompareAttestationRecords(nil, &AttestationRecords{})
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, err = HpcrCompareAttestationRecords(&AttestationRecords{HashAlgorithm: HashAlgorithmSha512}, &AttestationRecords{HashAlgorithm: HashAlgorithmSha256})
+	assert.EqualError(t, err, "hash algorithm mismatch - expected sha512, attestation records use sha256")
+}
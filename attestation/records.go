@@ -0,0 +1,214 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	// Hash algorithms used for attestation record digests
+	HashAlgorithmSha256 = "sha256"
+	HashAlgorithmSha384 = "sha384"
+	HashAlgorithmSha512 = "sha512"
+
+	// Well-known attestation record entry names
+	EntryRootTarGz            = "root.tar.gz"
+	EntryBaseImage            = "baseimage"
+	EntryCidata               = "/dev/disk/by-label/cidata"
+	EntryCidataMetaData       = "cidata/meta-data"
+	EntryCidataUserData       = "cidata/user-data"
+	EntryCidataVendorData     = "cidata/vendor-data"
+	EntryAttestationPublicKey = "attestationPublicKey"
+	EntryEnv                  = "env"
+	EntryWorkload             = "workload"
+
+	machineHeaderPrefix = "Machine Type/Plant/Serial:"
+)
+
+// hashAlgorithmsByDigestLength maps the hex length of a digest to its hash algorithm.
+var hashAlgorithmsByDigestLength = map[int]string{
+	64:  HashAlgorithmSha256,
+	96:  HashAlgorithmSha384,
+	128: HashAlgorithmSha512,
+}
+
+// AttestationRecords is the parsed content of se-checksums.txt.
+type AttestationRecords struct {
+	// Version of the image that produced the records, e.g. "24.3.3"
+	Version string `json:"version" yaml:"version"`
+	// Machine type, plant and serial number of the host, e.g. "8562/02/4C598"
+	Machine string `json:"machine,omitempty" yaml:"machine,omitempty"`
+	// Hash algorithm of the entry digests (sha256, sha384 or sha512)
+	HashAlgorithm string `json:"hashAlgorithm" yaml:"hashAlgorithm"`
+	// Measured entries in the order they appear in the records
+	Entries []AttestationEntry `json:"entries" yaml:"entries"`
+}
+
+// AttestationEntry is a single measurement from the attestation records.
+type AttestationEntry struct {
+	// Lowercase hex digest of the measured item
+	Digest string `json:"digest" yaml:"digest"`
+	// Name of the measured item, e.g. "baseimage" or "cidata/user-data"
+	Name string `json:"name" yaml:"name"`
+}
+
+// HpcrParseAttestationRecords parses decrypted attestation records into typed records.
+//
+// The records start with header lines (the image version and the machine type/plant/serial)
+// followed by one "<digest> <name>" line per measured item. The hash algorithm is derived from
+// the digest length, and all digests must use the same algorithm.
+//
+// Parameters:
+//   - records: Decrypted attestation records (output from [HpcrGetAttestationRecords], the content of se-checksums.txt)
+//
+// Returns:
+//   - Parsed attestation records
+//   - Error if the records are empty or malformed
+func HpcrParseAttestationRecords(records string) (*AttestationRecords, error) {
+	if gen.CheckIfEmpty(records) {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	parsed := &AttestationRecords{}
+	for number, line := range strings.Split(records, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		entry, ok, err := parseAttestationEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("malformed attestation record on line %d - %v", number+1, err)
+		}
+
+		if !ok {
+			if len(parsed.Entries) > 0 {
+				return nil, fmt.Errorf("malformed attestation record on line %d - expected \"<digest> <name>\"", number+1)
+			}
+			if strings.HasPrefix(line, machineHeaderPrefix) {
+				parsed.Machine = strings.TrimSpace(strings.TrimPrefix(line, machineHeaderPrefix))
+			} else if parsed.Version == "" {
+				parsed.Version = line
+			}
+			continue
+		}
+
+		algorithm := hashAlgorithmsByDigestLength[len(entry.Digest)]
+		if parsed.HashAlgorithm != "" && parsed.HashAlgorithm != algorithm {
+			return nil, fmt.Errorf("malformed attestation record on line %d - %s digest does not match the %s digests of previous entries", number+1, algorithm, parsed.HashAlgorithm)
+		}
+		parsed.HashAlgorithm = algorithm
+		parsed.Entries = append(parsed.Entries, entry)
+	}
+
+	if len(parsed.Entries) == 0 {
+		return nil, fmt.Errorf("attestation records do not contain any entries")
+	}
+
+	return parsed, nil
+}
+
+// HpcrGetParsedAttestationRecords decrypts encrypted attestation records and parses them into typed records.
+// It combines [HpcrGetAttestationRecords] and [HpcrParseAttestationRecords].
+//
+// Parameters:
+//   - data: Encrypted attestation data
+//   - privateKey: RSA private key (PEM format) corresponding to the attestationPublicKey used in the contract
+//   - password: Optional password to unlock the private key if it's encrypted (empty string "" for unencrypted keys)
+//
+// Returns:
+//   - Parsed attestation records
+//   - Error if decryption or parsing fails
+func HpcrGetParsedAttestationRecords(data, privateKey, password string) (*AttestationRecords, error) {
+	records, err := HpcrGetAttestationRecords(data, privateKey, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return HpcrParseAttestationRecords(records)
+}
+
+// Entry returns the first entry with the given name.
+//
+// Parameters:
+//   - name: Entry name, e.g. EntryBaseImage
+//
+// Returns:
+//   - Matching entry
+//   - true if the entry exists
+func (r *AttestationRecords) Entry(name string) (AttestationEntry, bool) {
+	for _, entry := range r.Entries {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+	return AttestationEntry{}, false
+}
+
+// Digest returns the digest of the entry with the given name.
+//
+// Parameters:
+//   - name: Entry name, e.g. EntryCidataUserData
+//
+// Returns:
+//   - Digest of the entry
+//   - true if the entry exists
+func (r *AttestationRecords) Digest(name string) (string, bool) {
+	entry, ok := r.Entry(name)
+	return entry.Digest, ok
+}
+
+// Names returns the entry names in the order they appear in the records.
+//
+// Returns:
+//   - Entry names
+func (r *AttestationRecords) Names() []string {
+	names := make([]string, 0, len(r.Entries))
+	for _, entry := range r.Entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+// parseAttestationEntry parses a "<digest> <name>" line. It reports false for lines that are not
+// entries (header lines) and an error for lines that look like entries but carry an invalid digest.
+func parseAttestationEntry(line string) (AttestationEntry, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || !isHexString(fields[0]) {
+		return AttestationEntry{}, false, nil
+	}
+
+	digest := strings.ToLower(fields[0])
+	if _, ok := hashAlgorithmsByDigestLength[len(digest)]; !ok {
+		return AttestationEntry{}, false, fmt.Errorf("digest of %s has unsupported length %d", fields[1], len(digest))
+	}
+
+	return AttestationEntry{Digest: digest, Name: fields[1]}, true, nil
+}
+
+// isHexString reports whether value is a non-empty, even-length hex string.
+func isHexString(value string) bool {
+	if len(value) < 32 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+// Testcase to check if HpcrGetParsedAttestationRecords() decrypts and parses the sample attestation records
+func TestHpcrGetParsedAttestationRecords(t *testing.T) {
+	encChecksum, err := gen.ReadDataFromFile(encryptedChecksumPath)
+	if err != nil {
+		t.Errorf("failed to get encrypted checksum - %v", err)
+	}
+
+	privateKeyData, err := gen.ReadDataFromFile(privateKeyPath)
+	if err != nil {
+		t.Errorf("failed to get private key - %v", err)
+	}
+
+	records, err := HpcrGetParsedAttestationRecords(encChecksum, privateKeyData, "")
+	if err != nil {
+		t.Fatalf("failed to parse attestation records - %v", err)
+	}
+
+	assert.Equal(t, "24.3.3", records.Version)
+	assert.Equal(t, "8562/02/4C598", records.Machine)
+	assert.Equal(t, HashAlgorithmSha256, records.HashAlgorithm)
+	assert.Equal(t, []string{
+		EntryRootTarGz, EntryBaseImage, EntryCidata, EntryCidataMetaData, EntryCidataUserData,
+		EntryCidataVendorData, EntryAttestationPublicKey, EntryEnv, EntryWorkload,
+	}, records.Names())
+
+	digest, ok := records.Digest(EntryBaseImage)
+	assert.True(t, ok)
+	assert.Equal(t, "4330056bbbf5d53d48fa167f0f46bf4501b8ee42bd926e1a8b6c25d210cd1baf", digest)
+
+	entry, ok := records.Entry(EntryCidataUserData)
+	assert.True(t, ok)
+	assert.Equal(t, "3879908b724ff6c8b49a63dbe4b741453f6bd19533518f4aa2168e739fe3d0ea", entry.Digest)
+
+	_, ok = records.Entry("missing")
+	assert.False(t, ok)
+}
+
+// Testcase to check if HpcrParseAttestationRecords() derives the hash algorithm from the digest length
+func TestHpcrParseAttestationRecordsHashAlgorithm(t *testing.T) {
+	for algorithm, length := range map[string]int{HashAlgorithmSha256: 64, HashAlgorithmSha384: 96, HashAlgorithmSha512: 128} {
+		records, err := HpcrParseAttestationRecords("24.3.3\n" + strings.Repeat("A", length) + " baseimage\n")
+		if err != nil {
+			t.Errorf("failed to parse attestation records - %v", err)
+			continue
+		}
+
+		assert.Equal(t, algorithm, records.HashAlgorithm)
+		assert.Equal(t, strings.Repeat("a", length), records.Entries[0].Digest)
+		assert.Empty(t, records.Machine)
+	}
+}
+
+// Testcase to check if HpcrParseAttestationRecords() rejects malformed records
+func TestHpcrParseAttestationRecordsMalformed(t *testing.T) {
+	_, err := HpcrParseAttestationRecords("")
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, err = HpcrParseAttestationRecords("24.3.3\nMachine Type/Plant/Serial: 8562/02/4C598\n")
+	assert.EqualError(t, err, "attestation records do not contain any entries")
+
+	_, err = HpcrParseAttestationRecords("24.3.3\n" + strings.Repeat("a", 40) + " baseimage\n")
+	assert.EqualError(t, err, "malformed attestation record on line 2 - digest of baseimage has unsupported length 40")
+
+	_, err = HpcrParseAttestationRecords("24.3.3\n" + strings.Repeat("a", 64) + " baseimage\n" + strings.Repeat("b", 96) + " env\n")
+	assert.EqualError(t, err, "malformed attestation record on line 3 - sha384 digest does not match the sha256 digests of previous entries")
+
+	_, err = HpcrParseAttestationRecords("24.3.3\n" + strings.Repeat("a", 64) + " baseimage\ntrailing garbage\n")
+	assert.EqualError(t, err, "malformed attestation record on line 3 - expected \"<digest> <name>\"")
+}
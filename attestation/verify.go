@@ -0,0 +1,281 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	crt "github.com/ibm-hyper-protect/contract-go/v2/common/cert"
+)
+
+const (
+	// Verification steps, in the order they are run
+	StepDecrypt          = "decrypt"
+	StepParse            = "parse"
+	StepCertificateChain = "certificate-chain"
+	StepRevocation       = "revocation"
+	StepSignature        = "signature"
+	StepMeasurements     = "measurements"
+
+	// Step statuses. Only StepPassed counts as success; a step that could not run is a failure.
+	StepPassed = "passed"
+	StepFailed = "failed"
+	StepNotRun = "not-run"
+)
+
+// Evidence is the attestation data received from a deployed instance.
+type Evidence struct {
+	// Encrypted attestation records (se-checksums.txt.enc)
+	EncryptedRecords string
+	// Signature of the decrypted records (se-signature.bin content)
+	Signature string
+	// IBM attestation certificate in PEM format
+	AttestationCert string
+}
+
+// Policy holds the keys, trust anchors and expected values used to verify attestation evidence.
+type Policy struct {
+	// RSA private key (PEM format) corresponding to the attestationPublicKey used in the contract
+	PrivateKey string
+	// Optional password to unlock the private key
+	PrivateKeyPassword string
+	// IBM intermediate certificate that issued the attestation certificate
+	IbmIntermediateCert string
+	// DigiCert intermediate certificate
+	DigicertIntermediateCert string
+	// DigiCert root certificate
+	DigicertRootCert string
+	// Expected measurements, usually from HpcrExpectedAttestationRecords
+	Expected *AttestationRecords
+	// Time the certificate chain and CRLs are validated at. Defaults to the current time when zero.
+	At time.Time
+	// Validate an evidence bundle at its creation time instead of At (HpcrVerifyEvidenceBundle only).
+	// The creation time is chosen by the author of the bundle, so only set it for bundles from
+	// trusted storage.
+	AtBundleCreation bool
+}
+
+// Verdict is the result of verifying attestation evidence.
+type Verdict struct {
+	// True only if every step passed
+	Verified bool `json:"verified" yaml:"verified"`
+	// Time the verification ran, or Policy.At when set
+	VerifiedAt time.Time `json:"verifiedAt" yaml:"verifiedAt"`
+	// Result of each verification step, in the order they were run
+	Steps []StepResult `json:"steps" yaml:"steps"`
+	// Parsed attestation records, nil if decryption or parsing failed
+	Records *AttestationRecords `json:"records,omitempty" yaml:"records,omitempty"`
+	// Comparison with the expected measurements, nil if the comparison did not run
+	Comparison *AttestationComparison `json:"comparison,omitempty" yaml:"comparison,omitempty"`
+}
+
+// StepResult is the result of a single verification step.
+type StepResult struct {
+	// Step name, e.g. StepSignature
+	Step string `json:"step" yaml:"step"`
+	// StepPassed, StepFailed or StepNotRun
+	Status string `json:"status" yaml:"status"`
+	// Details of the result or the reason for the failure
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// Step returns the result of the named step.
+//
+// Parameters:
+//   - name: Step name, e.g. StepRevocation
+//
+// Returns:
+//   - Result of the step
+//   - true if the step is part of the verdict
+func (v *Verdict) Step(name string) (StepResult, bool) {
+	for _, step := range v.Steps {
+		if step.Step == name {
+			return step, true
+		}
+	}
+	return StepResult{}, false
+}
+
+// Verify runs the complete attestation verification pipeline.
+//
+// The steps are: decrypt the records with the policy private key, parse them, validate the attestation
+// certificate chain, check the attestation certificate against the IBM CRL, verify the records signature
+// and compare the records with the expected measurements of the policy. Every step is always reported.
+// A step whose inputs are missing fails, and a step that depends on a failed step is reported as not run,
+// so the verdict is only verified when every step passed.
+//
+// Parameters:
+//   - evidence: Attestation data received from the instance
+//   - policy: Private key, trust anchors and expected measurements
+//
+// Returns:
+//   - Verdict with per-step results
+//   - Error listing the steps that did not pass, nil if the evidence is verified
+func Verify(evidence Evidence, policy Policy) (*Verdict, error) {
+	verdict, _, err := verify(evidence, policy, "", nil)
+	return verdict, err
+}
+
+// verify runs the verification pipeline. When bundledRecords is set, the records come from an evidence
+// bundle and the decrypt step only checks them against the encrypted records if a private key is given.
+// When crls is set, certificate revocation is checked against the snapshot instead of downloaded CRLs.
+// The decrypted records are returned alongside the verdict.
+func verify(evidence Evidence, policy Policy, bundledRecords string, crls crt.CRLSnapshot) (*Verdict, string, error) {
+	verifiedAt := policy.At
+	if verifiedAt.IsZero() {
+		verifiedAt = time.Now()
+	}
+	verdict := &Verdict{VerifiedAt: verifiedAt.UTC()}
+
+	var records string
+	switch {
+	case bundledRecords != "" && policy.PrivateKey == "":
+		records = bundledRecords
+		verdict.pass(StepDecrypt, "decrypted records taken from the evidence bundle")
+	default:
+		if missing := missingInputs(map[string]string{"encryptedRecords": evidence.EncryptedRecords, "privateKey": policy.PrivateKey}); missing != "" {
+			verdict.fail(StepDecrypt, missing)
+		} else if decrypted, err := HpcrGetAttestationRecords(evidence.EncryptedRecords, policy.PrivateKey, policy.PrivateKeyPassword); err != nil {
+			verdict.fail(StepDecrypt, err.Error())
+		} else if bundledRecords != "" && decrypted != bundledRecords {
+			verdict.fail(StepDecrypt, "decrypted records do not match the records in the evidence bundle")
+		} else {
+			records = decrypted
+			verdict.pass(StepDecrypt, "attestation records decrypted")
+		}
+	}
+
+	if records == "" {
+		verdict.notRun(StepParse, StepDecrypt)
+	} else if parsed, err := HpcrParseAttestationRecords(records); err != nil {
+		verdict.fail(StepParse, err.Error())
+	} else {
+		verdict.Records = parsed
+		verdict.pass(StepParse, fmt.Sprintf("%d %s entries parsed", len(parsed.Entries), parsed.HashAlgorithm))
+	}
+
+	if missing := missingInputs(map[string]string{
+		"attestationCert":          evidence.AttestationCert,
+		"ibmIntermediateCert":      policy.IbmIntermediateCert,
+		"digicertIntermediateCert": policy.DigicertIntermediateCert,
+		"digicertRootCert":         policy.DigicertRootCert,
+	}); missing != "" {
+		verdict.fail(StepCertificateChain, missing)
+	} else if _, msg, err := validateAttestationCertificate(evidence, policy, crls); err != nil {
+		verdict.fail(StepCertificateChain, err.Error())
+	} else {
+		verdict.pass(StepCertificateChain, msg)
+	}
+
+	if missing := missingInputs(map[string]string{"attestationCert": evidence.AttestationCert, "ibmIntermediateCert": policy.IbmIntermediateCert}); missing != "" {
+		verdict.fail(StepRevocation, missing)
+	} else if _, msg, err := validateAttestationRevocation(evidence, policy, crls); err != nil {
+		verdict.fail(StepRevocation, err.Error())
+	} else {
+		verdict.pass(StepRevocation, msg)
+	}
+
+	if missing := missingInputs(map[string]string{"signature": evidence.Signature, "attestationCert": evidence.AttestationCert}); missing != "" {
+		verdict.fail(StepSignature, missing)
+	} else if records == "" {
+		verdict.notRun(StepSignature, StepDecrypt)
+	} else if err := HpcrVerifySignatureAttestationRecords(records, evidence.Signature, evidence.AttestationCert); err != nil {
+		verdict.fail(StepSignature, err.Error())
+	} else {
+		verdict.pass(StepSignature, "attestation records signature is valid")
+	}
+
+	if policy.Expected == nil {
+		verdict.fail(StepMeasurements, "required input is missing: expected")
+	} else if verdict.Records == nil {
+		verdict.notRun(StepMeasurements, StepParse)
+	} else if comparison, err := HpcrCompareAttestationRecords(policy.Expected, verdict.Records); err != nil {
+		verdict.fail(StepMeasurements, err.Error())
+	} else {
+		verdict.Comparison = comparison
+		if comparison.Match {
+			verdict.pass(StepMeasurements, fmt.Sprintf("%d expected entries match", len(policy.Expected.Entries)))
+		} else {
+			mismatches := make([]string, 0, len(comparison.Mismatches))
+			for _, mismatch := range comparison.Mismatches {
+				mismatches = append(mismatches, fmt.Sprintf("%s (%s)", mismatch.Name, mismatch.Reason))
+			}
+			verdict.fail(StepMeasurements, "measurements do not match: "+strings.Join(mismatches, ", "))
+		}
+	}
+
+	var failed []string
+	for _, step := range verdict.Steps {
+		if step.Status != StepPassed {
+			failed = append(failed, step.Step)
+		}
+	}
+	verdict.Verified = len(failed) == 0
+	if !verdict.Verified {
+		return verdict, records, fmt.Errorf("attestation verification failed - steps not passed: %s", strings.Join(failed, ", "))
+	}
+
+	return verdict, records, nil
+}
+
+// validateAttestationCertificate validates the attestation certificate chain, offline when a CRL snapshot is given.
+func validateAttestationCertificate(evidence Evidence, policy Policy, crls crt.CRLSnapshot) (bool, string, error) {
+	if crls != nil {
+		return crt.ValidateAttestationCertificateDocumentAt(evidence.AttestationCert, policy.IbmIntermediateCert, policy.DigicertIntermediateCert, policy.DigicertRootCert, crls, policy.At)
+	}
+	return crt.ValidateAttestationCertificateDocumentAt(evidence.AttestationCert, policy.IbmIntermediateCert, policy.DigicertIntermediateCert, policy.DigicertRootCert, nil, policy.At)
+}
+
+// validateAttestationRevocation checks the attestation certificate against the IBM CRL, offline when a CRL snapshot is given.
+func validateAttestationRevocation(evidence Evidence, policy Policy, crls crt.CRLSnapshot) (bool, string, error) {
+	if crls != nil {
+		return crt.ValidateCertificateRevocationListAt(evidence.AttestationCert, policy.IbmIntermediateCert, crls, policy.At)
+	}
+	return crt.ValidateCertificateRevocationListAt(evidence.AttestationCert, policy.IbmIntermediateCert, nil, policy.At)
+}
+
+// pass records a passed step.
+func (v *Verdict) pass(step, message string) {
+	v.Steps = append(v.Steps, StepResult{Step: step, Status: StepPassed, Message: message})
+}
+
+// fail records a failed step.
+func (v *Verdict) fail(step, message string) {
+	v.Steps = append(v.Steps, StepResult{Step: step, Status: StepFailed, Message: message})
+}
+
+// notRun records a step that could not run because the step it depends on did not pass.
+func (v *Verdict) notRun(step, dependency string) {
+	v.Steps = append(v.Steps, StepResult{Step: step, Status: StepNotRun, Message: fmt.Sprintf("%s step did not pass", dependency)})
+}
+
+// missingInputs returns a message naming the empty inputs in sorted order, or "" if all are set.
+func missingInputs(inputs map[string]string) string {
+	var missing []string
+	for name, value := range inputs {
+		if strings.TrimSpace(value) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	sort.Strings(missing)
+	return "required input is missing: " + strings.Join(missing, ", ")
+}
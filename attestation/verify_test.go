@@ -0,0 +1,193 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attestation
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	"github.com/ibm-hyper-protect/contract-go/v2/internal/testpki"
+)
+
+// verifyFixture holds attestation evidence and a matching policy built from a test certificate chain.
+type verifyFixture struct {
+	evidence Evidence
+	policy   Policy
+	crlDir   string
+}
+
+// newVerifyFixture builds a DigiCert root -> DigiCert intermediate -> IBM intermediate -> attestation
+// certificate chain with file based CRLs, and signs the sample attestation records with the attestation key.
+func newVerifyFixture(t *testing.T, revokeAttestation bool) *verifyFixture {
+	t.Helper()
+
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	notBefore, notAfter := now.Add(-2*time.Hour), now.Add(72*time.Hour)
+
+	rootKey := testpki.MustGenerateRSAKey(t)
+	rootTemplate := testpki.CATemplate(t, "Test DigiCert Root", big.NewInt(100), notBefore, notAfter, nil, nil, 2)
+	rootDER := testpki.MustCreateCertificate(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	rootCert := testpki.MustParseCertificate(t, rootDER)
+
+	digicertKey := testpki.MustGenerateRSAKey(t)
+	digicertTemplate := testpki.CATemplate(t, "Test DigiCert Intermediate", big.NewInt(200), notBefore, notAfter,
+		[]string{testpki.FileURL(filepath.Join(dir, "root.crl"))}, rootCert.SubjectKeyId, 1)
+	digicertDER := testpki.MustCreateCertificate(t, digicertTemplate, rootCert, &digicertKey.PublicKey, rootKey)
+	digicertCert := testpki.MustParseCertificate(t, digicertDER)
+
+	ibmKey := testpki.MustGenerateRSAKey(t)
+	ibmTemplate := testpki.CATemplate(t, "Test IBM Intermediate", big.NewInt(300), notBefore, notAfter,
+		[]string{testpki.FileURL(filepath.Join(dir, "digicert.crl"))}, digicertCert.SubjectKeyId, 0)
+	ibmDER := testpki.MustCreateCertificate(t, ibmTemplate, digicertCert, &ibmKey.PublicKey, digicertKey)
+	ibmCert := testpki.MustParseCertificate(t, ibmDER)
+
+	attestationKey := testpki.MustGenerateRSAKey(t)
+	attestationTemplate := testpki.LeafTemplate(t, "Test Attestation Certificate", big.NewInt(500), now, false, false, true,
+		testpki.FileURL(filepath.Join(dir, "ibm.crl")), ibmCert.SubjectKeyId)
+	attestationDER := testpki.MustCreateCertificate(t, attestationTemplate, ibmCert, &attestationKey.PublicKey, ibmKey)
+
+	var revoked []*big.Int
+	if revokeAttestation {
+		revoked = append(revoked, attestationTemplate.SerialNumber)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root.crl"), testpki.MustCreateCRL(t, rootCert, rootKey, nil, now), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "digicert.crl"), testpki.MustCreateCRL(t, digicertCert, digicertKey, nil, now), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ibm.crl"), testpki.MustCreateCRL(t, ibmCert, ibmKey, revoked, now), 0600))
+
+	encryptedRecords, err := gen.ReadDataFromFile(encryptedChecksumPath)
+	require.NoError(t, err)
+	privateKey, err := gen.ReadDataFromFile(privateKeyPath)
+	require.NoError(t, err)
+	records, err := HpcrGetAttestationRecords(encryptedRecords, privateKey, "")
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte(records))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, attestationKey, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	parsed, err := HpcrParseAttestationRecords(records)
+	require.NoError(t, err)
+	baseImage, _ := parsed.Entry(EntryBaseImage)
+	userData, _ := parsed.Entry(EntryCidataUserData)
+
+	return &verifyFixture{
+		evidence: Evidence{
+			EncryptedRecords: encryptedRecords,
+			Signature:        string(signature),
+			AttestationCert:  testpki.PEMEncodeCert(attestationDER),
+		},
+		policy: Policy{
+			PrivateKey:               privateKey,
+			IbmIntermediateCert:      testpki.PEMEncodeCert(ibmDER),
+			DigicertIntermediateCert: testpki.PEMEncodeCert(digicertDER),
+			DigicertRootCert:         testpki.PEMEncodeCert(rootDER),
+			Expected:                 &AttestationRecords{HashAlgorithm: HashAlgorithmSha256, Entries: []AttestationEntry{baseImage, userData}},
+		},
+		crlDir: dir,
+	}
+}
+
+// stepStatuses returns the status of every step of a verdict keyed by step name.
+func stepStatuses(verdict *Verdict) map[string]string {
+	statuses := map[string]string{}
+	for _, step := range verdict.Steps {
+		statuses[step.Step] = step.Status
+	}
+	return statuses
+}
+
+// Testcase to check if Verify() passes every step for valid evidence
+func TestVerify(t *testing.T) {
+	fixture := newVerifyFixture(t, false)
+
+	verdict, err := Verify(fixture.evidence, fixture.policy)
+	require.NoError(t, err)
+
+	assert.True(t, verdict.Verified)
+	assert.Equal(t, []string{StepDecrypt, StepParse, StepCertificateChain, StepRevocation, StepSignature, StepMeasurements}, func() []string {
+		names := []string{}
+		for _, step := range verdict.Steps {
+			names = append(names, step.Step)
+			assert.Equal(t, StepPassed, step.Status, "step=%s message=%s", step.Step, step.Message)
+		}
+		return names
+	}())
+	assert.Equal(t, "24.3.3", verdict.Records.Version)
+	assert.True(t, verdict.Comparison.Match)
+}
+
+// Testcase to check if Verify() fails steps whose inputs are missing instead of skipping them
+func TestVerifyMissingInputs(t *testing.T) {
+	fixture := newVerifyFixture(t, false)
+	policy := fixture.policy
+	policy.DigicertRootCert = ""
+	policy.Expected = nil
+
+	verdict, err := Verify(Evidence{EncryptedRecords: fixture.evidence.EncryptedRecords, AttestationCert: fixture.evidence.AttestationCert}, policy)
+	assert.EqualError(t, err, "attestation verification failed - steps not passed: certificate-chain, signature, measurements")
+	assert.False(t, verdict.Verified)
+
+	step, ok := verdict.Step(StepCertificateChain)
+	assert.True(t, ok)
+	assert.Equal(t, "required input is missing: digicertRootCert", step.Message)
+	step, _ = verdict.Step(StepSignature)
+	assert.Equal(t, "required input is missing: signature", step.Message)
+	step, _ = verdict.Step(StepMeasurements)
+	assert.Equal(t, "required input is missing: expected", step.Message)
+
+	verdict, err = Verify(Evidence{}, Policy{})
+	assert.Error(t, err)
+	assert.Equal(t, map[string]string{
+		StepDecrypt: StepFailed, StepParse: StepNotRun, StepCertificateChain: StepFailed,
+		StepRevocation: StepFailed, StepSignature: StepFailed, StepMeasurements: StepFailed,
+	}, stepStatuses(verdict))
+}
+
+// Testcase to check if Verify() reports revoked certificates, bad signatures and unexpected measurements
+func TestVerifyFailures(t *testing.T) {
+	fixture := newVerifyFixture(t, true)
+	fixture.evidence.Signature = "invalid signature"
+	fixture.policy.Expected.Entries[0].Digest = fixture.policy.Expected.Entries[1].Digest
+
+	verdict, err := Verify(fixture.evidence, fixture.policy)
+	assert.EqualError(t, err, "attestation verification failed - steps not passed: revocation, signature, measurements")
+	assert.Equal(t, map[string]string{
+		StepDecrypt: StepPassed, StepParse: StepPassed, StepCertificateChain: StepPassed,
+		StepRevocation: StepFailed, StepSignature: StepFailed, StepMeasurements: StepFailed,
+	}, stepStatuses(verdict))
+
+	step, _ := verdict.Step(StepMeasurements)
+	assert.Equal(t, "measurements do not match: baseimage (digest-mismatch)", step.Message)
+	assert.False(t, verdict.Comparison.Match)
+
+	fixture.policy.PrivateKey = "invalid-private-key"
+	verdict, _ = Verify(fixture.evidence, fixture.policy)
+	assert.Equal(t, StepFailed, stepStatuses(verdict)[StepDecrypt])
+	assert.Equal(t, StepNotRun, stepStatuses(verdict)[StepSignature])
+	assert.Equal(t, StepNotRun, stepStatuses(verdict)[StepMeasurements])
+}
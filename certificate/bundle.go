@@ -0,0 +1,244 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"time"
+
+	enc "github.com/ibm-hyper-protect/contract-go/v2/common/encrypt"
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
+)
+
+const (
+	// Version of the certificate bundle format
+	CertificateBundleVersion = 1
+)
+
+// CertificateBundle is a set of encryption certificates for several platforms and versions,
+// serialized as JSON. The bundle is signed with a detached signature over its exact bytes.
+type CertificateBundle struct {
+	// Bundle format version, CertificateBundleVersion
+	Version int `json:"version"`
+	// Time the bundle was created
+	CreatedAt time.Time `json:"createdAt"`
+	// Encryption certificates of the bundle
+	Certificates []CertificateBundleEntry `json:"certificates"`
+}
+
+// CertificateBundleEntry is one encryption certificate of a certificate bundle.
+type CertificateBundleEntry struct {
+	// Platform the certificate belongs to ("ccrt", "ccrv", "ccco" or "hpvs")
+	Platform string `json:"platform"`
+	// Certificate version (e.g., "26.2.0")
+	Version string `json:"version"`
+	// Expiry of the certificate; set from the certificate when the bundle is created
+	Expiry time.Time `json:"expiry"`
+	// PEM-formatted encryption certificate
+	Cert string `json:"cert"`
+}
+
+// HpcrCreateCertificateBundle creates a signed bundle of encryption certificates.
+//
+// Use this function to distribute encryption certificates of new images to many machines without a
+// library upgrade. The bundle is loaded with [HpcrLoadCertificateBundle] using the public key of
+// privateKey. The expiry of every entry is taken from its certificate.
+//
+// Parameters:
+//   - certificates: Certificates to bundle; Platform, Version and Cert are required
+//   - privateKey: RSA private key (PEM format) used to sign the bundle
+//   - password: Optional password to unlock the encrypted private key (empty string "" for unencrypted keys)
+//
+// Returns:
+//   - Certificate bundle JSON
+//   - Base64-encoded detached SHA-256 signature of the bundle
+//   - Error if an entry is invalid or duplicated, or signing fails
+func HpcrCreateCertificateBundle(certificates []CertificateBundleEntry, privateKey, password string) (string, string, error) {
+	if len(certificates) == 0 || gen.CheckIfEmpty(privateKey) {
+		return "", "", fmt.Errorf(missingParameterErrStatement)
+	}
+
+	bundle := CertificateBundle{
+		Version:      CertificateBundleVersion,
+		CreatedAt:    time.Now().UTC(),
+		Certificates: make([]CertificateBundleEntry, 0, len(certificates)),
+	}
+
+	for _, entry := range certificates {
+		certificate, err := checkBundleEntry(entry, bundle.Certificates)
+		if err != nil {
+			return "", "", err
+		}
+		entry.Expiry = certificate.NotAfter.UTC()
+		bundle.Certificates = append(bundle.Certificates, entry)
+	}
+
+	bundleJson, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode certificate bundle - %v", err)
+	}
+
+	signature, err := enc.SignData(string(bundleJson), privateKey, password)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign certificate bundle - %v", err)
+	}
+
+	return string(bundleJson), signature, nil
+}
+
+// HpcrLoadCertificateBundle verifies a signed certificate bundle and adds its certificates to a
+// certificate store.
+//
+// The signature is checked first. A bundle created before the newest bundle recorded by the store is
+// rejected, so an old bundle cannot be replayed. A [cert.MemoryCertStore] records bundles in memory
+// only; to refuse old bundles after a restart, record the persisted [cert.MemoryCertStore.LastBundle]
+// on startup or pass a store whose RecordBundle persists the time. Every certificate must match the identity
+// of its platform and is verified against the IBM certificate chain as with [WithChainVerification],
+// using the default [TrustChain] unless one is given. Certificates are only added when the signature
+// and all certificates are valid, and all at once with the store's AddAll, so a bundle is never loaded
+// partially. The bundle is recorded after its certificates are added.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of intermediate and CRL downloads
+//   - bundle: Certificate bundle JSON (output from [HpcrCreateCertificateBundle])
+//   - signature: Base64-encoded detached signature of the bundle
+//   - publicKey: RSA public key (PEM format) of the key the bundle was signed with
+//   - store: Certificate store to add the certificates to, implementing [cert.WritableCertStore] and
+//     [cert.BundleRecorder] - uses encryption.DefaultCertStore() if nil
+//   - opts: Download options, e.g. [WithChainVerification], [WithRevocationCheck] or [WithHTTPClient]
+//
+// Returns:
+//   - Entries of the bundle that were added
+//   - Error if the store cannot add certificates or record bundles, the signature is invalid, the bundle
+//     is older than a loaded bundle, an entry is invalid or a certificate fails verification
+func HpcrLoadCertificateBundle(ctx context.Context, bundle, signature, publicKey string, store cert.CertStore, opts ...DownloadOption) ([]CertificateBundleEntry, error) {
+	if gen.CheckIfEmpty(bundle, signature, publicKey) {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	decodedSignature, err := gen.DecodeBase64String(signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode certificate bundle signature - %v", err)
+	}
+
+	if err := enc.VerifySignature(bundle, decodedSignature, publicKey); err != nil {
+		return nil, fmt.Errorf("certificate bundle signature verification failed - %v", err)
+	}
+
+	var parsed CertificateBundle
+	if err := json.Unmarshal([]byte(bundle), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse certificate bundle - %v", err)
+	}
+
+	if parsed.Version != CertificateBundleVersion {
+		return nil, fmt.Errorf("unsupported certificate bundle version %d", parsed.Version)
+	}
+
+	if len(parsed.Certificates) == 0 {
+		return nil, fmt.Errorf("certificate bundle does not contain certificates")
+	}
+
+	if parsed.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("certificate bundle does not have a creation time")
+	}
+
+	if store == nil {
+		store = cert.DefaultCertStore()
+	}
+	writable, ok := store.(cert.WritableCertStore)
+	if !ok {
+		return nil, fmt.Errorf("certificate store %T does not support adding certificates", store)
+	}
+	recorder, ok := store.(cert.BundleRecorder)
+	if !ok {
+		return nil, fmt.Errorf("certificate store %T does not record loaded bundles", store)
+	}
+	if err := recorder.CheckBundle(parsed.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	config := newDownloadConfig(opts)
+	if config.trustChain == nil {
+		config.trustChain = &TrustChain{}
+	}
+	verifier := newChainVerifier(ctx, config)
+
+	for i, entry := range parsed.Certificates {
+		certificate, err := checkBundleEntry(entry, parsed.Certificates[:i])
+		if err != nil {
+			return nil, err
+		}
+		if !entry.Expiry.Equal(certificate.NotAfter) {
+			return nil, fmt.Errorf("expiry of certificate %s %s does not match the certificate", entry.Platform, entry.Version)
+		}
+		if err := verifier.verify(entry.Cert); err != nil {
+			return nil, fmt.Errorf("certificate %s %s verification failed - %v", entry.Platform, entry.Version, err)
+		}
+	}
+
+	entries := make([]cert.CertEntry, 0, len(parsed.Certificates))
+	for _, entry := range parsed.Certificates {
+		entries = append(entries, cert.CertEntry{OsType: entry.Platform, Version: entry.Version, Certificate: entry.Cert})
+	}
+	if err := writable.AddAll(entries); err != nil {
+		return nil, fmt.Errorf("failed to add certificates - %v", err)
+	}
+	if err := recorder.RecordBundle(parsed.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	return parsed.Certificates, nil
+}
+
+// checkBundleEntry checks the fields of a bundle entry, that it is not a duplicate of a previous
+// entry and that its certificate matches the identity of its platform, and returns the parsed certificate.
+func checkBundleEntry(entry CertificateBundleEntry, previous []CertificateBundleEntry) (*x509.Certificate, error) {
+	if gen.CheckIfEmpty(entry.Platform, entry.Version, entry.Cert) {
+		return nil, fmt.Errorf("certificate bundle entry is missing platform, version or certificate")
+	}
+
+	if !slices.Contains(cert.OsTypes(), entry.Platform) {
+		return nil, fmt.Errorf("invalid platform %s of certificate %s", entry.Platform, entry.Version)
+	}
+
+	if slices.ContainsFunc(previous, func(p CertificateBundleEntry) bool {
+		return p.Platform == entry.Platform && p.Version == entry.Version
+	}) {
+		return nil, fmt.Errorf("duplicate certificate %s %s", entry.Platform, entry.Version)
+	}
+
+	block, _ := pem.Decode([]byte(entry.Cert))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("failed to parse certificate %s %s - no PEM encoded certificate found", entry.Platform, entry.Version)
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate %s %s - %v", entry.Platform, entry.Version, err)
+	}
+
+	if err := cert.CheckCertificateIdentity(entry.Platform, entry.Cert); err != nil {
+		return nil, fmt.Errorf("certificate %s %s does not match platform %s - %v", entry.Platform, entry.Version, entry.Platform, err)
+	}
+
+	return certificate, nil
+}
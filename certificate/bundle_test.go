@@ -0,0 +1,265 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	enc "github.com/ibm-hyper-protect/contract-go/v2/common/encrypt"
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sampleBundlePrivateKeyPath = "../samples/contract-expiry/private.pem"
+)
+
+// bundleKeys returns the sample private key and its public key for signing bundles.
+func bundleKeys(t *testing.T) (string, string) {
+	t.Helper()
+
+	privateKey, err := gen.ReadDataFromFile(sampleBundlePrivateKeyPath)
+	require.NoError(t, err)
+	publicKey, err := enc.GeneratePublicKey(privateKey, "")
+	require.NoError(t, err)
+
+	return privateKey, publicKey
+}
+
+// Testcase to check if a bundle created by HpcrCreateCertificateBundle() is verified and loaded by HpcrLoadCertificateBundle()
+func TestHpcrCertificateBundle(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+
+	bundle, signature, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{
+		{Platform: "ccrt", Version: "99.0.1", Cert: fixture.certs["1"]},
+		{Platform: "hpvs", Version: "99.0.1", Cert: fixture.certs["1"]},
+	}, privateKey, "")
+	require.NoError(t, err)
+	assert.Contains(t, bundle, `"expiry"`)
+
+	store := cert.NewCertStore()
+	chain := WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert})
+	entries, err := HpcrLoadCertificateBundle(context.Background(), bundle, signature, publicKey, store, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	certificate, ok := store.Certificate("ccrt", "99.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, fixture.certs["1"], certificate)
+	assert.Equal(t, []string{"99.0.1"}, store.Versions("hpvs"))
+
+	_, err = HpcrLoadCertificateBundle(context.Background(), strings.Replace(bundle, "99.0.1", "99.0.2", 1), signature, publicKey, store,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	assert.ErrorContains(t, err, "certificate bundle signature verification failed")
+}
+
+// Testcase to check if HpcrLoadCertificateBundle() adds no certificate when one certificate fails chain verification
+func TestHpcrLoadCertificateBundleUntrustedCertificate(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+
+	bundle, signature, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{
+		{Platform: "ccrt", Version: "99.0.1", Cert: fixture.certs["1"]},
+		{Platform: "ccrt", Version: "99.0.3", Cert: fixture.certs["3"]},
+	}, privateKey, "")
+	require.NoError(t, err)
+
+	store := cert.NewCertStore()
+	_, err = HpcrLoadCertificateBundle(context.Background(), bundle, signature, publicKey, store,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert}))
+	assert.ErrorContains(t, err, "certificate ccrt 99.0.3 verification failed")
+	assert.Empty(t, store.Versions("ccrt"))
+
+	bundle, signature, err = HpcrCreateCertificateBundle([]CertificateBundleEntry{
+		{Platform: "ccrt", Version: "99.0.2", Cert: fixture.certs["2"]},
+	}, privateKey, "")
+	require.NoError(t, err)
+
+	_, err = HpcrLoadCertificateBundle(context.Background(), bundle, signature, publicKey, store,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert}), WithRevocationCheck())
+	assert.ErrorContains(t, err, "certificate ccrt 99.0.2 verification failed")
+	assert.Empty(t, store.Versions("ccrt"))
+}
+
+// Testcase to check if HpcrCreateCertificateBundle() and HpcrLoadCertificateBundle() reject invalid entries
+func TestHpcrCertificateBundleInvalid(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+
+	_, _, err := HpcrCreateCertificateBundle(nil, privateKey, "")
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, _, err = HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "zos", Version: "1.0.0", Cert: fixture.certs["1"]}}, privateKey, "")
+	assert.EqualError(t, err, "invalid platform zos of certificate 1.0.0")
+
+	_, _, err = HpcrCreateCertificateBundle([]CertificateBundleEntry{
+		{Platform: "ccrt", Version: "1.0.0", Cert: fixture.certs["1"]},
+		{Platform: "ccrt", Version: "1.0.0", Cert: fixture.certs["1"]},
+	}, privateKey, "")
+	assert.EqualError(t, err, "duplicate certificate ccrt 1.0.0")
+
+	_, _, err = HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccrt", Version: "1.0.0", Cert: "invalid"}}, privateKey, "")
+	assert.EqualError(t, err, "failed to parse certificate ccrt 1.0.0 - no PEM encoded certificate found")
+
+	_, _, err = HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccco", Version: "1.0.0", Cert: fixture.certs["1"]}}, privateKey, "")
+	assert.ErrorContains(t, err, "certificate ccco 1.0.0 does not match platform ccco - subject common name")
+
+	// A bundle whose expiry metadata does not match its certificate is rejected even if it is signed
+	bundle, _, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccrt", Version: "1.0.0", Cert: fixture.certs["1"]}}, privateKey, "")
+	require.NoError(t, err)
+	var parsed CertificateBundle
+	require.NoError(t, json.Unmarshal([]byte(bundle), &parsed))
+	parsed.Certificates[0].Expiry = parsed.Certificates[0].Expiry.AddDate(1, 0, 0)
+	tampered, err := json.Marshal(parsed)
+	require.NoError(t, err)
+	signature, err := enc.SignData(string(tampered), privateKey, "")
+	require.NoError(t, err)
+
+	_, err = HpcrLoadCertificateBundle(context.Background(), string(tampered), signature, publicKey, cert.NewCertStore(), WithHTTPClient(fixture.server.Client()), WithIssuerDownload())
+	assert.EqualError(t, err, "expiry of certificate ccrt 1.0.0 does not match the certificate")
+
+	_, err = HpcrLoadCertificateBundle(context.Background(), "", signature, publicKey, nil)
+	assert.EqualError(t, err, missingParameterErrStatement)
+}
+
+// Testcase to check if HpcrLoadCertificateBundle() rejects a bundle older than the newest bundle loaded into the store
+func TestHpcrLoadCertificateBundleReplay(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+	chain := WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert})
+
+	sign := func(version string, createdAt time.Time) (string, string) {
+		bundle, _, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccrt", Version: version, Cert: fixture.certs["1"]}}, privateKey, "")
+		require.NoError(t, err)
+		var parsed CertificateBundle
+		require.NoError(t, json.Unmarshal([]byte(bundle), &parsed))
+		parsed.CreatedAt = createdAt
+		data, err := json.Marshal(parsed)
+		require.NoError(t, err)
+		signature, err := enc.SignData(string(data), privateKey, "")
+		require.NoError(t, err)
+		return string(data), signature
+	}
+
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	oldBundle, oldSignature := sign("99.0.1", createdAt.Add(-time.Hour))
+	newBundle, newSignature := sign("99.0.2", createdAt)
+
+	store := cert.NewCertStore()
+	_, err := HpcrLoadCertificateBundle(context.Background(), newBundle, newSignature, publicKey, store, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	require.NoError(t, err)
+
+	_, err = HpcrLoadCertificateBundle(context.Background(), oldBundle, oldSignature, publicKey, store, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	assert.ErrorContains(t, err, "is older than the bundle created at")
+	assert.Equal(t, []string{"99.0.2"}, store.Versions("ccrt"))
+
+	// Loading the same bundle again is allowed
+	_, err = HpcrLoadCertificateBundle(context.Background(), newBundle, newSignature, publicKey, store, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	assert.NoError(t, err)
+
+	undated, undatedSignature := sign("99.0.3", time.Time{})
+	_, err = HpcrLoadCertificateBundle(context.Background(), undated, undatedSignature, publicKey, store, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	assert.EqualError(t, err, "certificate bundle does not have a creation time")
+}
+
+// Testcase to check if HpcrLoadCertificateBundle() rejects a replayed bundle after a restart when the last bundle time is persisted
+func TestHpcrLoadCertificateBundleReplayAfterRestart(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+	chain := WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert})
+
+	oldBundle, oldSignature, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccrt", Version: "99.0.1", Cert: fixture.certs["1"]}}, privateKey, "")
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	newBundle, newSignature, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccrt", Version: "99.0.2", Cert: fixture.certs["1"]}}, privateKey, "")
+	require.NoError(t, err)
+
+	store := cert.NewCertStore()
+	_, err = HpcrLoadCertificateBundle(context.Background(), newBundle, newSignature, publicKey, store, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	require.NoError(t, err)
+	persisted := store.LastBundle()
+	assert.False(t, persisted.IsZero())
+
+	restarted := cert.NewCertStore()
+	require.NoError(t, restarted.RecordBundle(persisted))
+	_, err = HpcrLoadCertificateBundle(context.Background(), oldBundle, oldSignature, publicKey, restarted, WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain)
+	assert.ErrorContains(t, err, "is older than the bundle created at")
+	assert.Empty(t, restarted.Versions("ccrt"))
+}
+
+// readOnlyCertStore is a CertStore that certificates cannot be added to.
+type readOnlyCertStore struct{}
+
+func (readOnlyCertStore) Certificate(osType, version string) (string, bool) { return "", false }
+func (readOnlyCertStore) Versions(osType string) []string                   { return nil }
+
+// Testcase to check if HpcrLoadCertificateBundle() rejects stores that cannot add certificates
+func TestHpcrLoadCertificateBundleReadOnlyStore(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+
+	bundle, signature, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{{Platform: "ccrt", Version: "99.0.1", Cert: fixture.certs["1"]}}, privateKey, "")
+	require.NoError(t, err)
+
+	_, err = HpcrLoadCertificateBundle(context.Background(), bundle, signature, publicKey, readOnlyCertStore{}, WithHTTPClient(fixture.server.Client()), WithIssuerDownload())
+	assert.EqualError(t, err, "certificate store certificate.readOnlyCertStore does not support adding certificates")
+}
+
+// failingCertStore is a certificate store whose Add fails on the second entry of a batch.
+type failingCertStore struct {
+	*cert.MemoryCertStore
+}
+
+func (s failingCertStore) AddAll(entries []cert.CertEntry) error {
+	if len(entries) > 1 {
+		return fmt.Errorf("failed to add certificate %s %s", entries[1].OsType, entries[1].Version)
+	}
+	return s.MemoryCertStore.AddAll(entries)
+}
+
+// Testcase to check if HpcrLoadCertificateBundle() does not record a bundle whose certificates cannot be added
+func TestHpcrLoadCertificateBundleAddFails(t *testing.T) {
+	fixture := newChainFixture(t)
+	privateKey, publicKey := bundleKeys(t)
+
+	bundle, signature, err := HpcrCreateCertificateBundle([]CertificateBundleEntry{
+		{Platform: "ccrt", Version: "99.0.1", Cert: fixture.certs["1"]},
+		{Platform: "hpvs", Version: "99.0.1", Cert: fixture.certs["1"]},
+	}, privateKey, "")
+	require.NoError(t, err)
+
+	store := failingCertStore{MemoryCertStore: cert.NewCertStore()}
+	opts := []DownloadOption{WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert})}
+	_, err = HpcrLoadCertificateBundle(context.Background(), bundle, signature, publicKey, store, opts...)
+	assert.EqualError(t, err, "failed to add certificates - failed to add certificate hpvs 99.0.1")
+	assert.Empty(t, store.Versions("ccrt"))
+	assert.True(t, store.LastBundle().IsZero())
+
+	// The bundle can be loaded in full once the store accepts it
+	_, err = HpcrLoadCertificateBundle(context.Background(), bundle, signature, publicKey, store.MemoryCertStore, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"99.0.1"}, store.Versions("ccrt"))
+	assert.Equal(t, []string{"99.0.1"}, store.Versions("hpvs"))
+	assert.False(t, store.LastBundle().IsZero())
+}
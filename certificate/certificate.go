@@ -16,11 +16,12 @@
 package certificate
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
+	"slices"
 	"strings"
-	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -43,6 +44,72 @@ type CertSpec struct {
 	Patch string
 }
 
+// ValidationOption configures certificate document and CRL validation.
+type ValidationOption func(*validationConfig)
+
+// validationConfig holds the settings applied by ValidationOption values.
+type validationConfig struct {
+	crlStore crt.CRLStore
+	at       time.Time
+}
+
+// WithCRLStore takes the CRLs used for revocation checks from a CRL store instead of downloading
+// them on every validation. Use [HpcrNewCRLStore] to create a directory backed store.
+func WithCRLStore(store crt.CRLStore) ValidationOption {
+	return func(c *validationConfig) {
+		c.crlStore = store
+	}
+}
+
+// WithCRLDownload downloads the CRLs used for revocation checks with the given context and download
+// options. It replaces any CRL store set with [WithCRLStore]; to combine a directory store with
+// download options pass them to [HpcrNewCRLStore] instead.
+func WithCRLDownload(ctx context.Context, opts ...DownloadOption) ValidationOption {
+	return func(c *validationConfig) {
+		c.crlStore = crt.CRLDownloader{Context: ctx, Config: newDownloadConfig(opts).http}
+	}
+}
+
+// WithValidationTime checks validity periods of certificates and CRLs at the given time instead of
+// the current time, e.g. to check whether a certificate will still be valid at a planned deployment.
+func WithValidationTime(at time.Time) ValidationOption {
+	return func(c *validationConfig) {
+		c.at = at
+	}
+}
+
+// newValidationConfig applies validation options.
+func newValidationConfig(opts []ValidationOption) validationConfig {
+	var config validationConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// HpcrNewCRLStore creates a CRL store that caches CRLs and their metadata in a directory.
+//
+// Cached CRLs are reused until their nextUpdate time. In online mode missing or stale CRLs are
+// downloaded and saved. In offline mode nothing is downloaded and validation fails only when the
+// cached CRL is missing or stale; CRLs can be added by hand with the store's Import method.
+//
+// Parameters:
+//   - dir: Directory holding the cached CRLs (created if needed)
+//   - offline: true to never download CRLs
+//   - opts: Download options used to refresh CRLs in online mode
+//
+// Returns:
+//   - Directory backed CRL store, to be passed to validation functions with [WithCRLStore]
+//   - Error if the directory cannot be created
+func HpcrNewCRLStore(dir string, offline bool, opts ...DownloadOption) (*crt.DirectoryCRLStore, error) {
+	store, err := crt.NewDirectoryCRLStore(dir, offline)
+	if err != nil {
+		return nil, err
+	}
+	store.SetDownloader(crt.CRLDownloader{Config: newDownloadConfig(opts).http})
+	return store, nil
+}
+
 // HpcrGetEncryptionCertificateFromJson extracts a specific version's encryption certificate
 // from the output of [HpcrDownloadEncryptionCertificates].
 //
@@ -93,79 +160,7 @@ func HpcrGetEncryptionCertificateFromJson(encryptionCertificateJson, version str
 //     and expiry_date fields
 //   - Error if download fails, version format is invalid, or certificate not found
 func HpcrDownloadEncryptionCertificates(versionList []string, formatType, certDownloadUrlTemplate string) (string, error) {
-	if certDownloadUrlTemplate == "" {
-		certDownloadUrlTemplate = defaultEncCertUrlTemplate
-	}
-
-	if formatType == "" {
-		formatType = defaultFormat
-	}
-
-	if len(versionList) == 0 {
-		return "", fmt.Errorf(missingParameterErrStatement)
-	}
-
-	var vertCertMapVersion = make(map[string]map[string]string)
-
-	for _, version := range versionList {
-		verSpec := strings.Split(version, ".")
-		if !strings.Contains(version, ".") || len(verSpec) != 3 {
-			return "", fmt.Errorf("invalid version format: '%s'. Expected comma-separated versions, e.g., 1.0.21 or 1.0.21,1.0.22", version)
-		}
-		urlTemplate := template.New("url")
-		urlTemplate, err := urlTemplate.Parse(certDownloadUrlTemplate)
-		if err != nil {
-			return "", fmt.Errorf("failed to create url template - %v", err)
-		}
-		builder := &strings.Builder{}
-		err = urlTemplate.Execute(builder, CertSpec{verSpec[0], verSpec[1], verSpec[2]})
-		if err != nil {
-			return "", fmt.Errorf("failed to apply template - %v", err)
-		}
-
-		url := builder.String()
-		status, err := gen.CheckUrlExists(url)
-		if err != nil {
-			return "", fmt.Errorf("failed to check if URL exists - %v", err)
-		}
-		if !status {
-			return "", fmt.Errorf("encryption certificate doesn't exist in %s", url)
-		}
-
-		cert, err := gen.CertificateDownloader(url)
-		if err != nil {
-			return "", fmt.Errorf("failed to download encryption certificate - %v", err)
-		}
-
-		cert_status, daysLeft, certificateExpiryDate, err := gen.CheckEncryptionCertValidity(cert)
-		if err != nil {
-			return "", err
-		}
-		var verCertMap = make(map[string]string)
-		verCertMap["cert"] = cert
-		verCertMap["status"] = cert_status
-		verCertMap["expiry_days"] = strconv.Itoa(daysLeft)
-		verCertMap["expiry_date"] = certificateExpiryDate
-		vertCertMapVersion[version] = verCertMap
-	}
-
-	switch formatType {
-	case formatJson:
-		jsonBytes, err := json.Marshal(vertCertMapVersion)
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal JSON - %v", err)
-		}
-
-		return string(jsonBytes), nil
-	case formatYaml:
-		yamlBytes, err := yaml.Marshal(vertCertMapVersion)
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal YAML - %v", err)
-		}
-		return string(yamlBytes), nil
-	default:
-		return "", fmt.Errorf("invalid output format")
-	}
+	return HpcrDownloadEncryptionCertificatesContext(context.Background(), versionList, formatType, certDownloadUrlTemplate)
 }
 
 // HpcrValidateEncryptionCertificate validates an IBM encryption certificate and returns its expiry status.
@@ -176,12 +171,14 @@ func HpcrDownloadEncryptionCertificates(versionList []string, formatType, certDo
 //
 // Parameters:
 //   - encryptionCert: PEM-formatted IBM encryption certificate to validate
+//   - opts: Optional settings; [WithValidationTime] checks the certificate at the given time
 //
 // Returns:
 //   - Validation message indicating the certificate status (valid with days remaining, or expired)
 //   - Error if the certificate is invalid, corrupted, or has expired
-func HpcrValidateEncryptionCertificate(encryptionCert string) (string, error) {
-	msg, err := gen.CheckEncryptionCertValidityForContractEncryption(encryptionCert)
+func HpcrValidateEncryptionCertificate(encryptionCert string, opts ...ValidationOption) (string, error) {
+	config := newValidationConfig(opts)
+	msg, err := gen.CheckEncryptionCertValidityForContractEncryptionAt(encryptionCert, config.at)
 	if err != nil {
 		return "", err
 	}
@@ -196,17 +193,19 @@ func HpcrValidateEncryptionCertificate(encryptionCert string) (string, error) {
 //   - ibmIntermediateCert: IBM intermediate certificate content
 //   - digicertIntermediateCert: DigiCert intermediate certificate content
 //   - digicertRootCert: DigiCert root certificate content
+//   - opts: Optional settings, e.g. [WithCRLStore] or [WithValidationTime]
 //
 // Returns:
 //   - valid: true if the certificate document is valid
 //   - message: Detailed validation message
 //   - error: Error with stage information if validation fails
-func HpcrVerifyEncryptionCertificateDocument(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string) (bool, string, error) {
+func HpcrVerifyEncryptionCertificateDocument(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, opts ...ValidationOption) (bool, string, error) {
 	if gen.CheckIfEmpty(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
 		return false, "", fmt.Errorf(missingParameterErrStatement)
 	}
 
-	valid, msg, err := crt.ValidateEncryptionCertificateDocument(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert)
+	config := newValidationConfig(opts)
+	valid, msg, err := crt.ValidateEncryptionCertificateDocumentAt(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, config.crlStore, config.at)
 	if err != nil {
 		return false, "", err
 	}
@@ -222,17 +221,19 @@ func HpcrVerifyEncryptionCertificateDocument(encryptionCert, ibmIntermediateCert
 //   - ibmIntermediateCert: IBM intermediate certificate content
 //   - digicertIntermediateCert: DigiCert intermediate certificate content
 //   - digicertRootCert: DigiCert root certificate content
+//   - opts: Optional settings, e.g. [WithCRLStore] or [WithValidationTime]
 //
 // Returns:
 //   - valid: true if the certificate document is valid
 //   - message: Detailed validation message
 //   - error: Error with stage information if validation fails
-func HpcrVerifyAttestationCertificateDocument(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string) (bool, string, error) {
+func HpcrVerifyAttestationCertificateDocument(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, opts ...ValidationOption) (bool, string, error) {
 	if gen.CheckIfEmpty(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
 		return false, "", fmt.Errorf(missingParameterErrStatement)
 	}
 
-	valid, msg, err := crt.ValidateAttestationCertificateDocument(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert)
+	config := newValidationConfig(opts)
+	valid, msg, err := crt.ValidateAttestationCertificateDocumentAt(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, config.crlStore, config.at)
 	if err != nil {
 		return false, "", err
 	}
@@ -246,17 +247,19 @@ func HpcrVerifyAttestationCertificateDocument(attestationCert, ibmIntermediateCe
 // Parameters:
 //   - certificateDocument: certificate document content (encryption or attestation)
 //   - ibmIntermediateCert: IBM intermediate certificate content
+//   - opts: Optional settings, e.g. [WithCRLStore] or [WithValidationTime]
 //
 // Returns:
 //   - valid: true if CRL validation succeeded and certificate is not revoked
 //   - message: Detailed validation message
 //   - error: Error with stage information if validation fails
-func HpcrValidateCertificateRevocationList(certificateDocument, ibmIntermediateCert string) (bool, string, error) {
+func HpcrValidateCertificateRevocationList(certificateDocument, ibmIntermediateCert string, opts ...ValidationOption) (bool, string, error) {
 	if gen.CheckIfEmpty(certificateDocument, ibmIntermediateCert) {
 		return false, "", fmt.Errorf(missingParameterErrStatement)
 	}
 
-	valid, msg, err := crt.ValidateCertificateRevocationList(certificateDocument, ibmIntermediateCert)
+	config := newValidationConfig(opts)
+	valid, msg, err := crt.ValidateCertificateRevocationListAt(certificateDocument, ibmIntermediateCert, config.crlStore, config.at)
 	if err != nil {
 		return false, "", err
 	}
@@ -264,6 +267,66 @@ func HpcrValidateCertificateRevocationList(certificateDocument, ibmIntermediateC
 	return valid, msg, nil
 }
 
+// HpcrEncryptionCertificateValidationReport validates an encryption certificate document and its
+// revocation status and reports the result of every stage.
+//
+// The stages are CA verify, signing cert verify, doc signature verify, date verify, CRL signature
+// verify and serial revoked - the checks of [HpcrVerifyEncryptionCertificateDocument] followed by those
+// of [HpcrValidateCertificateRevocationList]. Each stage lists the certificates and the CRL it checked,
+// so a failure can be traced to a single certificate or CRL. A stage that depends on a stage that did
+// not pass is reported as not run.
+//
+// Parameters:
+//   - encryptionCert: encryption certificate document content
+//   - ibmIntermediateCert: IBM intermediate certificate content
+//   - digicertIntermediateCert: DigiCert intermediate certificate content
+//   - digicertRootCert: DigiCert root certificate content
+//   - opts: Optional settings, e.g. [WithCRLStore] or [WithValidationTime]
+//
+// Returns:
+//   - Validation report with per-stage results, nil if a parameter is missing
+//   - Error of the first stage that did not pass (see ValidationReport.Err), nil if the certificate document is valid
+func HpcrEncryptionCertificateValidationReport(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, opts ...ValidationOption) (*crt.ValidationReport, error) {
+	if gen.CheckIfEmpty(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	config := newValidationConfig(opts)
+	report, err := crt.EncryptionCertificateValidationReport(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, config.crlStore, config.at)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, report.Err()
+}
+
+// HpcrAttestationCertificateValidationReport validates an attestation certificate document and its
+// revocation status and reports the result of every stage. See [HpcrEncryptionCertificateValidationReport].
+//
+// Parameters:
+//   - attestationCert: attestation certificate document content
+//   - ibmIntermediateCert: IBM intermediate certificate content
+//   - digicertIntermediateCert: DigiCert intermediate certificate content
+//   - digicertRootCert: DigiCert root certificate content
+//   - opts: Optional settings, e.g. [WithCRLStore] or [WithValidationTime]
+//
+// Returns:
+//   - Validation report with per-stage results, nil if a parameter is missing
+//   - Error of the first stage that did not pass (see ValidationReport.Err), nil if the certificate document is valid
+func HpcrAttestationCertificateValidationReport(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, opts ...ValidationOption) (*crt.ValidationReport, error) {
+	if gen.CheckIfEmpty(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	config := newValidationConfig(opts)
+	report, err := crt.AttestationCertificateValidationReport(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, config.crlStore, config.at)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, report.Err()
+}
+
 // HpcrListAvailableEncCertVersions returns available embedded encryption certificate versions
 // in JSON or YAML format. If osType is provided, returns versions for that specific OS type only.
 // If osType is empty, returns versions for all OS types.
@@ -315,16 +378,15 @@ func HpcrListAvailableEncCertVersions(osType, formatType string) (string, error)
 		osType = strings.ToLower(osType)
 
 		// Check if the specified OS type exists
-		osMap, exists := cert.CertificateMap[osType]
-		if !exists {
+		if !slices.Contains(cert.OsTypes(), osType) {
 			return "", fmt.Errorf("invalid OS type: %s. Valid types are: ccrt, ccrv, ccco, hpvs", osType)
 		}
 
-		result[osType] = gen.CollectAndSortVersions(osMap)
+		result[osType] = cert.DefaultCertStore().Versions(osType)
 	} else {
-		// Get all OS types from the certificate map
-		for osType, osMap := range cert.CertificateMap {
-			result[osType] = gen.CollectAndSortVersions(osMap)
+		// Get all OS types from the default certificate store
+		for _, osType := range cert.OsTypes() {
+			result[osType] = cert.DefaultCertStore().Versions(osType)
 		}
 	}
 
@@ -16,10 +16,13 @@
 package certificate
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
 
 	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -164,6 +167,69 @@ func TestHpcrValidateCertificateRevocationList_EmptyParameters(t *testing.T) {
 	assert.Contains(t, err.Error(), missingParameterErrStatement)
 }
 
+// Testcase to check if HpcrNewCRLStore creates a store that WithCRLStore passes to validation
+func TestHpcrNewCRLStore(t *testing.T) {
+	store, err := HpcrNewCRLStore(filepath.Join(t.TempDir(), "crls"), true)
+	assert.NoError(t, err)
+
+	config := newValidationConfig([]ValidationOption{WithCRLStore(store)})
+	assert.Equal(t, store, config.crlStore)
+
+	_, err = store.CRL("http://example.com/intermediate.crl")
+	assert.EqualError(t, err, "no cached CRL for http://example.com/intermediate.crl (offline mode)")
+
+	_, err = HpcrNewCRLStore("", true)
+	assert.Error(t, err)
+}
+
+// Testcase to check if validation functions still check parameters when a CRL store is set
+func TestHpcrValidationWithCRLStore_EmptyParameters(t *testing.T) {
+	store, err := HpcrNewCRLStore(t.TempDir(), true)
+	assert.NoError(t, err)
+
+	_, _, err = HpcrVerifyEncryptionCertificateDocument("", "", "", "", WithCRLStore(store))
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, _, err = HpcrVerifyAttestationCertificateDocument("", "", "", "", WithCRLStore(store))
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, _, err = HpcrValidateCertificateRevocationList("", "", WithCRLStore(store))
+	assert.EqualError(t, err, missingParameterErrStatement)
+}
+
+// Testcase to check if HpcrValidateEncryptionCertificate() checks the certificate at the time given with WithValidationTime()
+func TestHpcrValidateEncryptionCertificateValidationTime(t *testing.T) {
+	encryptionCert, err := gen.ReadDataFromFile(sampleEncryptionCertPath)
+	require.NoError(t, err)
+
+	_, err = HpcrValidateEncryptionCertificate(encryptionCert)
+	require.NoError(t, err)
+
+	_, err = HpcrValidateEncryptionCertificate(encryptionCert, WithValidationTime(time.Now().AddDate(50, 0, 0)))
+	assert.Error(t, err)
+
+	config := newValidationConfig([]ValidationOption{WithValidationTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))})
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), config.at)
+}
+
+// Testcase to check if HpcrEncryptionCertificateValidationReport() and HpcrAttestationCertificateValidationReport() handle empty and invalid parameters
+func TestHpcrCertificateValidationReport(t *testing.T) {
+	report, err := HpcrEncryptionCertificateValidationReport("", "", "", "")
+	assert.Nil(t, report)
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	report, err = HpcrAttestationCertificateValidationReport("", "", "", "")
+	assert.Nil(t, report)
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	report, err = HpcrEncryptionCertificateValidationReport("invalid", "invalid", "invalid", "invalid")
+	require.NotNil(t, report)
+	assert.False(t, report.Valid)
+	assert.Len(t, report.Stages, 6)
+	assert.EqualError(t, err, "CA verify failed - failed to parse DigiCert intermediate certificate - no PEM encoded certificate found")
+	assert.Equal(t, report.Err(), err)
+}
+
 // Testcase to check if HpcrListAvailableEncCertVersions returns all available certificates when osType is empty (JSON)
 func TestHpcrListAvailableEncCertVersions_AllOsTypes(t *testing.T) {
 	result, err := HpcrListAvailableEncCertVersions("", "json")
@@ -0,0 +1,615 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	crt "github.com/ibm-hyper-protect/contract-go/v2/common/cert"
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	defaultDownloadConcurrency = 4
+)
+
+// DownloadOption configures how certificates and CRLs are downloaded.
+type DownloadOption func(*downloadConfig)
+
+// downloadConfig holds the settings applied by DownloadOption values.
+type downloadConfig struct {
+	http            gen.DownloadConfig
+	concurrency     int
+	trustChain      *TrustChain
+	checkRevocation bool
+	at              time.Time
+	intermediates   []string
+	downloadIssuers bool
+}
+
+// TrustChain holds the certificates downloaded encryption certificates are verified against.
+// Empty intermediates use the embedded intermediate that issued the certificate (see
+// [crt.EmbeddedIntermediateCertificates]) and an empty root uses the embedded DigiCert Trusted Root G4.
+type TrustChain struct {
+	// IBM intermediate certificate that signs encryption certificates
+	IbmIntermediateCert string
+	// DigiCert intermediate certificate that signs the IBM intermediate
+	DigicertIntermediateCert string
+	// DigiCert root certificate
+	DigicertRootCert string
+	// CRL store used for revocation checks; CRLs are downloaded with the download options when nil
+	CRLStore crt.CRLStore
+}
+
+// WithHTTPClient sends download requests with the given client, e.g. an *http.Client configured with
+// a proxy, custom CA roots or a timeout.
+func WithHTTPClient(fetcher gen.Fetcher) DownloadOption {
+	return func(c *downloadConfig) {
+		c.http.Fetcher = fetcher
+	}
+}
+
+// WithRetry retries failed downloads (network errors, 429 and 5xx responses) up to maxAttempts
+// attempts in total, waiting initialBackoff before the first retry and doubling the wait after each
+// retry up to maxBackoff. Zero values use the defaults of 3 attempts, 500ms and 10s; set maxAttempts to
+// 1 to disable retries.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) DownloadOption {
+	return func(c *downloadConfig) {
+		c.http.MaxAttempts = maxAttempts
+		c.http.InitialBackoff = initialBackoff
+		c.http.MaxBackoff = maxBackoff
+	}
+}
+
+// WithUserAgent sets the User-Agent header of download requests.
+func WithUserAgent(userAgent string) DownloadOption {
+	return func(c *downloadConfig) {
+		c.http.UserAgent = userAgent
+	}
+}
+
+// WithConcurrency sets how many versions are downloaded in parallel. Values below 1 download one
+// version at a time. Defaults to 4.
+func WithConcurrency(concurrency int) DownloadOption {
+	return func(c *downloadConfig) {
+		c.concurrency = concurrency
+	}
+}
+
+// WithChainVerification verifies every downloaded encryption certificate against the IBM certificate
+// chain, including the revocation status of the intermediates, before it is returned. Certificates
+// that fail verification are reported as errors and never returned.
+func WithChainVerification(chain TrustChain) DownloadOption {
+	return func(c *downloadConfig) {
+		c.trustChain = &chain
+	}
+}
+
+// WithIssuerDownload downloads intermediates that are neither given in the [TrustChain] nor embedded
+// from the CA Issuers URL of the certificate they issued. The URL is taken from the certificate being
+// verified, so downloaded intermediates are only trusted if they chain to the root.
+func WithIssuerDownload() DownloadOption {
+	return func(c *downloadConfig) {
+		c.downloadIssuers = true
+	}
+}
+
+// WithRevocationCheck additionally checks that downloaded encryption certificates are not revoked.
+// It enables chain verification with the default [TrustChain] unless [WithChainVerification] is given.
+func WithRevocationCheck() DownloadOption {
+	return func(c *downloadConfig) {
+		c.checkRevocation = true
+	}
+}
+
+// WithCheckTime checks the expiry of downloaded certificates and the validity periods of the trust
+// chain and CRLs at the given time instead of the current time.
+func WithCheckTime(at time.Time) DownloadOption {
+	return func(c *downloadConfig) {
+		c.at = at
+	}
+}
+
+// newDownloadConfig applies download options.
+func newDownloadConfig(opts []DownloadOption) downloadConfig {
+	config := downloadConfig{concurrency: defaultDownloadConcurrency, intermediates: crt.EmbeddedIntermediateCertificates()}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.concurrency < 1 {
+		config.concurrency = 1
+	}
+	if config.checkRevocation && config.trustChain == nil {
+		config.trustChain = &TrustChain{}
+	}
+	return config
+}
+
+// CertificateDownloadResult is the outcome of downloading the encryption certificate of one version.
+type CertificateDownloadResult struct {
+	// Runtime version the certificate belongs to
+	Version string
+	// URL the certificate was downloaded from; empty if the version is invalid
+	URL string
+	// PEM-formatted encryption certificate
+	Cert string
+	// Validity status of the certificate
+	Status string
+	// Days until the certificate expires
+	ExpiryDays int
+	// Expiry date of the certificate
+	ExpiryDate string
+	// true if the certificate was verified against the IBM certificate chain
+	Verified bool
+	// Error if the version could not be downloaded; the other fields except Version and URL are empty
+	Err error
+}
+
+// HpcrDownloadEncryptionCertificatesContext downloads encryption certificates like
+// [HpcrDownloadEncryptionCertificates], with a context and download options.
+//
+// Use this function to route downloads through a proxy or custom CA roots with [WithHTTPClient],
+// retry transient failures with [WithRetry], set a User-Agent with [WithUserAgent], limit parallel
+// downloads with [WithConcurrency], or cancel the downloads through the context. Use
+// [HpcrDownloadEncryptionCertificateResults] to get the result of every version instead of failing
+// when one version fails.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the downloads and of retry waits
+//   - versionList: List of runtime versions to download certificates for (e.g., []string{"1.1.14", "1.1.15"})
+//   - formatType: Output format — "json" or "yaml" (defaults to "json" if empty)
+//   - certDownloadUrlTemplate: Custom URL template for certificate download (default IBM Cloud Object Storage URL if empty)
+//   - opts: Download options
+//
+// Returns:
+//   - JSON or YAML formatted map of versions to certificates, each with cert, status, expiry_days,
+//     and expiry_date fields
+//   - Error of the first failed version in versionList order, or if the version format is invalid
+//     or the context is cancelled
+func HpcrDownloadEncryptionCertificatesContext(ctx context.Context, versionList []string, formatType, certDownloadUrlTemplate string, opts ...DownloadOption) (string, error) {
+	if formatType == "" {
+		formatType = defaultFormat
+	}
+
+	results, err := HpcrDownloadEncryptionCertificateResults(ctx, versionList, certDownloadUrlTemplate, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var vertCertMapVersion = make(map[string]map[string]string)
+	for _, result := range results {
+		if result.Err != nil {
+			return "", result.Err
+		}
+
+		var verCertMap = make(map[string]string)
+		verCertMap["cert"] = result.Cert
+		verCertMap["status"] = result.Status
+		verCertMap["expiry_days"] = strconv.Itoa(result.ExpiryDays)
+		verCertMap["expiry_date"] = result.ExpiryDate
+		vertCertMapVersion[result.Version] = verCertMap
+	}
+
+	switch formatType {
+	case formatJson:
+		jsonBytes, err := json.Marshal(vertCertMapVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON - %v", err)
+		}
+
+		return string(jsonBytes), nil
+	case formatYaml:
+		yamlBytes, err := yaml.Marshal(vertCertMapVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal YAML - %v", err)
+		}
+		return string(yamlBytes), nil
+	default:
+		return "", fmt.Errorf("invalid output format")
+	}
+}
+
+// HpcrDownloadEncryptionCertificateResults downloads the encryption certificates of several versions
+// in parallel and reports the result of each version separately.
+//
+// A failed version does not stop the others; its error is recorded in its result. Use this function
+// to refresh many versions at once, e.g. in a nightly job, and handle failures per version.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the downloads and of retry waits
+//   - versionList: List of runtime versions to download certificates for (e.g., []string{"1.1.14", "1.1.15"})
+//   - certDownloadUrlTemplate: Custom URL template for certificate download (default IBM Cloud Object Storage URL if empty)
+//   - opts: Download options, e.g. [WithConcurrency] to limit parallel downloads (default 4)
+//
+// Returns:
+//   - One result per entry of versionList, in the same order
+//   - Error if versionList is empty or the URL template is invalid
+func HpcrDownloadEncryptionCertificateResults(ctx context.Context, versionList []string, certDownloadUrlTemplate string, opts ...DownloadOption) ([]CertificateDownloadResult, error) {
+	if len(versionList) == 0 {
+		return nil, fmt.Errorf(missingParameterErrStatement)
+	}
+
+	if certDownloadUrlTemplate == "" {
+		certDownloadUrlTemplate = defaultEncCertUrlTemplate
+	}
+
+	urlTemplate, err := template.New("url").Parse(certDownloadUrlTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create url template - %v", err)
+	}
+
+	config := newDownloadConfig(opts)
+	var verifier *chainVerifier
+	if config.trustChain != nil {
+		verifier = newChainVerifier(ctx, config)
+	}
+
+	results := make([]CertificateDownloadResult, len(versionList))
+	semaphore := make(chan struct{}, config.concurrency)
+
+	var wg sync.WaitGroup
+	for i, version := range versionList {
+		semaphore <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-semaphore }()
+			results[i] = downloadEncryptionCertificate(ctx, version, urlTemplate, config.http, verifier, config.at)
+		})
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// downloadEncryptionCertificate downloads and checks the encryption certificate of one version,
+// checking its expiry at the given time (now when zero).
+func downloadEncryptionCertificate(ctx context.Context, version string, urlTemplate *template.Template, config gen.DownloadConfig, verifier *chainVerifier, at time.Time) CertificateDownloadResult {
+	result := CertificateDownloadResult{Version: version}
+
+	verSpec := strings.Split(version, ".")
+	if !strings.Contains(version, ".") || len(verSpec) != 3 {
+		result.Err = fmt.Errorf("invalid version format: '%s'. Expected comma-separated versions, e.g., 1.0.21 or 1.0.21,1.0.22", version)
+		return result
+	}
+
+	builder := &strings.Builder{}
+	if err := urlTemplate.Execute(builder, CertSpec{verSpec[0], verSpec[1], verSpec[2]}); err != nil {
+		result.Err = fmt.Errorf("failed to apply template - %v", err)
+		return result
+	}
+	result.URL = builder.String()
+
+	status, err := gen.CheckUrlExistsContext(ctx, result.URL, config)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to check if URL exists - %v", err)
+		return result
+	}
+	if !status {
+		result.Err = fmt.Errorf("encryption certificate doesn't exist in %s", result.URL)
+		return result
+	}
+
+	cert, err := gen.CertificateDownloaderContext(ctx, result.URL, config)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to download encryption certificate - %v", err)
+		return result
+	}
+
+	if verifier != nil {
+		if err := verifier.verify(cert); err != nil {
+			result.Err = fmt.Errorf("encryption certificate verification failed - %v", err)
+			return result
+		}
+		result.Verified = true
+	}
+
+	certStatus, daysLeft, certificateExpiryDate, err := gen.CheckEncryptionCertValidityAt(cert, at)
+	if err != nil {
+		result.Err = err
+		result.Verified = false
+		return result
+	}
+
+	result.Cert = cert
+	result.Status = certStatus
+	result.ExpiryDays = daysLeft
+	result.ExpiryDate = certificateExpiryDate
+	return result
+}
+
+// HpcrVerifyEncryptionCertificateTrust verifies that an encryption certificate is an authentic IBM
+// encryption certificate.
+//
+// The certificate document signature, its validity period, the IBM and DigiCert intermediates with
+// their CRLs and the revocation status of the certificate are checked, as with [WithChainVerification]
+// and [WithRevocationCheck]. Intermediates missing from the chain default to the embedded
+// intermediates and are only downloaded from CA Issuers URLs with [WithIssuerDownload].
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of intermediate and CRL downloads
+//   - encryptionCert: PEM-formatted encryption certificate
+//   - chain: Trust chain; the zero value uses the embedded intermediates and DigiCert Trusted Root G4
+//     and downloads CRLs. Set CRLStore to use cached CRLs (see [HpcrNewCRLStore])
+//   - opts: Download options, e.g. [WithHTTPClient] or [WithCheckTime]
+//
+// Returns:
+//   - Error naming the failed check, nil if the certificate is trusted
+func HpcrVerifyEncryptionCertificateTrust(ctx context.Context, encryptionCert string, chain TrustChain, opts ...DownloadOption) error {
+	if gen.CheckIfEmpty(encryptionCert) {
+		return fmt.Errorf(missingParameterErrStatement)
+	}
+
+	config := newDownloadConfig(opts)
+	config.trustChain = &chain
+	config.checkRevocation = true
+
+	return newChainVerifier(ctx, config).verify(encryptionCert)
+}
+
+// TrustVerifier verifies encryption certificates like [HpcrVerifyEncryptionCertificateTrust], but
+// keeps the intermediates and CRLs it downloads for later verifications. Downloaded CRLs are reused
+// until their nextUpdate time; CRLs of a CRLStore set in the trust chain are not cached again.
+// A TrustVerifier is safe for concurrent use.
+type TrustVerifier struct {
+	config  downloadConfig
+	issuers *issuerCache
+	crls    *crlCache
+}
+
+// HpcrNewTrustVerifier creates a verifier that checks encryption certificates against a trust chain
+// and caches the intermediates and CRLs it downloads.
+//
+// Parameters:
+//   - chain: Trust chain; the zero value uses the embedded intermediates and DigiCert Trusted Root G4
+//     and downloads CRLs. Set CRLStore to use cached CRLs (see [HpcrNewCRLStore])
+//   - opts: Download options, e.g. [WithHTTPClient] or [WithCheckTime]
+//
+// Returns:
+//   - Verifier to check encryption certificates with
+func HpcrNewTrustVerifier(chain TrustChain, opts ...DownloadOption) *TrustVerifier {
+	config := newDownloadConfig(opts)
+	config.trustChain = &chain
+	config.checkRevocation = true
+
+	return &TrustVerifier{
+		config:  config,
+		issuers: newIssuerCache(),
+		crls:    &crlCache{crls: make(map[string]cachedCRL)},
+	}
+}
+
+// Verify verifies that an encryption certificate is an authentic IBM encryption certificate, with the
+// checks of [HpcrVerifyEncryptionCertificateTrust].
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of intermediate and CRL downloads
+//   - encryptionCert: PEM-formatted encryption certificate
+//
+// Returns:
+//   - Error naming the failed check, nil if the certificate is trusted
+func (v *TrustVerifier) Verify(ctx context.Context, encryptionCert string) error {
+	return v.VerifyAt(ctx, encryptionCert, v.config.at)
+}
+
+// VerifyAt is like [TrustVerifier.Verify], but checks the validity periods of the certificates and
+// CRLs at the given time.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of intermediate and CRL downloads
+//   - encryptionCert: PEM-formatted encryption certificate
+//   - at: Time to check validity at; the time of [WithCheckTime] or the current time if zero
+//
+// Returns:
+//   - Error naming the failed check, nil if the certificate is trusted
+func (v *TrustVerifier) VerifyAt(ctx context.Context, encryptionCert string, at time.Time) error {
+	if gen.CheckIfEmpty(encryptionCert) {
+		return fmt.Errorf(missingParameterErrStatement)
+	}
+
+	config := v.config
+	if !at.IsZero() {
+		config.at = at
+	}
+	if config.trustChain.CRLStore == nil {
+		chain := *config.trustChain
+		chain.CRLStore = cachedCRLStore{cache: v.crls, downloader: crt.CRLDownloader{Context: ctx, Config: config.http}}
+		config.trustChain = &chain
+	}
+
+	verifier := newChainVerifier(ctx, config)
+	verifier.issuers = v.issuers
+	return verifier.verify(encryptionCert)
+}
+
+// chainVerifier verifies downloaded encryption certificates against a trust chain and keeps the
+// intermediates it downloads for reuse by other versions.
+type chainVerifier struct {
+	ctx             context.Context
+	http            gen.DownloadConfig
+	chain           TrustChain
+	checkRevocation bool
+	at              time.Time
+	intermediates   []string
+	downloadIssuers bool
+	issuers         *issuerCache
+}
+
+// issuerCache holds downloaded issuer certificates keyed by their CA Issuers URL.
+type issuerCache struct {
+	mu      sync.Mutex
+	issuers map[string]string
+}
+
+// newIssuerCache creates an empty issuer certificate cache.
+func newIssuerCache() *issuerCache {
+	return &issuerCache{issuers: make(map[string]string)}
+}
+
+// crlCache holds downloaded CRLs keyed by their distribution point URL.
+type crlCache struct {
+	mu   sync.Mutex
+	crls map[string]cachedCRL
+}
+
+// cachedCRL is a downloaded CRL with the time by which the next CRL is issued.
+type cachedCRL struct {
+	data       []byte
+	nextUpdate time.Time
+}
+
+// cachedCRLStore is a CRLStore that reuses the CRLs of a cache until their nextUpdate time and
+// downloads missing or stale CRLs into it.
+type cachedCRLStore struct {
+	cache      *crlCache
+	downloader crt.CRLDownloader
+}
+
+// CRL returns the CRL for crlURL that is current now.
+func (s cachedCRLStore) CRL(crlURL string) ([]byte, error) {
+	return s.CRLAt(crlURL, time.Now())
+}
+
+// CRLAt returns the cached CRL for crlURL if it is current at the given time, downloading it otherwise.
+func (s cachedCRLStore) CRLAt(crlURL string, at time.Time) ([]byte, error) {
+	s.cache.mu.Lock()
+	cached, ok := s.cache.crls[crlURL]
+	s.cache.mu.Unlock()
+	if ok && at.Before(cached.nextUpdate) {
+		return cached.data, nil
+	}
+
+	data, err := s.downloader.CRL(crlURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Import(crlURL, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Import adds a CRL for crlURL to the cache, replacing any cached CRL.
+func (s cachedCRLStore) Import(crlURL string, crl []byte) error {
+	der := crl
+	if block, _ := pem.Decode(crl); block != nil {
+		der = block.Bytes
+	}
+	parsed, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return fmt.Errorf("failed to parse CRL - %v", err)
+	}
+
+	s.cache.mu.Lock()
+	s.cache.crls[crlURL] = cachedCRL{data: crl, nextUpdate: parsed.NextUpdate}
+	s.cache.mu.Unlock()
+	return nil
+}
+
+// newChainVerifier creates a verifier for the trust chain of a download configuration.
+func newChainVerifier(ctx context.Context, config downloadConfig) *chainVerifier {
+	chain := *config.trustChain
+	if chain.DigicertRootCert == "" {
+		chain.DigicertRootCert = crt.DigicertTrustedRootG4
+	}
+	if chain.CRLStore == nil {
+		chain.CRLStore = crt.CRLDownloader{Context: ctx, Config: config.http}
+	}
+
+	return &chainVerifier{
+		ctx:             ctx,
+		http:            config.http,
+		chain:           chain,
+		checkRevocation: config.checkRevocation,
+		at:              config.at,
+		intermediates:   config.intermediates,
+		downloadIssuers: config.downloadIssuers,
+		issuers:         newIssuerCache(),
+	}
+}
+
+// verify validates an encryption certificate document and, if enabled, its revocation status.
+func (v *chainVerifier) verify(encryptionCert string) error {
+	ibmIntermediateCert := v.chain.IbmIntermediateCert
+	if ibmIntermediateCert == "" {
+		issuer, err := v.issuer(encryptionCert)
+		if err != nil {
+			return fmt.Errorf("failed to get IBM intermediate certificate - %v", err)
+		}
+		ibmIntermediateCert = issuer
+	}
+
+	digicertIntermediateCert := v.chain.DigicertIntermediateCert
+	if digicertIntermediateCert == "" {
+		issuer, err := v.issuer(ibmIntermediateCert)
+		if err != nil {
+			return fmt.Errorf("failed to get DigiCert intermediate certificate - %v", err)
+		}
+		digicertIntermediateCert = issuer
+	}
+
+	if _, _, err := crt.ValidateEncryptionCertificateDocumentAt(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, v.chain.DigicertRootCert, v.chain.CRLStore, v.at); err != nil {
+		return err
+	}
+
+	if v.checkRevocation {
+		if _, _, err := crt.ValidateCertificateRevocationListAt(encryptionCert, ibmIntermediateCert, v.chain.CRLStore, v.at); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// issuer returns the embedded intermediate that issued a certificate. Without one, the issuer is
+// downloaded once from the CA Issuers URL of the certificate if issuer downloads are enabled.
+func (v *chainVerifier) issuer(certificatePEM string) (string, error) {
+	issuer, err := crt.FindIssuerCertificate(certificatePEM, v.intermediates)
+	if err == nil || !v.downloadIssuers {
+		return issuer, err
+	}
+
+	issuerURL, err := crt.IssuerCertificateURL(certificatePEM)
+	if err != nil {
+		return "", err
+	}
+
+	v.issuers.mu.Lock()
+	cached, ok := v.issuers.issuers[issuerURL]
+	v.issuers.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	issuer, err = crt.DownloadIssuerCertificate(v.ctx, certificatePEM, v.http)
+	if err != nil {
+		return "", err
+	}
+
+	v.issuers.mu.Lock()
+	v.issuers.issuers[issuerURL] = issuer
+	v.issuers.mu.Unlock()
+	return issuer, nil
+}
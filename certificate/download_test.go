@@ -0,0 +1,394 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	"github.com/ibm-hyper-protect/contract-go/v2/internal/testpki"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCertificateServer starts a TLS server that serves the sample encryption certificate for versions
+// whose patch is not listed in missingPatches and records the highest number of parallel requests.
+func newCertificateServer(t *testing.T, missingPatches ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	encryptionCert, err := gen.ReadDataFromFile(sampleEncryptionCertPath)
+	require.NoError(t, err)
+
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := maxInFlight.Load()
+			if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		for _, patch := range missingPatches {
+			if strings.HasSuffix(r.URL.Path, "/"+patch+".crt") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		w.Write([]byte(encryptionCert))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &maxInFlight
+}
+
+// chainFixture is a test certificate chain whose intermediates and encryption certificates are served
+// by an HTTPS server.
+type chainFixture struct {
+	server      *httptest.Server
+	rootCert    string
+	urlTemplate string
+	// PEM-formatted encryption certificates keyed by patch version
+	certs map[string]string
+	// PEM-formatted IBM and DigiCert intermediate certificates
+	intermediates []string
+}
+
+// newChainFixture creates a root, DigiCert and IBM intermediates and encryption certificates for the
+// patch versions 1 (valid), 2 (revoked) and 3 (signed by an untrusted CA). Certificates reference their
+// issuer through CA Issuers URLs served by the fixture server, and CRLs through file URLs.
+func newChainFixture(t *testing.T) *chainFixture {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string][]byte{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Now().UTC()
+	notBefore, notAfter := now.Add(-2*time.Hour), now.Add(72*time.Hour)
+	// Encryption certificates carry the identity of IBM encryption certificates (see encryption.CheckCertificateIdentity)
+	ibmSubject := pkix.Name{CommonName: "Test IBM Intermediate", Organization: []string{"International Business Machines Corporation"}}
+	encryptionSubject := pkix.Name{CommonName: "Container Runtime Contract Encryption", Organization: []string{"IBM India Pvt Ltd"}}
+	crlDP := func(name string) []string {
+		return []string{testpki.FileURL(filepath.Join(dir, name))}
+	}
+
+	rootKey := testpki.MustGenerateRSAKey(t)
+	rootTemplate := testpki.CATemplate(t, "Test DigiCert Root", big.NewInt(100), notBefore, notAfter, nil, nil, 2)
+	rootDER := testpki.MustCreateCertificate(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	rootCert := testpki.MustParseCertificate(t, rootDER)
+
+	digicertKey := testpki.MustGenerateRSAKey(t)
+	digicertTemplate := testpki.CATemplate(t, "Test DigiCert Intermediate", big.NewInt(200), notBefore, notAfter, crlDP("root.crl"), rootCert.SubjectKeyId, 1)
+	digicertDER := testpki.MustCreateCertificate(t, digicertTemplate, rootCert, &digicertKey.PublicKey, rootKey)
+	digicertCert := testpki.MustParseCertificate(t, digicertDER)
+
+	ibmKey := testpki.MustGenerateRSAKey(t)
+	ibmTemplate := testpki.CATemplate(t, ibmSubject.CommonName, big.NewInt(300), notBefore, notAfter, crlDP("digicert.crl"), digicertCert.SubjectKeyId, 0)
+	ibmTemplate.Subject = ibmSubject
+	ibmTemplate.IssuingCertificateURL = []string{server.URL + "/digicert.crt"}
+	ibmDER := testpki.MustCreateCertificate(t, ibmTemplate, digicertCert, &ibmKey.PublicKey, digicertKey)
+	ibmCert := testpki.MustParseCertificate(t, ibmDER)
+
+	attackerKey := testpki.MustGenerateRSAKey(t)
+	attackerTemplate := testpki.CATemplate(t, ibmSubject.CommonName, big.NewInt(666), notBefore, notAfter, nil, nil, 0)
+	attackerTemplate.Subject = ibmSubject
+	attackerDER := testpki.MustCreateCertificate(t, attackerTemplate, attackerTemplate, &attackerKey.PublicKey, attackerKey)
+	attackerCert := testpki.MustParseCertificate(t, attackerDER)
+
+	encryptionCertificate := func(serial int64, issuerURL string, issuer *x509.Certificate, issuerKey *rsa.PrivateKey) []byte {
+		template := testpki.LeafTemplate(t, encryptionSubject.CommonName, big.NewInt(serial), now, false, false, true, crlDP("ibm.crl")[0], issuer.SubjectKeyId)
+		template.Subject = encryptionSubject
+		template.NotAfter = notAfter
+		template.IssuingCertificateURL = []string{issuerURL}
+		return testpki.MustCreateCertificate(t, template, issuer, &testpki.MustGenerateRSAKey(t).PublicKey, issuerKey)
+	}
+	validDER := encryptionCertificate(400, server.URL+"/ibm.crt", ibmCert, ibmKey)
+	revokedDER := encryptionCertificate(401, server.URL+"/ibm.crt", ibmCert, ibmKey)
+	forgedDER := encryptionCertificate(402, server.URL+"/attacker.crt", attackerCert, attackerKey)
+
+	files["/digicert.crt"] = digicertDER
+	files["/ibm.crt"] = ibmDER
+	files["/attacker.crt"] = attackerDER
+	files["/1.0/1.crt"] = []byte(testpki.PEMEncodeCert(validDER))
+	files["/1.0/2.crt"] = []byte(testpki.PEMEncodeCert(revokedDER))
+	files["/1.0/3.crt"] = []byte(testpki.PEMEncodeCert(forgedDER))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root.crl"), testpki.MustCreateCRL(t, rootCert, rootKey, nil, now), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "digicert.crl"), testpki.MustCreateCRL(t, digicertCert, digicertKey, nil, now), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ibm.crl"), testpki.MustCreateCRL(t, ibmCert, ibmKey, []*big.Int{big.NewInt(401)}, now), 0600))
+
+	return &chainFixture{
+		server:        server,
+		rootCert:      testpki.PEMEncodeCert(rootDER),
+		urlTemplate:   server.URL + "/{{.Major}}.{{.Minor}}/{{.Patch}}.crt",
+		intermediates: []string{testpki.PEMEncodeCert(ibmDER), testpki.PEMEncodeCert(digicertDER)},
+		certs: map[string]string{
+			"1": string(files["/1.0/1.crt"]),
+			"2": string(files["/1.0/2.crt"]),
+			"3": string(files["/1.0/3.crt"]),
+		},
+	}
+}
+
+// Testcase to check if HpcrDownloadEncryptionCertificatesContext() downloads through the given client with retries
+func TestHpcrDownloadEncryptionCertificatesContext(t *testing.T) {
+	encryptionCert, err := gen.ReadDataFromFile(sampleEncryptionCertPath)
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/s390x-22/1-0.crt", r.URL.Path)
+		assert.Equal(t, "contract-go-test", r.UserAgent())
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(encryptionCert))
+	}))
+	defer server.Close()
+
+	result, err := HpcrDownloadEncryptionCertificatesContext(context.Background(), []string{"1.0.22"}, "json", server.URL+"/s390x-{{.Patch}}/{{.Major}}-{{.Minor}}.crt",
+		WithHTTPClient(server.Client()), WithRetry(3, time.Millisecond, 0), WithUserAgent("contract-go-test"))
+	require.NoError(t, err)
+	assert.Contains(t, result, "1.0.22")
+	assert.Equal(t, int32(3), requests.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = HpcrDownloadEncryptionCertificatesContext(ctx, []string{"1.0.22"}, "json", server.URL+"/s390x-{{.Patch}}/{{.Major}}-{{.Minor}}.crt", WithHTTPClient(server.Client()))
+	assert.ErrorContains(t, err, "context canceled")
+}
+
+// Testcase to check if HpcrDownloadEncryptionCertificateResults() reports results per version in input order
+func TestHpcrDownloadEncryptionCertificateResults(t *testing.T) {
+	server, maxInFlight := newCertificateServer(t, "22")
+	versions := []string{"1.0.20", "1.0.21", "1.0.22", "bad", "1.0.23", "1.0.24"}
+
+	results, err := HpcrDownloadEncryptionCertificateResults(context.Background(), versions, server.URL+"/{{.Major}}.{{.Minor}}/{{.Patch}}.crt",
+		WithHTTPClient(server.Client()), WithConcurrency(2))
+	require.NoError(t, err)
+	require.Len(t, results, len(versions))
+
+	for i, result := range results {
+		assert.Equal(t, versions[i], result.Version)
+		switch result.Version {
+		case "1.0.22":
+			assert.EqualError(t, result.Err, "encryption certificate doesn't exist in "+server.URL+"/1.0/22.crt")
+			assert.Empty(t, result.Cert)
+		case "bad":
+			assert.ErrorContains(t, result.Err, "invalid version format")
+			assert.Empty(t, result.URL)
+		default:
+			assert.NoError(t, result.Err)
+			assert.Contains(t, result.Cert, "-----BEGIN CERTIFICATE-----")
+			assert.Equal(t, server.URL+"/1.0/"+strings.TrimPrefix(result.Version, "1.0.")+".crt", result.URL)
+			assert.NotEmpty(t, result.ExpiryDate)
+			assert.Greater(t, result.ExpiryDays, 0)
+		}
+	}
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+
+	_, err = HpcrDownloadEncryptionCertificatesContext(context.Background(), versions, "json", server.URL+"/{{.Major}}.{{.Minor}}/{{.Patch}}.crt",
+		WithHTTPClient(server.Client()))
+	assert.EqualError(t, err, "encryption certificate doesn't exist in "+server.URL+"/1.0/22.crt")
+}
+
+// Testcase to check if HpcrDownloadEncryptionCertificateResults() rejects invalid input
+func TestHpcrDownloadEncryptionCertificateResultsInvalid(t *testing.T) {
+	_, err := HpcrDownloadEncryptionCertificateResults(context.Background(), nil, "")
+	assert.EqualError(t, err, missingParameterErrStatement)
+
+	_, err = HpcrDownloadEncryptionCertificateResults(context.Background(), []string{"1.0.22"}, "{{.Patch")
+	assert.ErrorContains(t, err, "failed to create url template")
+}
+
+// Testcase to check if WithChainVerification() only returns certificates that chain to the trusted root
+func TestHpcrDownloadEncryptionCertificateResultsChainVerification(t *testing.T) {
+	fixture := newChainFixture(t)
+	versions := []string{"1.0.1", "1.0.2", "1.0.3"}
+
+	results, err := HpcrDownloadEncryptionCertificateResults(context.Background(), versions, fixture.urlTemplate,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert}))
+	require.NoError(t, err)
+
+	assert.NoError(t, results[0].Err)
+	assert.True(t, results[0].Verified)
+	assert.Contains(t, results[0].Cert, "-----BEGIN CERTIFICATE-----")
+
+	assert.NoError(t, results[1].Err)
+	assert.True(t, results[1].Verified)
+
+	assert.ErrorContains(t, results[2].Err, "encryption certificate verification failed")
+	assert.False(t, results[2].Verified)
+	assert.Empty(t, results[2].Cert)
+
+	results, err = HpcrDownloadEncryptionCertificateResults(context.Background(), versions[:2], fixture.urlTemplate,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert}), WithRevocationCheck())
+	require.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "serial revoked failed")
+	assert.Empty(t, results[1].Cert)
+}
+
+// Testcase to check if WithRevocationCheck() verifies against the embedded DigiCert root by default
+func TestHpcrDownloadEncryptionCertificateResultsEmbeddedRoot(t *testing.T) {
+	fixture := newChainFixture(t)
+
+	results, err := HpcrDownloadEncryptionCertificateResults(context.Background(), []string{"1.0.1"}, fixture.urlTemplate,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithRevocationCheck())
+	require.NoError(t, err)
+	assert.ErrorContains(t, results[0].Err, "encryption certificate verification failed - CA verify failed")
+	assert.Empty(t, results[0].Cert)
+}
+
+// Testcase to check if WithCheckTime() checks certificate expiry and the trust chain at the given time
+func TestHpcrDownloadEncryptionCertificateResultsCheckTime(t *testing.T) {
+	fixture := newChainFixture(t)
+	now := time.Now()
+
+	results, err := HpcrDownloadEncryptionCertificateResults(context.Background(), []string{"1.0.1"}, fixture.urlTemplate,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), WithCheckTime(now.Add(100*time.Hour)))
+	require.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "expired", results[0].Status)
+	assert.Less(t, results[0].ExpiryDays, 0)
+
+	chain := WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert})
+	results, err = HpcrDownloadEncryptionCertificateResults(context.Background(), []string{"1.0.1"}, fixture.urlTemplate,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain, WithCheckTime(now.Add(time.Hour)))
+	require.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.True(t, results[0].Verified)
+
+	// The CRLs of the fixture expire after 24 hours
+	results, err = HpcrDownloadEncryptionCertificateResults(context.Background(), []string{"1.0.1"}, fixture.urlTemplate,
+		WithHTTPClient(fixture.server.Client()), WithIssuerDownload(), chain, WithCheckTime(now.Add(30*time.Hour)))
+	require.NoError(t, err)
+	assert.ErrorContains(t, results[0].Err, "CRL has expired")
+	assert.False(t, results[0].Verified)
+}
+
+// Testcase to check if HpcrVerifyEncryptionCertificateTrust() verifies the chain and revocation status of a certificate
+func TestHpcrVerifyEncryptionCertificateTrust(t *testing.T) {
+	fixture := newChainFixture(t)
+	chain := TrustChain{DigicertRootCert: fixture.rootCert}
+	client := []DownloadOption{WithHTTPClient(fixture.server.Client()), WithIssuerDownload()}
+
+	assert.NoError(t, HpcrVerifyEncryptionCertificateTrust(context.Background(), fixture.certs["1"], chain, client...))
+
+	err := HpcrVerifyEncryptionCertificateTrust(context.Background(), fixture.certs["2"], chain, client...)
+	assert.ErrorContains(t, err, "serial revoked failed")
+
+	err = HpcrVerifyEncryptionCertificateTrust(context.Background(), fixture.certs["3"], chain, client...)
+	assert.Error(t, err)
+
+	err = HpcrVerifyEncryptionCertificateTrust(context.Background(), fixture.certs["1"], TrustChain{}, client...)
+	assert.ErrorContains(t, err, "CA verify failed")
+
+	// Intermediates are only downloaded from the CA Issuers URL of the certificate when enabled
+	err = HpcrVerifyEncryptionCertificateTrust(context.Background(), fixture.certs["1"], chain, client[0])
+	assert.EqualError(t, err, "failed to get IBM intermediate certificate - issuer \"Test IBM Intermediate\" of certificate \"Container Runtime Contract Encryption\" is not a known intermediate certificate")
+
+	err = HpcrVerifyEncryptionCertificateTrust(context.Background(), "", chain)
+	assert.EqualError(t, err, missingParameterErrStatement)
+}
+
+// countingFetcher counts the requests sent through an HTTP client.
+type countingFetcher struct {
+	client   *http.Client
+	requests atomic.Int32
+}
+
+func (f *countingFetcher) Do(req *http.Request) (*http.Response, error) {
+	f.requests.Add(1)
+	return f.client.Do(req)
+}
+
+// failingFetcher fails every request sent through it and counts them.
+type failingFetcher struct {
+	requests atomic.Int32
+}
+
+func (f *failingFetcher) Do(req *http.Request) (*http.Response, error) {
+	f.requests.Add(1)
+	return nil, fmt.Errorf("network is unreachable")
+}
+
+// Testcase to check if embedded intermediates verify certificates without any network request
+func TestHpcrVerifyEncryptionCertificateTrustEmbeddedIntermediates(t *testing.T) {
+	fixture := newChainFixture(t)
+	fetcher := &failingFetcher{}
+
+	config := newDownloadConfig([]DownloadOption{WithHTTPClient(fetcher), WithChainVerification(TrustChain{DigicertRootCert: fixture.rootCert}), WithRevocationCheck()})
+	config.intermediates = fixture.intermediates
+	verifier := newChainVerifier(context.Background(), config)
+
+	assert.NoError(t, verifier.verify(fixture.certs["1"]))
+	assert.ErrorContains(t, verifier.verify(fixture.certs["2"]), "serial revoked failed")
+	assert.ErrorContains(t, verifier.verify(fixture.certs["3"]), "failed to get IBM intermediate certificate")
+	assert.Zero(t, fetcher.requests.Load())
+}
+
+// Testcase to check if TrustVerifier reuses downloaded intermediates and CRLs across verifications
+func TestTrustVerifierCachesDownloads(t *testing.T) {
+	fixture := newChainFixture(t)
+	fetcher := &countingFetcher{client: fixture.server.Client()}
+	verifier := HpcrNewTrustVerifier(TrustChain{DigicertRootCert: fixture.rootCert}, WithHTTPClient(fetcher), WithIssuerDownload())
+
+	require.NoError(t, verifier.Verify(context.Background(), fixture.certs["1"]))
+	assert.Equal(t, int32(2), fetcher.requests.Load())
+
+	// The CRL of the IBM intermediate is served from the cache once its file is gone
+	block, _ := pem.Decode([]byte(fixture.certs["1"]))
+	encryptionCert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	crlURL, err := url.Parse(encryptionCert.CRLDistributionPoints[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(crlURL.Path))
+
+	assert.NoError(t, verifier.Verify(context.Background(), fixture.certs["1"]))
+	assert.ErrorContains(t, verifier.Verify(context.Background(), fixture.certs["2"]), "serial revoked failed")
+	assert.Equal(t, int32(2), fetcher.requests.Load())
+
+	assert.Error(t, verifier.VerifyAt(context.Background(), fixture.certs["1"], time.Now().Add(-3*time.Hour)))
+	assert.EqualError(t, verifier.Verify(context.Background(), ""), missingParameterErrStatement)
+}
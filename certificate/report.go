@@ -0,0 +1,242 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
+)
+
+const (
+	defaultWarnDays     = 180
+	defaultCriticalDays = 30
+)
+
+// Expiry status of a certificate in a [CertificateExpiryReport].
+const (
+	ExpiryStatusValid       = "valid"
+	ExpiryStatusWarning     = "warning"
+	ExpiryStatusCritical    = "critical"
+	ExpiryStatusExpired     = "expired"
+	ExpiryStatusNotYetValid = "not-yet-valid"
+)
+
+// ExpiryThresholds defines when a certificate is reported as expiring soon.
+type ExpiryThresholds struct {
+	// Certificates expiring in fewer days are reported as warning. Defaults to 180 when zero.
+	WarnDays int
+	// Certificates expiring in fewer days are reported as critical. Defaults to 30, or WarnDays if lower,
+	// when nil. Set it to 0 to report no certificate as critical.
+	CriticalDays *int
+	// Time the days left are computed from. Defaults to the current time when zero.
+	At time.Time
+}
+
+// CertificateExpiryReport lists the expiry of the encryption certificates of a certificate store.
+type CertificateExpiryReport struct {
+	// Time the days left are computed from, the generation time unless ExpiryThresholds.At is set
+	GeneratedAt time.Time `json:"generatedAt" yaml:"generatedAt"`
+	// Warning threshold in days
+	WarnDays int `json:"warnDays" yaml:"warnDays"`
+	// Critical threshold in days
+	CriticalDays int `json:"criticalDays" yaml:"criticalDays"`
+	// Number of certificates per status
+	Counts map[string]int `json:"counts" yaml:"counts"`
+	// Certificates by platform, latest version first
+	Certificates []CertificateExpiryEntry `json:"certificates" yaml:"certificates"`
+}
+
+// CertificateExpiryEntry is the expiry information of one encryption certificate.
+type CertificateExpiryEntry struct {
+	// Platform the certificate belongs to
+	Platform string `json:"platform" yaml:"platform"`
+	// Certificate version
+	Version string `json:"version" yaml:"version"`
+	// Subject distinguished name
+	Subject string `json:"subject" yaml:"subject"`
+	// Serial number in hexadecimal
+	Serial string `json:"serial" yaml:"serial"`
+	// SHA-256 fingerprint of the DER certificate in hexadecimal
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+	// Start of the validity period
+	NotBefore time.Time `json:"notBefore" yaml:"notBefore"`
+	// End of the validity period
+	NotAfter time.Time `json:"notAfter" yaml:"notAfter"`
+	// Whole days until the certificate expires, rounded down, so negative once expired
+	DaysLeft int `json:"daysLeft" yaml:"daysLeft"`
+	// ExpiryStatusValid, ExpiryStatusWarning, ExpiryStatusCritical, ExpiryStatusExpired or ExpiryStatusNotYetValid
+	Status string `json:"status" yaml:"status"`
+}
+
+// HpcrGetCertificateExpiryReport reports the expiry of every encryption certificate of a certificate store.
+//
+// Use this function to monitor certificates, e.g. in a daily job that alerts when a certificate
+// reaches the warning or critical threshold. The default store covers the embedded certificates and
+// the certificates registered at runtime.
+//
+// Parameters:
+//   - store: Certificate store to report on - uses encryption.DefaultCertStore() if nil
+//   - thresholds: Warning and critical thresholds in days (defaults 180 and the lower of 30 and the warning threshold) and the report time (defaults to now)
+//
+// Returns:
+//   - Expiry report
+//   - Error if the thresholds are invalid or a certificate cannot be parsed
+func HpcrGetCertificateExpiryReport(store cert.CertStore, thresholds ExpiryThresholds) (*CertificateExpiryReport, error) {
+	if thresholds.WarnDays == 0 {
+		thresholds.WarnDays = defaultWarnDays
+	}
+	criticalDays := min(defaultCriticalDays, thresholds.WarnDays)
+	if thresholds.CriticalDays != nil {
+		criticalDays = *thresholds.CriticalDays
+	}
+	if thresholds.WarnDays < 0 || criticalDays < 0 || criticalDays > thresholds.WarnDays {
+		return nil, fmt.Errorf("invalid expiry thresholds: warn %d days, critical %d days - critical must not exceed warn and neither may be negative", thresholds.WarnDays, criticalDays)
+	}
+
+	if store == nil {
+		store = cert.DefaultCertStore()
+	}
+
+	generatedAt := thresholds.At
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+
+	report := &CertificateExpiryReport{
+		GeneratedAt:  generatedAt.UTC(),
+		WarnDays:     thresholds.WarnDays,
+		CriticalDays: criticalDays,
+		Counts: map[string]int{
+			ExpiryStatusValid:       0,
+			ExpiryStatusWarning:     0,
+			ExpiryStatusCritical:    0,
+			ExpiryStatusExpired:     0,
+			ExpiryStatusNotYetValid: 0,
+		},
+		Certificates: []CertificateExpiryEntry{},
+	}
+
+	for _, platform := range cert.OsTypes() {
+		for _, version := range store.Versions(platform) {
+			certificatePEM, ok := store.Certificate(platform, version)
+			if !ok {
+				continue
+			}
+
+			entry, err := certificateExpiryEntry(platform, version, certificatePEM, report.GeneratedAt, report.WarnDays, report.CriticalDays)
+			if err != nil {
+				return nil, err
+			}
+			report.Counts[entry.Status]++
+			report.Certificates = append(report.Certificates, entry)
+		}
+	}
+
+	return report, nil
+}
+
+// HpcrCertificateExpiryReport reports the expiry of every encryption certificate of a certificate
+// store as JSON or YAML. See [HpcrGetCertificateExpiryReport].
+//
+// Parameters:
+//   - store: Certificate store to report on - uses encryption.DefaultCertStore() if nil
+//   - thresholds: Warning and critical thresholds in days (defaults 180 and the lower of 30 and the warning threshold) and the report time (defaults to now)
+//   - formatType: Output format — "json" or "yaml" (defaults to "json" if empty)
+//
+// Returns:
+//   - JSON or YAML formatted expiry report
+//   - Error if the thresholds or format are invalid or a certificate cannot be parsed
+func HpcrCertificateExpiryReport(store cert.CertStore, thresholds ExpiryThresholds, formatType string) (string, error) {
+	if formatType == "" {
+		formatType = defaultFormat
+	}
+
+	if formatType != formatJson && formatType != formatYaml {
+		return "", fmt.Errorf("invalid output format: %s. Valid formats are: json, yaml", formatType)
+	}
+
+	report, err := HpcrGetCertificateExpiryReport(store, thresholds)
+	if err != nil {
+		return "", err
+	}
+
+	switch formatType {
+	case formatJson:
+		jsonBytes, err := json.Marshal(report)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON - %v", err)
+		}
+		return string(jsonBytes), nil
+	default:
+		yamlBytes, err := yaml.Marshal(report)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal YAML - %v", err)
+		}
+		return string(yamlBytes), nil
+	}
+}
+
+// certificateExpiryEntry computes the expiry information of a certificate at a point in time.
+func certificateExpiryEntry(platform, version, certificatePEM string, now time.Time, warnDays, criticalDays int) (CertificateExpiryEntry, error) {
+	block, _ := pem.Decode([]byte(certificatePEM))
+	if block == nil {
+		return CertificateExpiryEntry{}, fmt.Errorf("failed to parse certificate %s %s - no PEM encoded certificate found", platform, version)
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return CertificateExpiryEntry{}, fmt.Errorf("failed to parse certificate %s %s - %v", platform, version, err)
+	}
+
+	fingerprint := sha256.Sum256(certificate.Raw)
+	daysLeft := int(math.Floor(certificate.NotAfter.Sub(now).Hours() / 24))
+
+	var status string
+	switch {
+	case now.After(certificate.NotAfter):
+		status = ExpiryStatusExpired
+	case now.Before(certificate.NotBefore):
+		status = ExpiryStatusNotYetValid
+	case daysLeft < criticalDays:
+		status = ExpiryStatusCritical
+	case daysLeft < warnDays:
+		status = ExpiryStatusWarning
+	default:
+		status = ExpiryStatusValid
+	}
+
+	return CertificateExpiryEntry{
+		Platform:    platform,
+		Version:     version,
+		Subject:     certificate.Subject.String(),
+		Serial:      fmt.Sprintf("%X", certificate.SerialNumber),
+		Fingerprint: hex.EncodeToString(fingerprint[:]),
+		NotBefore:   certificate.NotBefore.UTC(),
+		NotAfter:    certificate.NotAfter.UTC(),
+		DaysLeft:    daysLeft,
+		Status:      status,
+	}, nil
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sampleExpiredEncryptionCertPath = "../samples/encryption-cert/expired.crt"
+)
+
+// reportStore returns a store with the active sample certificate as ccrt 2.0.0 and the expired one as ccrt 1.0.0.
+func reportStore(t *testing.T) *cert.MemoryCertStore {
+	t.Helper()
+
+	activeCert, err := gen.ReadDataFromFile(sampleEncryptionCertPath)
+	require.NoError(t, err)
+	expiredCert, err := gen.ReadDataFromFile(sampleExpiredEncryptionCertPath)
+	require.NoError(t, err)
+
+	store := cert.NewCertStore()
+	require.NoError(t, store.Add("ccrt", "1.0.0", expiredCert))
+	require.NoError(t, store.Add("ccrt", "2.0.0", activeCert))
+	return store
+}
+
+// expiryDays returns a pointer to a threshold in days.
+func expiryDays(days int) *int {
+	return &days
+}
+
+// Testcase to check if HpcrGetCertificateExpiryReport() reports every certificate against the given thresholds
+func TestHpcrGetCertificateExpiryReport(t *testing.T) {
+	store := reportStore(t)
+
+	report, err := HpcrGetCertificateExpiryReport(store, ExpiryThresholds{})
+	require.NoError(t, err)
+	assert.Equal(t, 180, report.WarnDays)
+	assert.Equal(t, 30, report.CriticalDays)
+	require.Len(t, report.Certificates, 2)
+
+	active := report.Certificates[0]
+	assert.Equal(t, "ccrt", active.Platform)
+	assert.Equal(t, "2.0.0", active.Version)
+	assert.NotEmpty(t, active.Subject)
+	assert.NotEmpty(t, active.Serial)
+	assert.Len(t, active.Fingerprint, 64)
+	assert.True(t, active.NotBefore.Before(active.NotAfter))
+	assert.Greater(t, active.DaysLeft, 0)
+
+	expired := report.Certificates[1]
+	assert.Equal(t, "1.0.0", expired.Version)
+	assert.Equal(t, ExpiryStatusExpired, expired.Status)
+	assert.Less(t, expired.DaysLeft, 0)
+
+	// Thresholds are relative to the days left of the active certificate so the test does not depend on the date
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{WarnDays: active.DaysLeft + 1, CriticalDays: expiryDays(active.DaysLeft)})
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryStatusWarning, report.Certificates[0].Status)
+	assert.Equal(t, map[string]int{ExpiryStatusValid: 0, ExpiryStatusWarning: 1, ExpiryStatusCritical: 0, ExpiryStatusExpired: 1, ExpiryStatusNotYetValid: 0}, report.Counts)
+
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{WarnDays: active.DaysLeft + 2, CriticalDays: expiryDays(active.DaysLeft + 1)})
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryStatusCritical, report.Certificates[0].Status)
+
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{WarnDays: active.DaysLeft, CriticalDays: expiryDays(1)})
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryStatusValid, report.Certificates[0].Status)
+
+	// Days left are computed from the given time
+	at := active.NotAfter.Add(-10 * 24 * time.Hour)
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{At: at})
+	require.NoError(t, err)
+	assert.True(t, at.Equal(report.GeneratedAt))
+	assert.Equal(t, 10, report.Certificates[0].DaysLeft)
+	assert.Equal(t, ExpiryStatusCritical, report.Certificates[0].Status)
+
+	// Days left are rounded down, so a certificate that expired an hour ago has -1 days left
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{At: active.NotAfter.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryStatusExpired, report.Certificates[0].Status)
+	assert.Equal(t, -1, report.Certificates[0].DaysLeft)
+	assert.Equal(t, 2, report.Counts[ExpiryStatusExpired])
+
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{At: active.NotBefore.Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryStatusNotYetValid, report.Certificates[0].Status)
+	assert.Equal(t, 1, report.Counts[ExpiryStatusNotYetValid])
+}
+
+// Testcase to check if HpcrGetCertificateExpiryReport() lowers the default critical threshold to the warning threshold
+func TestHpcrGetCertificateExpiryReportLowWarnDays(t *testing.T) {
+	store := reportStore(t)
+
+	report, err := HpcrGetCertificateExpiryReport(store, ExpiryThresholds{WarnDays: 14})
+	require.NoError(t, err)
+	assert.Equal(t, 14, report.WarnDays)
+	assert.Equal(t, 14, report.CriticalDays)
+
+	active := report.Certificates[0]
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{WarnDays: 14, At: active.NotAfter.Add(-10 * 24 * time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, ExpiryStatusCritical, report.Certificates[0].Status)
+}
+
+// Testcase to check if HpcrGetCertificateExpiryReport() reports no certificate as critical when CriticalDays is 0
+func TestHpcrGetCertificateExpiryReportNoCritical(t *testing.T) {
+	store := reportStore(t)
+
+	report, err := HpcrGetCertificateExpiryReport(store, ExpiryThresholds{CriticalDays: expiryDays(0)})
+	require.NoError(t, err)
+	assert.Equal(t, 180, report.WarnDays)
+	assert.Equal(t, 0, report.CriticalDays)
+
+	active := report.Certificates[0]
+	report, err = HpcrGetCertificateExpiryReport(store, ExpiryThresholds{CriticalDays: expiryDays(0), At: active.NotAfter.Add(-time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Certificates[0].DaysLeft)
+	assert.Equal(t, ExpiryStatusWarning, report.Certificates[0].Status)
+	assert.Equal(t, 0, report.Counts[ExpiryStatusCritical])
+}
+
+// Testcase to check if HpcrCertificateExpiryReport() formats the report as JSON or YAML
+func TestHpcrCertificateExpiryReport(t *testing.T) {
+	store := reportStore(t)
+
+	jsonReport, err := HpcrCertificateExpiryReport(store, ExpiryThresholds{}, "")
+	require.NoError(t, err)
+	var fromJson CertificateExpiryReport
+	require.NoError(t, json.Unmarshal([]byte(jsonReport), &fromJson))
+	assert.Len(t, fromJson.Certificates, 2)
+	assert.Contains(t, jsonReport, `"fingerprint"`)
+
+	yamlReport, err := HpcrCertificateExpiryReport(store, ExpiryThresholds{}, "yaml")
+	require.NoError(t, err)
+	var fromYaml CertificateExpiryReport
+	require.NoError(t, yaml.Unmarshal([]byte(yamlReport), &fromYaml))
+	assert.Equal(t, fromJson.Certificates[1].Fingerprint, fromYaml.Certificates[1].Fingerprint)
+	assert.Contains(t, yamlReport, "notAfter:")
+
+	// The default store covers the embedded certificates
+	defaultReport, err := HpcrGetCertificateExpiryReport(nil, ExpiryThresholds{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, defaultReport.Certificates)
+}
+
+// Testcase to check if HpcrCertificateExpiryReport() rejects invalid thresholds and formats
+func TestHpcrCertificateExpiryReportInvalid(t *testing.T) {
+	_, err := HpcrCertificateExpiryReport(nil, ExpiryThresholds{WarnDays: 10, CriticalDays: expiryDays(20)}, "json")
+	assert.ErrorContains(t, err, "invalid expiry thresholds")
+
+	_, err = HpcrCertificateExpiryReport(nil, ExpiryThresholds{WarnDays: -1}, "json")
+	assert.ErrorContains(t, err, "invalid expiry thresholds")
+
+	_, err = HpcrCertificateExpiryReport(nil, ExpiryThresholds{CriticalDays: expiryDays(-1)}, "json")
+	assert.ErrorContains(t, err, "invalid expiry thresholds")
+
+	_, err = HpcrCertificateExpiryReport(nil, ExpiryThresholds{}, "xml")
+	assert.EqualError(t, err, "invalid output format: xml. Valid formats are: json, yaml")
+}
@@ -16,16 +16,14 @@
 package cert
 
 import (
+	"bytes"
 	"context"
-	"errors"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
-	"io"
-	"net/http"
+	"math/big"
 	"net/url"
 	"os"
-	"os/exec"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -33,23 +31,33 @@ import (
 )
 
 const (
-	opensslCommandTimeout = 30 * time.Second
-
-	stageCAVerify           = "CA verify"
-	stageSigningCertVerify  = "signing cert verify"
-	stageDocSignatureVerify = "doc signature verify"
-	stageDateVerify         = "date verify"
-	stageCRLSignatureVerify = "CRL signature verify"
-	stageSerialRevoked      = "serial revoked"
+	// Layout used for certificate and CRL timestamps in validation messages
+	certificateTimeLayout = "Jan _2 15:04:05 2006 GMT"
 )
 
-var (
-	asn1OffsetPattern = regexp.MustCompile(`^\s*([0-9]+):`)
-	crlURIRegex       = regexp.MustCompile(`URI:([^\s]+)`)
-	serialRegex       = regexp.MustCompile(`Serial Number:\s*([0-9A-Fa-f:]+)`)
-)
+// CRLSnapshot holds CRL content (DER or PEM) keyed by the CRL distribution point URL it was fetched from.
+// It implements CRLStore and never downloads.
+type CRLSnapshot map[string][]byte
+
+// CRL returns the CRL stored for crlURL.
+func (s CRLSnapshot) CRL(crlURL string) ([]byte, error) {
+	data, ok := s[crlURL]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("CRL %s is not in the snapshot", crlURL)
+	}
+	return data, nil
+}
 
-// ValidateCertificateChain validates a complete certificate chain using OpenSSL verify command.
+// Import adds a CRL to the snapshot.
+func (s CRLSnapshot) Import(crlURL string, crl []byte) error {
+	if _, err := parseCRL(crl); err != nil {
+		return fmt.Errorf("failed to parse CRL - %v", err)
+	}
+	s[crlURL] = crl
+	return nil
+}
+
+// ValidateCertificateChain validates a complete certificate chain.
 // It verifies the trust chain from encryption certificate through intermediate to root CA.
 //
 // Parameters:
@@ -62,74 +70,47 @@ var (
 //   - message: Detailed validation message including expiry information
 //   - error: Error if validation process fails
 func ValidateCertificateChain(encCertPEM, intermediateCertPEM, rootCertPEM string) (bool, string, error) {
+	return ValidateCertificateChainAt(encCertPEM, intermediateCertPEM, rootCertPEM, time.Now())
+}
+
+// ValidateCertificateChainAt validates a certificate chain like ValidateCertificateChain, checking the
+// validity periods at the given time instead of the current time.
+//
+// Parameters:
+//   - encCertPEM: PEM-formatted encryption certificate to validate
+//   - intermediateCertPEM: PEM-formatted intermediate CA certificate
+//   - rootCertPEM: PEM-formatted root CA certificate
+//   - at: Time the chain is validated at; the current time if zero
+//
+// Returns:
+//   - valid: true if certificate chain is valid, false otherwise
+//   - message: Detailed validation message including expiry information
+//   - error: Error if validation process fails
+func ValidateCertificateChainAt(encCertPEM, intermediateCertPEM, rootCertPEM string, at time.Time) (bool, string, error) {
 	if gen.CheckIfEmpty(encCertPEM, intermediateCertPEM, rootCertPEM) {
 		return false, "", fmt.Errorf("required parameter is missing")
 	}
 
-	encCertPath, err := gen.CreateTempFile(encCertPEM)
+	encCert, err := parseCertificatePEM(encCertPEM)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to create temp file for encryption certificate - %v", err)
+		return false, err.Error(), fmt.Errorf("certificate chain validation failed - failed to parse encryption certificate - %v", err)
 	}
-	defer gen.RemoveTempFile(encCertPath)
 
-	intermediateCertPath, err := gen.CreateTempFile(intermediateCertPEM)
+	intermediateCert, err := parseCertificatePEM(intermediateCertPEM)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to create temp file for intermediate certificate - %v", err)
+		return false, err.Error(), fmt.Errorf("certificate chain validation failed - failed to parse intermediate certificate - %v", err)
 	}
-	defer gen.RemoveTempFile(intermediateCertPath)
 
-	rootCertPath, err := gen.CreateTempFile(rootCertPEM)
+	rootCert, err := parseCertificatePEM(rootCertPEM)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to create temp file for root certificate - %v", err)
+		return false, err.Error(), fmt.Errorf("certificate chain validation failed - failed to parse root certificate - %v", err)
 	}
-	defer gen.RemoveTempFile(rootCertPath)
 
-	result, err := gen.ExecCommand(gen.GetOpenSSLPath(), "", "verify", "-CAfile", rootCertPath, "-untrusted", intermediateCertPath, encCertPath)
-
-	if err != nil {
-		errMsg := strings.TrimSpace(result)
-		if errMsg == "" {
-			errMsg = err.Error()
-		}
-		return false, errMsg, fmt.Errorf("certificate chain validation failed - %v", errMsg)
-	}
-
-	if strings.Contains(result, "OK") {
-		// Get certificate expiry information
-		expiryMsg, err := getCertificateExpiry(encCertPath)
-		if err != nil {
-			return true, "Certificate chain is valid", nil
-		}
-		return true, fmt.Sprintf("Certificate chain is valid. %s", expiryMsg), nil
-	}
-
-	return false, strings.TrimSpace(result), fmt.Errorf("certificate chain validation failed")
-}
-
-// getCertificateExpiry gets the expiry information from a certificate using OpenSSL.
-// It extracts the notAfter date from the certificate and formats it as a readable message.
-//
-// Parameters:
-//   - certPath: File path to the certificate
-//
-// Returns:
-//   - Formatted expiry message (e.g., "Certificate expires on Jan 15 23:59:59 2027 GMT")
-//   - Error if extraction fails
-func getCertificateExpiry(certPath string) (string, error) {
-	// Get the end date of the certificate
-	result, err := gen.ExecCommand(gen.GetOpenSSLPath(), "", "x509", "-in", certPath, "-noout", "-enddate")
-
-	if err != nil {
-		return "", err
+	if _, err := verifyChain(encCert, rootCert, intermediateCert, validationTime(at)); err != nil {
+		return false, err.Error(), fmt.Errorf("certificate chain validation failed - %v", err)
 	}
 
-	result = strings.TrimSpace(result)
-	if strings.HasPrefix(result, "notAfter=") {
-		dateStr := strings.TrimPrefix(result, "notAfter=")
-		return fmt.Sprintf("Certificate expires on %s", dateStr), nil
-	}
-
-	return "", fmt.Errorf("failed to parse certificate expiry")
+	return true, fmt.Sprintf("Certificate chain is valid. Certificate expires on %s", formatCertificateTime(encCert.NotAfter)), nil
 }
 
 // ValidateEncryptionCertificateDocument validates an encryption certificate document
@@ -141,6 +122,8 @@ func ValidateEncryptionCertificateDocument(encryptionCert, ibmIntermediateCert,
 		digicertIntermediateCert,
 		digicertRootCert,
 		"encryption",
+		nil,
+		time.Now(),
 	)
 }
 
@@ -153,629 +136,362 @@ func ValidateAttestationCertificateDocument(attestationCert, ibmIntermediateCert
 		digicertIntermediateCert,
 		digicertRootCert,
 		"attestation",
+		nil,
+		time.Now(),
 	)
 }
 
-// validateCertificateDocument runs the shared validation flow for encryption or attestation documents.
-func validateCertificateDocument(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, certType string) (bool, string, error) {
-	if gen.CheckIfEmpty(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
+// ValidateEncryptionCertificateDocumentWithCRLs validates an encryption certificate document like
+// ValidateEncryptionCertificateDocument, but takes CRLs from a CRL store instead of downloading them
+// on every validation.
+func ValidateEncryptionCertificateDocumentWithCRLs(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, crls CRLStore) (bool, string, error) {
+	if crls == nil {
 		return false, "", fmt.Errorf("required parameter is missing")
 	}
+	return validateCertificateDocument(
+		encryptionCert,
+		ibmIntermediateCert,
+		digicertIntermediateCert,
+		digicertRootCert,
+		"encryption",
+		crls,
+		time.Now(),
+	)
+}
 
-	targetCertPath, err := gen.CreateTempFile(targetCert)
-	if err != nil {
-		return false, "", stageError(stageDocSignatureVerify, fmt.Sprintf("failed to create temp file for %s certificate - %v", certType, err))
-	}
-	defer gen.RemoveTempFile(targetCertPath)
-
-	ibmIntermediatePath, err := gen.CreateTempFile(ibmIntermediateCert)
-	if err != nil {
-		return false, "", stageError(stageSigningCertVerify, fmt.Sprintf("failed to create temp file for IBM intermediate certificate - %v", err))
+// ValidateAttestationCertificateDocumentWithCRLs validates an attestation certificate document like
+// ValidateAttestationCertificateDocument, but takes CRLs from a CRL store (such as a CRLSnapshot)
+// instead of downloading them, so the validation can run offline.
+func ValidateAttestationCertificateDocumentWithCRLs(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, crls CRLStore) (bool, string, error) {
+	if crls == nil {
+		return false, "", fmt.Errorf("required parameter is missing")
 	}
-	defer gen.RemoveTempFile(ibmIntermediatePath)
+	return validateCertificateDocument(
+		attestationCert,
+		ibmIntermediateCert,
+		digicertIntermediateCert,
+		digicertRootCert,
+		"attestation",
+		crls,
+		time.Now(),
+	)
+}
 
-	digicertIntermediatePath, err := gen.CreateTempFile(digicertIntermediateCert)
-	if err != nil {
-		return false, "", stageError(stageCAVerify, fmt.Sprintf("failed to create temp file for DigiCert intermediate certificate - %v", err))
-	}
-	defer gen.RemoveTempFile(digicertIntermediatePath)
+// ValidateEncryptionCertificateDocumentAt validates an encryption certificate document like
+// ValidateEncryptionCertificateDocument, checking certificate and CRL validity windows at the given
+// time, e.g. a planned deployment date.
+//
+// Parameters:
+//   - encryptionCert: PEM-formatted encryption certificate document
+//   - ibmIntermediateCert: PEM-formatted IBM intermediate certificate
+//   - digicertIntermediateCert: PEM-formatted DigiCert intermediate certificate
+//   - digicertRootCert: PEM-formatted DigiCert root certificate
+//   - crls: CRL store to take CRLs from; CRLs are downloaded if nil
+//   - at: Time the document is validated at; the current time if zero
+//
+// Returns:
+//   - true if the document is valid
+//   - Validation message
+//   - Error naming the failed validation stage
+func ValidateEncryptionCertificateDocumentAt(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, crls CRLStore, at time.Time) (bool, string, error) {
+	return validateCertificateDocument(
+		encryptionCert,
+		ibmIntermediateCert,
+		digicertIntermediateCert,
+		digicertRootCert,
+		"encryption",
+		crls,
+		at,
+	)
+}
 
-	digicertRootPath, err := gen.CreateTempFile(digicertRootCert)
-	if err != nil {
-		return false, "", stageError(stageCAVerify, fmt.Sprintf("failed to create temp file for DigiCert root certificate - %v", err))
-	}
-	defer gen.RemoveTempFile(digicertRootPath)
+// ValidateAttestationCertificateDocumentAt validates an attestation certificate document like
+// ValidateAttestationCertificateDocument, checking certificate and CRL validity windows at the given time.
+//
+// Parameters:
+//   - attestationCert: PEM-formatted attestation certificate document
+//   - ibmIntermediateCert: PEM-formatted IBM intermediate certificate
+//   - digicertIntermediateCert: PEM-formatted DigiCert intermediate certificate
+//   - digicertRootCert: PEM-formatted DigiCert root certificate
+//   - crls: CRL store to take CRLs from; CRLs are downloaded if nil
+//   - at: Time the document is validated at; the current time if zero
+//
+// Returns:
+//   - true if the document is valid
+//   - Validation message
+//   - Error naming the failed validation stage
+func ValidateAttestationCertificateDocumentAt(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, crls CRLStore, at time.Time) (bool, string, error) {
+	return validateCertificateDocument(
+		attestationCert,
+		ibmIntermediateCert,
+		digicertIntermediateCert,
+		digicertRootCert,
+		"attestation",
+		crls,
+		at,
+	)
+}
 
-	// Step 1: Verify DigiCert intermediate certificate.
-	if err := verifyCertificateWithCRLFallback(digicertIntermediatePath, digicertRootPath, "", stageCAVerify); err != nil {
-		return false, "", err
+// validateCertificateDocument runs the shared validation flow for encryption or attestation documents
+// at the given time. CRLs are downloaded when crls is nil and taken from the store otherwise.
+func validateCertificateDocument(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, certType string, crls CRLStore, at time.Time) (bool, string, error) {
+	if gen.CheckIfEmpty(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
+		return false, "", fmt.Errorf("required parameter is missing")
 	}
 
-	// Step 2: Verify IBM intermediate certificate.
-	if err := verifyCertificateWithCRLFallback(ibmIntermediatePath, digicertRootPath, digicertIntermediatePath, stageSigningCertVerify); err != nil {
+	report := newValidationReport(at)
+	report.validateDocument(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, certType, crls)
+	if err := report.Err(); err != nil {
 		return false, "", err
 	}
 
-	// Step 3: Verify certificate document signature using IBM intermediate public key.
-	if err := verifyDocumentSignature(targetCertPath, ibmIntermediatePath); err != nil {
-		return false, "", stageError(stageDocSignatureVerify, err.Error())
-	}
-
-	// Step 4: Verify validity dates.
-	if err := validateCertificateDateWindow(targetCertPath); err != nil {
-		return false, "", stageError(stageDateVerify, err.Error())
-	}
-
 	return true, fmt.Sprintf("%s certificate document is valid", certType), nil
 }
 
 // ValidateCertificateRevocationList validates CRL metadata/signature and checks
 // revocation status for a certificate document (encryption or attestation).
 func ValidateCertificateRevocationList(certificateDocument, ibmIntermediateCert string) (bool, string, error) {
-	if gen.CheckIfEmpty(certificateDocument, ibmIntermediateCert) {
-		return false, "", fmt.Errorf("required parameter is missing")
-	}
-
-	certificatePath, err := gen.CreateTempFile(certificateDocument)
-	if err != nil {
-		return false, "", stageError(stageCRLSignatureVerify, fmt.Sprintf("failed to create temp file for certificate - %v", err))
-	}
-	defer gen.RemoveTempFile(certificatePath)
-
-	ibmIntermediatePath, err := gen.CreateTempFile(ibmIntermediateCert)
-	if err != nil {
-		return false, "", stageError(stageCRLSignatureVerify, fmt.Sprintf("failed to create temp file for IBM intermediate certificate - %v", err))
-	}
-	defer gen.RemoveTempFile(ibmIntermediatePath)
-
-	crlURL, err := extractCRLDistributionPointFromCertificate(certificatePath)
-	if err != nil {
-		return false, "", stageError(stageCRLSignatureVerify, fmt.Sprintf("failed to extract CRL URL - %v", err))
-	}
-
-	crlPath, err := downloadCRLFromURLToTempFile(crlURL)
-	if err != nil {
-		return false, "", stageError(stageCRLSignatureVerify, fmt.Sprintf("failed to download CRL - %v", err))
-	}
-	defer gen.RemoveTempFile(crlPath)
+	return validateCertificateRevocationList(certificateDocument, ibmIntermediateCert, nil, time.Now())
+}
 
-	crlText, err := validateCRLMetadata(crlPath)
-	if err != nil {
-		return false, "", stageError(stageCRLSignatureVerify, err.Error())
+// ValidateCertificateRevocationListWithCRLs validates a certificate document against the CRL from a
+// CRL store instead of downloading it on every validation.
+func ValidateCertificateRevocationListWithCRLs(certificateDocument, ibmIntermediateCert string, crls CRLStore) (bool, string, error) {
+	if crls == nil {
+		return false, "", fmt.Errorf("required parameter is missing")
 	}
+	return validateCertificateRevocationList(certificateDocument, ibmIntermediateCert, crls, time.Now())
+}
 
-	if err := verifyCRLSignature(crlPath, ibmIntermediatePath); err != nil {
-		return false, "", stageError(stageCRLSignatureVerify, err.Error())
-	}
+// ValidateCertificateRevocationListAt validates a certificate document against its CRL like
+// ValidateCertificateRevocationList, checking the CRL validity window at the given time.
+//
+// Parameters:
+//   - certificateDocument: PEM-formatted encryption or attestation certificate
+//   - ibmIntermediateCert: PEM-formatted IBM intermediate certificate that signs the CRL
+//   - crls: CRL store to take the CRL from; the CRL is downloaded if nil
+//   - at: Time the CRL is validated at; the current time if zero
+//
+// Returns:
+//   - true if the CRL is valid and the certificate is not revoked
+//   - Validation message
+//   - Error naming the failed validation stage
+func ValidateCertificateRevocationListAt(certificateDocument, ibmIntermediateCert string, crls CRLStore, at time.Time) (bool, string, error) {
+	return validateCertificateRevocationList(certificateDocument, ibmIntermediateCert, crls, at)
+}
 
-	serial, err := extractCertificateSerial(certificatePath)
-	if err != nil {
-		return false, "", stageError(stageSerialRevoked, fmt.Sprintf("failed to extract certificate serial - %v", err))
+// validateCertificateRevocationList runs the CRL validation at the given time, downloading the CRL when crls is nil.
+func validateCertificateRevocationList(certificateDocument, ibmIntermediateCert string, crls CRLStore, at time.Time) (bool, string, error) {
+	if gen.CheckIfEmpty(certificateDocument, ibmIntermediateCert) {
+		return false, "", fmt.Errorf("required parameter is missing")
 	}
 
-	if isCertificateRevoked(crlText, serial) {
-		return false, "", stageError(stageSerialRevoked, "certificate is listed in CRL")
+	report := newValidationReport(at)
+	report.validateRevocation(certificateDocument, ibmIntermediateCert, crls)
+	if err := report.Err(); err != nil {
+		return false, "", err
 	}
 
 	return true, "CRL is valid and certificate is not revoked", nil
 }
 
-// verifyCertificateWithCRLFallback verifies a certificate and falls back to manual CRL download/check when needed.
-func verifyCertificateWithCRLFallback(certPath, rootCertPath, untrustedCertPath, stage string) error {
-	args := []string{"verify", "-crl_download", "-crl_check", "-CAfile", rootCertPath}
-	if untrustedCertPath != "" {
-		args = append(args, "-untrusted", untrustedCertPath)
-	}
-	args = append(args, certPath)
-
-	verifyOutput, verifyErr := runOpenSSLCommand(args...)
-	if verifyErr == nil && strings.Contains(verifyOutput, "OK") {
-		return nil
-	}
+// verifyChain verifies that certificate chains up to root, optionally through intermediate, at the given time.
+// Any extended key usage is accepted, as certificate documents are not TLS certificates.
+func verifyChain(certificate, root, intermediate *x509.Certificate, at time.Time) ([]*x509.Certificate, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
 
-	crlURL, err := extractCRLDistributionPointFromCertificate(certPath)
-	if err != nil {
-		if verifyErr != nil {
-			return stageError(stage, fmt.Sprintf("%s (manual fallback setup failed: %v)", verifyOutput, err))
-		}
-		return stageError(stage, fmt.Sprintf("manual fallback setup failed: %v", err))
+	intermediates := x509.NewCertPool()
+	if intermediate != nil {
+		intermediates.AddCert(intermediate)
 	}
 
-	crlPath, err := downloadCRLFromURLToTempFile(crlURL)
+	chains, err := certificate.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
 	if err != nil {
-		return stageError(stage, fmt.Sprintf("failed to download CRL for manual fallback - %v", err))
-	}
-	defer gen.RemoveTempFile(crlPath)
-
-	fallbackArgs := []string{"verify", "-CAfile", rootCertPath}
-	if untrustedCertPath != "" {
-		fallbackArgs = append(fallbackArgs, "-untrusted", untrustedCertPath)
+		return nil, err
 	}
-	fallbackArgs = append(fallbackArgs, "-CRLfile", crlPath, "-crl_check", certPath)
 
-	fallbackOutput, fallbackErr := runOpenSSLCommand(fallbackArgs...)
-	if fallbackErr != nil {
-		return stageError(stage, fallbackOutput)
-	}
-	if !strings.Contains(fallbackOutput, "OK") {
-		return stageError(stage, fallbackOutput)
-	}
-
-	return nil
+	return chains[0], nil
 }
 
-// verifyDocumentSignature verifies the certificate document signature using IBM intermediate public key and ASN.1 offsets.
-func verifyDocumentSignature(documentPath, ibmIntermediatePath string) error {
-	pubKeyPath, err := createEmptyTempFilePath()
+// verifyCertificateWithCRL verifies a certificate chain and checks the certificate against the CRL
+// published by its issuer. With a CRL store, the CRL is taken from the store. The CRL used is returned
+// once its URL is known.
+func verifyCertificateWithCRL(certificate, root, intermediate *x509.Certificate, at time.Time, crls CRLStore) (*CRLInfo, error) {
+	chain, err := verifyChain(certificate, root, intermediate, at)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file for public key - %v", err)
-	}
-	defer gen.RemoveTempFile(pubKeyPath)
-
-	if _, err := runOpenSSLCommand("x509", "-in", ibmIntermediatePath, "-pubkey", "-noout", "-out", pubKeyPath); err != nil {
-		return fmt.Errorf("failed to extract public key from IBM intermediate certificate - %v", err)
+		return nil, fmt.Errorf("%s: %v", certificate.Subject.CommonName, err)
 	}
 
-	asn1Output, err := runOpenSSLCommand("asn1parse", "-in", documentPath)
+	crlURL, err := crlDistributionPoint(certificate)
 	if err != nil {
-		return fmt.Errorf("failed to parse ASN.1 certificate document - %v", err)
+		return nil, fmt.Errorf("%s: %v", certificate.Subject.CommonName, err)
 	}
 
-	signatureOffset, err := getLastASN1Offset(asn1Output)
+	crlInfo := &CRLInfo{URL: crlURL}
+	crlData, err := fetchCRL(crlURL, crls, at)
 	if err != nil {
-		return fmt.Errorf("failed to extract signature offset - %v", err)
+		return crlInfo, fmt.Errorf("failed to download CRL - %v", err)
 	}
 
-	signaturePath, err := createEmptyTempFilePath()
+	crl, err := parseCRL(crlData)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file for signature - %v", err)
+		return crlInfo, fmt.Errorf("failed to parse CRL - %v", err)
 	}
-	defer gen.RemoveTempFile(signaturePath)
+	crlInfo = newCRLInfo(crlURL, crl)
 
-	bodyPath, err := createEmptyTempFilePath()
-	if err != nil {
-		return fmt.Errorf("failed to create temp file for body - %v", err)
+	if err := validateCRLWindow(crl, at); err != nil {
+		return crlInfo, err
 	}
-	defer gen.RemoveTempFile(bodyPath)
 
-	if _, err := runOpenSSLCommand(
-		"asn1parse",
-		"-in", documentPath,
-		"-out", signaturePath,
-		"-strparse", strconv.Itoa(signatureOffset),
-		"-noout",
-	); err != nil {
-		return fmt.Errorf("failed to extract signature blob - %v", err)
+	// A certificate that is itself the trust anchor forms a chain of one and issues its own CRL
+	issuer := certificate
+	if len(chain) > 1 {
+		issuer = chain[1]
+	} else if !bytes.Equal(certificate.RawIssuer, certificate.RawSubject) {
+		return crlInfo, fmt.Errorf("%s: issuer of the CRL is not in the verified chain", certificate.Subject.CommonName)
 	}
-
-	if _, err := runOpenSSLCommand(
-		"asn1parse",
-		"-in", documentPath,
-		"-out", bodyPath,
-		"-strparse", "4",
-		"-noout",
-	); err != nil {
-		return fmt.Errorf("failed to extract certificate body - %v", err)
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return crlInfo, fmt.Errorf("CRL signature verification failed - %v", err)
 	}
 
-	verifyOutput, verifyErr := runOpenSSLCommand("sha512", "-verify", pubKeyPath, "-signature", signaturePath, bodyPath)
-	if verifyErr != nil {
-		return fmt.Errorf("failed to verify signature - %v", verifyErr)
-	}
-	if !strings.Contains(verifyOutput, "Verified OK") {
-		return fmt.Errorf("signature verification did not return success: %s", verifyOutput)
+	if revoked, revokedAt := isSerialRevoked(crl, certificate.SerialNumber); revoked {
+		return crlInfo, fmt.Errorf("%s: certificate revoked at %s", certificate.Subject.CommonName, formatCertificateTime(revokedAt))
 	}
 
-	return nil
+	return crlInfo, nil
 }
 
-// validateCertificateDateWindow verifies the certificate notBefore/notAfter window against current UTC time.
-func validateCertificateDateWindow(certPath string) error {
-	datesOutput, err := runOpenSSLCommand("x509", "-in", certPath, "-dates", "-noout")
-	if err != nil {
-		return fmt.Errorf("failed to read certificate dates - %v", err)
-	}
-
-	var notBeforeRaw string
-	var notAfterRaw string
-	for _, line := range strings.Split(datesOutput, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "notBefore=") {
-			notBeforeRaw = strings.TrimPrefix(line, "notBefore=")
-		}
-		if strings.HasPrefix(line, "notAfter=") {
-			notAfterRaw = strings.TrimPrefix(line, "notAfter=")
-		}
-	}
-
-	if notBeforeRaw == "" || notAfterRaw == "" {
-		return fmt.Errorf("unable to parse notBefore/notAfter from certificate dates output")
-	}
-
-	notBefore, err := time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimSpace(notBeforeRaw))
-	if err != nil {
-		return fmt.Errorf("failed to parse notBefore date - %v", err)
-	}
-	notAfter, err := time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimSpace(notAfterRaw))
-	if err != nil {
-		return fmt.Errorf("failed to parse notAfter date - %v", err)
+// validationTime returns at, or the current time if at is zero.
+func validationTime(at time.Time) time.Time {
+	if at.IsZero() {
+		return time.Now()
 	}
+	return at
+}
 
-	now := time.Now().UTC()
-	if now.Before(notBefore.UTC()) {
-		return fmt.Errorf("certificate is not yet valid (validity starts at %s)", strings.TrimSpace(notBeforeRaw))
+// validateCertificateDateWindow verifies the certificate notBefore/notAfter window against the given time.
+func validateCertificateDateWindow(certificate *x509.Certificate, at time.Time) error {
+	if at.Before(certificate.NotBefore) {
+		return fmt.Errorf("certificate is not yet valid (validity starts at %s)", formatCertificateTime(certificate.NotBefore))
 	}
-	if now.After(notAfter.UTC()) {
-		return fmt.Errorf("certificate has expired (expired at %s)", strings.TrimSpace(notAfterRaw))
+	if at.After(certificate.NotAfter) {
+		return fmt.Errorf("certificate has expired (expired at %s)", formatCertificateTime(certificate.NotAfter))
 	}
-
 	return nil
 }
 
-// validateCRLMetadata validates CRL issuer metadata and update window, and returns CRL text output.
-func validateCRLMetadata(crlPath string) (string, error) {
-	crlTextOutput, err := runCRLCommandWithDERFallback("-text", "-noout", "-in", crlPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse CRL text - %v", err)
+// validateCRLWindow verifies the CRL thisUpdate/nextUpdate window against the given time.
+func validateCRLWindow(crl *x509.RevocationList, at time.Time) error {
+	if crl.ThisUpdate.IsZero() || crl.NextUpdate.IsZero() {
+		return fmt.Errorf("CRL validity window is incomplete")
 	}
-	if !strings.Contains(crlTextOutput, "Issuer:") {
-		return "", fmt.Errorf("CRL output does not contain issuer information")
+	if at.Before(crl.ThisUpdate) {
+		return fmt.Errorf("CRL is not yet valid (lastUpdate=%s)", formatCertificateTime(crl.ThisUpdate))
 	}
-	if !strings.Contains(crlTextOutput, "Last Update:") || !strings.Contains(crlTextOutput, "Next Update:") {
-		return "", fmt.Errorf("CRL output does not contain update timestamps")
-	}
-
-	dateOutput, err := runCRLCommandWithDERFallback("-noout", "-lastupdate", "-nextupdate", "-in", crlPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse CRL validity window - %v", err)
+	if at.After(crl.NextUpdate) {
+		return fmt.Errorf("CRL has expired (nextUpdate=%s)", formatCertificateTime(crl.NextUpdate))
 	}
+	return nil
+}
 
-	var lastUpdateRaw string
-	var nextUpdateRaw string
-	for _, line := range strings.Split(dateOutput, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "lastUpdate=") {
-			lastUpdateRaw = strings.TrimPrefix(line, "lastUpdate=")
+// isSerialRevoked reports whether serial is listed in the CRL and when it was revoked.
+func isSerialRevoked(crl *x509.RevocationList, serial *big.Int) (bool, time.Time) {
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber != nil && entry.SerialNumber.Cmp(serial) == 0 {
+			return true, entry.RevocationTime
 		}
-		if strings.HasPrefix(line, "nextUpdate=") {
-			nextUpdateRaw = strings.TrimPrefix(line, "nextUpdate=")
-		}
-	}
-
-	if lastUpdateRaw == "" || nextUpdateRaw == "" {
-		return "", fmt.Errorf("CRL validity window is incomplete")
-	}
-
-	lastUpdate, err := time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimSpace(lastUpdateRaw))
-	if err != nil {
-		return "", fmt.Errorf("failed to parse CRL lastUpdate - %v", err)
-	}
-	nextUpdate, err := time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimSpace(nextUpdateRaw))
-	if err != nil {
-		return "", fmt.Errorf("failed to parse CRL nextUpdate - %v", err)
-	}
-
-	now := time.Now().UTC()
-	if now.Before(lastUpdate.UTC()) {
-		return "", fmt.Errorf("CRL is not yet valid (lastUpdate=%s)", strings.TrimSpace(lastUpdateRaw))
 	}
-	if now.After(nextUpdate.UTC()) {
-		return "", fmt.Errorf("CRL has expired (nextUpdate=%s)", strings.TrimSpace(nextUpdateRaw))
-	}
-
-	return crlTextOutput, nil
+	return false, time.Time{}
 }
 
-// verifyCRLSignature verifies a CRL signature by extracting ASN.1 body/signature sections and checking with SHA-512.
-func verifyCRLSignature(crlPath, ibmIntermediatePath string) error {
-	pubKeyPath, err := createEmptyTempFilePath()
-	if err != nil {
-		return fmt.Errorf("failed to create temp file for CRL verification public key - %v", err)
-	}
-	defer gen.RemoveTempFile(pubKeyPath)
-
-	if _, err := runOpenSSLCommand("x509", "-in", ibmIntermediatePath, "-pubkey", "-noout", "-out", pubKeyPath); err != nil {
-		return fmt.Errorf("failed to extract public key from IBM intermediate certificate - %v", err)
-	}
-
-	asn1Output, useDER, err := runASN1ParseWithDERFallback(crlPath)
-	if err != nil {
-		return fmt.Errorf("failed to parse CRL ASN.1 structure - %v", err)
-	}
-
-	bodyBeginOffset, err := getSecondASN1Offset(asn1Output)
-	if err != nil {
-		return fmt.Errorf("failed to extract CRL body offset - %v", err)
-	}
-	signatureOffset, err := getLastASN1Offset(asn1Output)
-	if err != nil {
-		return fmt.Errorf("failed to extract CRL signature offset - %v", err)
-	}
-
-	signaturePath, err := createEmptyTempFilePath()
-	if err != nil {
-		return fmt.Errorf("failed to create temp file for CRL signature - %v", err)
-	}
-	defer gen.RemoveTempFile(signaturePath)
-
-	bodyPath, err := createEmptyTempFilePath()
-	if err != nil {
-		return fmt.Errorf("failed to create temp file for CRL body - %v", err)
-	}
-	defer gen.RemoveTempFile(bodyPath)
-
-	signatureArgs := []string{"asn1parse"}
-	if useDER {
-		signatureArgs = append(signatureArgs, "-inform", "DER")
+// parseCertificatePEM parses the first PEM encoded certificate.
+func parseCertificatePEM(certificatePEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(certificatePEM)))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
 	}
-	signatureArgs = append(signatureArgs,
-		"-in", crlPath,
-		"-out", signaturePath,
-		"-strparse", strconv.Itoa(signatureOffset),
-		"-noout",
-	)
-	if _, err := runOpenSSLCommand(signatureArgs...); err != nil {
-		return fmt.Errorf("failed to extract CRL signature blob - %v", err)
+	if block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
 	}
+	return x509.ParseCertificate(block.Bytes)
+}
 
-	bodyArgs := []string{"asn1parse"}
-	if useDER {
-		bodyArgs = append(bodyArgs, "-inform", "DER")
-	}
-	bodyArgs = append(bodyArgs,
-		"-in", crlPath,
-		"-out", bodyPath,
-		"-strparse", strconv.Itoa(bodyBeginOffset),
-		"-noout",
-	)
-	if _, err := runOpenSSLCommand(bodyArgs...); err != nil {
-		return fmt.Errorf("failed to extract CRL body blob - %v", err)
+// parseCRL parses a PEM or DER encoded CRL.
+func parseCRL(data []byte) (*x509.RevocationList, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "X509 CRL" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		data = block.Bytes
 	}
+	return x509.ParseRevocationList(data)
+}
 
-	verifyOutput, verifyErr := runOpenSSLCommand("sha512", "-verify", pubKeyPath, "-signature", signaturePath, bodyPath)
-	if verifyErr != nil {
-		return fmt.Errorf("failed to verify CRL signature - %v", verifyErr)
-	}
-	if !strings.Contains(verifyOutput, "Verified OK") {
-		return fmt.Errorf("CRL signature verification did not return success: %s", verifyOutput)
+// crlDistributionPoint returns the first CRL distribution point URI of a certificate.
+func crlDistributionPoint(certificate *x509.Certificate) (string, error) {
+	if len(certificate.CRLDistributionPoints) == 0 {
+		return "", fmt.Errorf("CRL distribution point URI not found")
 	}
-
-	return nil
+	return certificate.CRLDistributionPoints[0], nil
 }
 
-// extractCRLDistributionPointFromCertificate extracts the first CRL Distribution Point URI from a certificate.
-func extractCRLDistributionPointFromCertificate(certPath string) (string, error) {
-	output, err := runOpenSSLCommand("x509", "-in", certPath, "-noout", "-ext", "crlDistributionPoints")
-	if err != nil {
-		return "", fmt.Errorf("failed to read CRL distribution points - %v", err)
+// fetchCRL returns the CRL published at crlURL. The CRL is taken from the store when one is given
+// and downloaded otherwise; a CRLStoreAt is asked for the CRL current at the validation time.
+func fetchCRL(crlURL string, crls CRLStore, at time.Time) ([]byte, error) {
+	if crls == nil {
+		crls = CRLDownloader{}
 	}
-
-	matches := crlURIRegex.FindStringSubmatch(output)
-	if len(matches) != 2 {
-		return "", fmt.Errorf("CRL distribution point URI not found")
+	if store, ok := crls.(CRLStoreAt); ok {
+		return store.CRLAt(crlURL, at)
 	}
-
-	return strings.TrimSpace(matches[1]), nil
+	return crls.CRL(crlURL)
 }
 
-// downloadCRLFromURLToTempFile downloads CRL content from http/https/file URL and stores it in a temporary binary file.
-func downloadCRLFromURLToTempFile(crlURL string) (string, error) {
+// downloadCRLFromURL downloads CRL content from an http/https/file URL.
+// HTTP downloads use the given configuration.
+func downloadCRLFromURL(ctx context.Context, crlURL string, config gen.DownloadConfig) ([]byte, error) {
 	parsedURL, err := url.Parse(crlURL)
 	if err != nil {
-		return "", fmt.Errorf("invalid CRL URL - %v", err)
+		return nil, fmt.Errorf("invalid CRL URL - %v", err)
 	}
 
 	var data []byte
 	switch parsedURL.Scheme {
 	case "http", "https":
-		client := &http.Client{Timeout: opensslCommandTimeout}
-		resp, err := client.Get(crlURL)
+		crl, err := gen.CertificateDownloaderContext(ctx, crlURL, config)
 		if err != nil {
-			return "", fmt.Errorf("failed to download CRL - %v", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-			return "", fmt.Errorf("failed to download CRL - unexpected HTTP status: %s", resp.Status)
-		}
-		data, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return "", fmt.Errorf("failed to read CRL response body - %v", err)
+			return nil, fmt.Errorf("failed to download CRL - %v", err)
 		}
+		data = []byte(crl)
 	case "file":
 		filePath := parsedURL.Path
 		if filePath == "" {
-			return "", fmt.Errorf("invalid file URL for CRL")
+			return nil, fmt.Errorf("invalid file URL for CRL")
 		}
 		data, err = os.ReadFile(filePath)
 		if err != nil {
-			return "", fmt.Errorf("failed to read CRL from file URL - %v", err)
+			return nil, fmt.Errorf("failed to read CRL from file URL - %v", err)
 		}
 	default:
-		return "", fmt.Errorf("unsupported CRL URL scheme: %s", parsedURL.Scheme)
+		return nil, fmt.Errorf("unsupported CRL URL scheme: %s", parsedURL.Scheme)
 	}
 
 	if len(data) == 0 {
-		return "", fmt.Errorf("downloaded CRL is empty")
-	}
-
-	crlPath, err := gen.CreateTempBinaryFile(data)
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp file for CRL - %v", err)
-	}
-	return crlPath, nil
-}
-
-// extractCertificateSerial extracts and normalizes the certificate serial number from OpenSSL output.
-func extractCertificateSerial(certPath string) (string, error) {
-	output, err := runOpenSSLCommand("x509", "-in", certPath, "-noout", "-serial")
-	if err != nil {
-		return "", fmt.Errorf("failed to extract certificate serial - %v", err)
-	}
-
-	for _, line := range strings.Split(output, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(strings.ToLower(line), "serial=") {
-			serial := strings.TrimSpace(strings.SplitN(line, "=", 2)[1])
-			if serial == "" {
-				break
-			}
-			return normalizeSerial(serial), nil
-		}
-	}
-	return "", fmt.Errorf("serial not found in certificate output")
-}
-
-// isCertificateRevoked checks whether a normalized serial exists in the CRL text.
-func isCertificateRevoked(crlText, serial string) bool {
-	target := normalizeSerial(serial)
-	if target == "" {
-		return false
-	}
-
-	matches := serialRegex.FindAllStringSubmatch(crlText, -1)
-	for _, match := range matches {
-		if len(match) != 2 {
-			continue
-		}
-		if normalizeSerial(match[1]) == target {
-			return true
-		}
-	}
-
-	return strings.Contains(normalizeSerial(crlText), target)
-}
-
-// normalizeSerial removes non-hex characters and uppercases the serial for comparison.
-func normalizeSerial(value string) string {
-	var builder strings.Builder
-	upper := strings.ToUpper(strings.TrimSpace(value))
-	for _, r := range upper {
-		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'F') {
-			builder.WriteRune(r)
-		}
-	}
-	return builder.String()
-}
-
-// getLastASN1Offset returns the final ASN.1 node offset from openssl asn1parse output.
-func getLastASN1Offset(asn1Output string) (int, error) {
-	lines := strings.Split(asn1Output, "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-		match := asn1OffsetPattern.FindStringSubmatch(line)
-		if len(match) != 2 {
-			continue
-		}
-		offset, err := strconv.Atoi(match[1])
-		if err != nil {
-			return 0, err
-		}
-		return offset, nil
-	}
-	return 0, fmt.Errorf("unable to determine ASN.1 offset from output")
-}
-
-// getSecondASN1Offset returns the second ASN.1 node offset from openssl asn1parse output.
-func getSecondASN1Offset(asn1Output string) (int, error) {
-	lines := strings.Split(asn1Output, "\n")
-	offsets := make([]int, 0, 2)
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		match := asn1OffsetPattern.FindStringSubmatch(line)
-		if len(match) != 2 {
-			continue
-		}
-		offset, err := strconv.Atoi(match[1])
-		if err != nil {
-			return 0, err
-		}
-		offsets = append(offsets, offset)
-		if len(offsets) == 2 {
-			return offsets[1], nil
-		}
-	}
-	return 0, fmt.Errorf("unable to determine second ASN.1 offset from output")
-}
-
-// createEmptyTempFilePath creates and closes an empty temporary file, returning its path.
-func createEmptyTempFilePath() (string, error) {
-	tmpFile, err := os.CreateTemp("", gen.TempFolderNamePrefix)
-	if err != nil {
-		return "", err
-	}
-	defer tmpFile.Close()
-	return tmpFile.Name(), nil
-}
-
-// runOpenSSLCommand executes an OpenSSL command with timeout and returns combined output.
-func runOpenSSLCommand(args ...string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), opensslCommandTimeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, gen.GetOpenSSLPath(), args...)
-	output, err := cmd.CombinedOutput()
-	trimmedOutput := strings.TrimSpace(string(output))
-
-	if ctx.Err() == context.DeadlineExceeded {
-		if trimmedOutput == "" {
-			trimmedOutput = "openssl command timed out"
-		}
-		return trimmedOutput, errors.New(trimmedOutput)
-	}
-
-	if err != nil {
-		if trimmedOutput == "" {
-			trimmedOutput = err.Error()
-		}
-		return trimmedOutput, errors.New(trimmedOutput)
-	}
-
-	return trimmedOutput, nil
-}
-
-// runCRLCommandWithDERFallback runs openssl crl and retries with DER input when parsing fails.
-func runCRLCommandWithDERFallback(args ...string) (string, error) {
-	baseArgs := append([]string{"crl"}, args...)
-	output, err := runOpenSSLCommand(baseArgs...)
-	if err == nil {
-		return output, nil
-	}
-
-	withDER := append([]string{"crl", "-inform", "DER"}, args...)
-	outputDER, errDER := runOpenSSLCommand(withDER...)
-	if errDER == nil {
-		return outputDER, nil
+		return nil, fmt.Errorf("downloaded CRL is empty")
 	}
 
-	if strings.TrimSpace(outputDER) != "" {
-		return outputDER, errDER
-	}
-	return output, errDER
+	return data, nil
 }
 
-// runASN1ParseWithDERFallback runs openssl asn1parse and retries with DER input when parsing fails.
-func runASN1ParseWithDERFallback(filePath string) (string, bool, error) {
-	output, err := runOpenSSLCommand("asn1parse", "-in", filePath)
-	if err == nil {
-		return output, false, nil
-	}
-
-	outputDER, errDER := runOpenSSLCommand("asn1parse", "-inform", "DER", "-in", filePath)
-	if errDER == nil {
-		return outputDER, true, nil
-	}
-
-	if strings.TrimSpace(outputDER) != "" {
-		return outputDER, false, errDER
-	}
-	return output, false, errDER
+// formatCertificateTime formats a certificate or CRL timestamp in UTC.
+func formatCertificateTime(t time.Time) string {
+	return t.UTC().Format(certificateTimeLayout)
 }
 
 // stageError wraps an error detail with a validation stage prefix.
@@ -784,7 +500,8 @@ func stageError(stage, detail string) error {
 }
 
 // CheckCertificateRevocation checks if a certificate has been revoked using a CRL.
-// It uses OpenSSL verify command with the -CRLfile option to check revocation status.
+// The CRL may be PEM or DER encoded. The CRL signature is not verified; use
+// ValidateCertificateRevocationList to also verify the CRL issuer.
 //
 // Parameters:
 //   - certPEM: PEM-formatted certificate to check
@@ -799,36 +516,21 @@ func CheckCertificateRevocation(certPEM, crlPEM string) (bool, string, error) {
 		return false, "", fmt.Errorf("required parameter is missing")
 	}
 
-	// Create temporary files
-	certPath, err := gen.CreateTempFile(certPEM)
-	if err != nil {
-		return false, "", fmt.Errorf("failed to create temp file for certificate - %v", err)
-	}
-	defer gen.RemoveTempFile(certPath)
-
-	crlPath, err := gen.CreateTempFile(crlPEM)
+	certificate, err := parseCertificatePEM(certPEM)
 	if err != nil {
-		return false, "", fmt.Errorf("failed to create temp file for CRL - %v", err)
+		return false, "", fmt.Errorf("failed to parse certificate - %v", err)
 	}
-	defer gen.RemoveTempFile(crlPath)
-
-	result, err := gen.ExecCommand(gen.GetOpenSSLPath(), "", "verify", "-crl_check", "-CRLfile", crlPath, certPath)
 
+	crl, err := parseCRL([]byte(crlPEM))
 	if err != nil {
-		// Check if error indicates revocation
-		errMsg := strings.TrimSpace(result)
-		if strings.Contains(strings.ToLower(errMsg), "revoked") {
-			return true, fmt.Sprintf("Certificate is revoked: %s", errMsg), nil
-		}
-		return false, "", fmt.Errorf("CRL check failed - %v", errMsg)
+		return false, "", fmt.Errorf("CRL check failed - %v", err)
 	}
 
-	// Certificate is not revoked
-	if strings.Contains(result, "OK") {
-		return false, "Certificate is not revoked", nil
+	if revoked, revokedAt := isSerialRevoked(crl, certificate.SerialNumber); revoked {
+		return true, fmt.Sprintf("Certificate is revoked: serial %s revoked at %s", certificate.SerialNumber.Text(16), formatCertificateTime(revokedAt)), nil
 	}
 
-	return false, strings.TrimSpace(result), nil
+	return false, "Certificate is not revoked", nil
 }
 
 // DownloadCRL downloads a Certificate Revocation List from the specified URL.
@@ -841,14 +543,87 @@ func CheckCertificateRevocation(certPEM, crlPEM string) (bool, string, error) {
 //   - PEM-formatted CRL data
 //   - Error if download fails
 func DownloadCRL(crlURL string) (string, error) {
+	return DownloadCRLContext(context.Background(), crlURL, gen.DownloadConfig{})
+}
+
+// DownloadCRLContext downloads a Certificate Revocation List from the specified URL using the given
+// download configuration (HTTP client, retries and user agent).
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the download
+//   - crlURL: URL of the CRL to download
+//   - config: Download configuration
+//
+// Returns:
+//   - PEM-formatted CRL data
+//   - Error if download fails
+func DownloadCRLContext(ctx context.Context, crlURL string, config gen.DownloadConfig) (string, error) {
 	if gen.CheckIfEmpty(crlURL) {
 		return "", fmt.Errorf("required parameter is missing")
 	}
 
-	crlData, err := gen.CertificateDownloader(crlURL)
+	crlData, err := gen.CertificateDownloaderContext(ctx, crlURL, config)
 	if err != nil {
 		return "", fmt.Errorf("failed to download CRL - %v", err)
 	}
 
 	return crlData, nil
 }
+
+// SnapshotCRLs downloads the CRL referenced by the CRL distribution point of each certificate.
+// The snapshot can be stored and later passed to the WithCRLs validation functions to validate offline.
+//
+// Parameters:
+//   - certificates: PEM-formatted certificates whose CRLs should be captured
+//
+// Returns:
+//   - CRL content keyed by distribution point URL
+//   - Error if a certificate has no CRL distribution point or a download fails
+func SnapshotCRLs(certificates ...string) (CRLSnapshot, error) {
+	return SnapshotCRLsContext(context.Background(), gen.DownloadConfig{}, certificates...)
+}
+
+// SnapshotCRLsContext is SnapshotCRLs with a context and download configuration for the CRL downloads.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the downloads
+//   - config: Download configuration
+//   - certificates: PEM-formatted certificates whose CRLs should be captured
+//
+// Returns:
+//   - CRL content keyed by distribution point URL
+//   - Error if a certificate has no CRL distribution point or a download fails
+func SnapshotCRLsContext(ctx context.Context, config gen.DownloadConfig, certificates ...string) (CRLSnapshot, error) {
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("required parameter is missing")
+	}
+
+	snapshot := CRLSnapshot{}
+	for _, certificatePEM := range certificates {
+		if gen.CheckIfEmpty(certificatePEM) {
+			return nil, fmt.Errorf("required parameter is missing")
+		}
+
+		certificate, err := parseCertificatePEM(certificatePEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate - %v", err)
+		}
+
+		crlURL, err := crlDistributionPoint(certificate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract CRL URL - %v", err)
+		}
+
+		if _, ok := snapshot[crlURL]; ok {
+			continue
+		}
+
+		data, err := downloadCRLFromURL(ctx, crlURL, config)
+		if err != nil {
+			return nil, err
+		}
+		snapshot[crlURL] = data
+	}
+
+	return snapshot, nil
+}
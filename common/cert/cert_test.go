@@ -16,10 +16,6 @@
 package cert
 
 import (
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"crypto/x509/pkix"
 	"encoding/pem"
 	"math/big"
 	"net/url"
@@ -29,6 +25,7 @@ import (
 	"time"
 
 	"github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	"github.com/ibm-hyper-protect/contract-go/v2/internal/testpki"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -60,6 +57,7 @@ type docFixtureOptions struct {
 	invalidCRLSignature     bool
 	missingEncryptionCRLDP  bool
 	missingAttestationCRLDP bool
+	revokeIbmIntermediate   bool
 }
 
 type docValidationFixture struct {
@@ -194,22 +192,22 @@ func TestValidateEncryptionCertificateDocument(t *testing.T) {
 			mutate: func(f *docValidationFixture) {
 				f.digicertRootCert = f.encryptionCert
 			},
-			errorStage: stageCAVerify,
+			errorStage: StageCAVerify,
 		},
 		{
 			name:       "BadSignature",
 			opts:       docFixtureOptions{badEncryptionSignature: true},
-			errorStage: stageDocSignatureVerify,
+			errorStage: StageDocSignatureVerify,
 		},
 		{
 			name:       "Expired",
 			opts:       docFixtureOptions{expiredEncryption: true},
-			errorStage: stageDateVerify,
+			errorStage: StageDateVerify,
 		},
 		{
 			name:       "NotYetValid",
 			opts:       docFixtureOptions{futureEncryption: true},
-			errorStage: stageDateVerify,
+			errorStage: StageDateVerify,
 		},
 	}
 
@@ -258,22 +256,27 @@ func TestValidateAttestationCertificateDocument(t *testing.T) {
 			mutate: func(f *docValidationFixture) {
 				f.digicertRootCert = f.attestationCert
 			},
-			errorStage: stageCAVerify,
+			errorStage: StageCAVerify,
+		},
+		{
+			name:       "RevokedSigningCert",
+			opts:       docFixtureOptions{revokeIbmIntermediate: true},
+			errorStage: StageSigningCertVerify,
 		},
 		{
 			name:       "BadSignature",
 			opts:       docFixtureOptions{badAttestationSignature: true},
-			errorStage: stageDocSignatureVerify,
+			errorStage: StageDocSignatureVerify,
 		},
 		{
 			name:       "Expired",
 			opts:       docFixtureOptions{expiredAttestation: true},
-			errorStage: stageDateVerify,
+			errorStage: StageDateVerify,
 		},
 		{
 			name:       "NotYetValid",
 			opts:       docFixtureOptions{futureAttestation: true},
-			errorStage: stageDateVerify,
+			errorStage: StageDateVerify,
 		},
 	}
 
@@ -324,25 +327,25 @@ func TestValidateCertificateRevocationList(t *testing.T) {
 		{
 			name:            "EncryptionRevoked",
 			opts:            docFixtureOptions{revokeEncryption: true},
-			errorStage:      stageSerialRevoked,
+			errorStage:      StageSerialRevoked,
 			certificateKind: "encryption",
 		},
 		{
 			name:            "AttestationRevoked",
 			opts:            docFixtureOptions{revokeAttestation: true},
-			errorStage:      stageSerialRevoked,
+			errorStage:      StageSerialRevoked,
 			certificateKind: "attestation",
 		},
 		{
 			name:            "InvalidCRLSignature",
 			opts:            docFixtureOptions{invalidCRLSignature: true},
-			errorStage:      stageCRLSignatureVerify,
+			errorStage:      StageCRLSignatureVerify,
 			certificateKind: "encryption",
 		},
 		{
 			name:            "MalformedCRL",
 			opts:            docFixtureOptions{malformedCRL: true},
-			errorStage:      stageCRLSignatureVerify,
+			errorStage:      StageCRLSignatureVerify,
 			certificateKind: "encryption",
 		},
 		{
@@ -350,7 +353,7 @@ func TestValidateCertificateRevocationList(t *testing.T) {
 			opts: docFixtureOptions{
 				missingEncryptionCRLDP: true,
 			},
-			errorStage:      stageCRLSignatureVerify,
+			errorStage:      StageCRLSignatureVerify,
 			certificateKind: "encryption",
 		},
 		{
@@ -358,7 +361,7 @@ func TestValidateCertificateRevocationList(t *testing.T) {
 			opts: docFixtureOptions{
 				missingAttestationCRLDP: true,
 			},
-			errorStage:      stageCRLSignatureVerify,
+			errorStage:      StageCRLSignatureVerify,
 			certificateKind: "attestation",
 		},
 	}
@@ -392,6 +395,126 @@ func TestValidateCertificateRevocationList(t *testing.T) {
 	}
 }
 
+// Test SnapshotCRLs and the WithCRLs validation functions validate offline from a CRL snapshot
+func TestValidateWithCRLSnapshot(t *testing.T) {
+	fixture := newDocValidationFixture(t, docFixtureOptions{revokeEncryption: true})
+
+	crls, err := SnapshotCRLs(fixture.digicertIntermediateCert, fixture.ibmIntermediateCert, fixture.attestationCert, fixture.encryptionCert)
+	require.NoError(t, err)
+	assert.Len(t, crls, 3)
+
+	for crlURL := range crls {
+		parsedURL, err := url.Parse(crlURL)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(parsedURL.Path))
+	}
+
+	valid, msg, err := ValidateAttestationCertificateDocumentWithCRLs(fixture.attestationCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, crls)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, "attestation certificate document is valid", msg)
+
+	valid, _, err = ValidateCertificateRevocationListWithCRLs(fixture.attestationCert, fixture.ibmIntermediateCert, crls)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, _, err = ValidateCertificateRevocationListWithCRLs(fixture.encryptionCert, fixture.ibmIntermediateCert, crls)
+	assert.ErrorContains(t, err, StageSerialRevoked)
+
+	_, _, err = ValidateCertificateRevocationListWithCRLs(fixture.attestationCert, fixture.ibmIntermediateCert, CRLSnapshot{})
+	assert.ErrorContains(t, err, "is not in the snapshot")
+
+	_, _, err = ValidateCertificateRevocationListWithCRLs(fixture.attestationCert, fixture.ibmIntermediateCert, nil)
+	assert.EqualError(t, err, "required parameter is missing")
+
+	_, err = SnapshotCRLs()
+	assert.EqualError(t, err, "required parameter is missing")
+}
+
+// Test a self-signed trust anchor that publishes a CRL is checked against its own CRL
+func TestValidateSelfSignedCertificateWithCRL(t *testing.T) {
+	crlPath := filepath.Join(t.TempDir(), "self.crl")
+	crlURL := testpki.FileURL(crlPath)
+	now := time.Now().UTC()
+
+	key := testpki.MustGenerateRSAKey(t)
+	template := testpki.CATemplate(t, "Test Self-Signed CA", big.NewInt(800), now.Add(-2*time.Hour), now.Add(72*time.Hour), []string{crlURL}, nil, 1)
+	certDER := testpki.MustCreateCertificate(t, template, template, &key.PublicKey, key)
+	certPEM := testpki.PEMEncodeCert(certDER)
+	require.NoError(t, os.WriteFile(crlPath, testpki.MustCreateCRL(t, testpki.MustParseCertificate(t, certDER), key, nil, now), 0600))
+
+	crls, err := SnapshotCRLs(certPEM)
+	require.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		_, _, err = ValidateEncryptionCertificateDocumentWithCRLs(certPEM, certPEM, certPEM, certPEM, crls)
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(crlPath, testpki.MustCreateCRL(t, testpki.MustParseCertificate(t, certDER), key, []*big.Int{big.NewInt(800)}, now), 0600))
+	crls, err = SnapshotCRLs(certPEM)
+	require.NoError(t, err)
+
+	_, _, err = ValidateEncryptionCertificateDocumentWithCRLs(certPEM, certPEM, certPEM, certPEM, crls)
+	assert.ErrorContains(t, err, "certificate revoked")
+}
+
+// Test the At validation functions check validity windows at the given time
+func TestValidateCertificateDocumentAt(t *testing.T) {
+	fixture := newDocValidationFixture(t, docFixtureOptions{futureEncryption: true})
+	now := time.Now()
+
+	_, _, err := ValidateEncryptionCertificateDocumentAt(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, time.Time{})
+	assert.ErrorContains(t, err, StageDateVerify)
+
+	valid, msg, err := ValidateEncryptionCertificateDocumentAt(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, "encryption certificate document is valid", msg)
+
+	valid, _, err = ValidateAttestationCertificateDocumentAt(fixture.attestationCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, _, err = ValidateCertificateRevocationListAt(fixture.encryptionCert, fixture.ibmIntermediateCert, nil, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// The CRLs of the fixture expire after 24 hours
+	_, _, err = ValidateCertificateRevocationListAt(fixture.encryptionCert, fixture.ibmIntermediateCert, nil, now.Add(25*time.Hour))
+	assert.ErrorContains(t, err, "CRL has expired")
+
+	_, _, err = ValidateEncryptionCertificateDocumentAt(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, now.Add(25*time.Hour))
+	assert.ErrorContains(t, err, "CRL has expired")
+}
+
+// Test CheckCertificateRevocation with PEM and DER encoded CRLs
+func TestCheckCertificateRevocation(t *testing.T) {
+	now := time.Now().UTC()
+	issuerKey := testpki.MustGenerateRSAKey(t)
+	issuerTemplate := testpki.CATemplate(t, "Test Issuer", big.NewInt(100), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 0)
+	issuerCert := testpki.MustParseCertificate(t, testpki.MustCreateCertificate(t, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey))
+
+	leafKey := testpki.MustGenerateRSAKey(t)
+	leafTemplate := testpki.LeafTemplate(t, "Test Leaf", big.NewInt(4242), now, false, false, false, "", issuerCert.SubjectKeyId)
+	leafPEM := testpki.PEMEncodeCert(testpki.MustCreateCertificate(t, leafTemplate, issuerCert, &leafKey.PublicKey, issuerKey))
+
+	revokedCRL := string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: testpki.MustCreateCRL(t, issuerCert, issuerKey, []*big.Int{big.NewInt(4242)}, now)}))
+	revoked, msg, err := CheckCertificateRevocation(leafPEM, revokedCRL)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Contains(t, msg, "Certificate is revoked: serial 1092")
+
+	emptyCRL := string(testpki.MustCreateCRL(t, issuerCert, issuerKey, nil, now))
+	revoked, msg, err = CheckCertificateRevocation(leafPEM, emptyCRL)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Equal(t, "Certificate is not revoked", msg)
+
+	_, _, err = CheckCertificateRevocation(leafPEM, "not a crl")
+	assert.ErrorContains(t, err, "CRL check failed")
+}
+
 func newDocValidationFixture(t *testing.T, opts docFixtureOptions) *docValidationFixture {
 	t.Helper()
 
@@ -401,19 +524,19 @@ func newDocValidationFixture(t *testing.T, opts docFixtureOptions) *docValidatio
 	digiCRLPath := filepath.Join(tempDir, "digicert.crl")
 	ibmCRLPath := filepath.Join(tempDir, "ibm.crl")
 
-	rootCRLURL := (&url.URL{Scheme: "file", Path: rootCRLPath}).String()
-	digiCRLURL := (&url.URL{Scheme: "file", Path: digiCRLPath}).String()
-	ibmCRLURL := (&url.URL{Scheme: "file", Path: ibmCRLPath}).String()
+	rootCRLURL := testpki.FileURL(rootCRLPath)
+	digiCRLURL := testpki.FileURL(digiCRLPath)
+	ibmCRLURL := testpki.FileURL(ibmCRLPath)
 
 	now := time.Now().UTC()
 
-	rootKey := mustGenerateRSAKey(t)
-	rootTemplate := caTemplate(t, "Test DigiCert Root", big.NewInt(100), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 2)
-	rootDER := mustCreateCertificate(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
-	rootCert := mustParseCertificate(t, rootDER)
+	rootKey := testpki.MustGenerateRSAKey(t)
+	rootTemplate := testpki.CATemplate(t, "Test DigiCert Root", big.NewInt(100), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 2)
+	rootDER := testpki.MustCreateCertificate(t, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	rootCert := testpki.MustParseCertificate(t, rootDER)
 
-	digicertIntermediateKey := mustGenerateRSAKey(t)
-	digicertIntermediateTemplate := caTemplate(
+	digicertIntermediateKey := testpki.MustGenerateRSAKey(t)
+	digicertIntermediateTemplate := testpki.CATemplate(
 		t,
 		"Test DigiCert Intermediate",
 		big.NewInt(200),
@@ -423,11 +546,11 @@ func newDocValidationFixture(t *testing.T, opts docFixtureOptions) *docValidatio
 		rootCert.SubjectKeyId,
 		1,
 	)
-	digicertIntermediateDER := mustCreateCertificate(t, digicertIntermediateTemplate, rootCert, &digicertIntermediateKey.PublicKey, rootKey)
-	digicertIntermediateCert := mustParseCertificate(t, digicertIntermediateDER)
+	digicertIntermediateDER := testpki.MustCreateCertificate(t, digicertIntermediateTemplate, rootCert, &digicertIntermediateKey.PublicKey, rootKey)
+	digicertIntermediateCert := testpki.MustParseCertificate(t, digicertIntermediateDER)
 
-	ibmIntermediateKey := mustGenerateRSAKey(t)
-	ibmIntermediateTemplate := caTemplate(
+	ibmIntermediateKey := testpki.MustGenerateRSAKey(t)
+	ibmIntermediateTemplate := testpki.CATemplate(
 		t,
 		"Test IBM Intermediate",
 		big.NewInt(300),
@@ -437,43 +560,47 @@ func newDocValidationFixture(t *testing.T, opts docFixtureOptions) *docValidatio
 		digicertIntermediateCert.SubjectKeyId,
 		0,
 	)
-	ibmIntermediateDER := mustCreateCertificate(t, ibmIntermediateTemplate, digicertIntermediateCert, &ibmIntermediateKey.PublicKey, digicertIntermediateKey)
-	ibmIntermediateCert := mustParseCertificate(t, ibmIntermediateDER)
+	ibmIntermediateDER := testpki.MustCreateCertificate(t, ibmIntermediateTemplate, digicertIntermediateCert, &ibmIntermediateKey.PublicKey, digicertIntermediateKey)
+	ibmIntermediateCert := testpki.MustParseCertificate(t, ibmIntermediateDER)
 
-	encryptionKey := mustGenerateRSAKey(t)
-	attestationKey := mustGenerateRSAKey(t)
+	encryptionKey := testpki.MustGenerateRSAKey(t)
+	attestationKey := testpki.MustGenerateRSAKey(t)
 
-	encryptionTemplate := leafTemplate(t, "Test Encryption Certificate", big.NewInt(400), now, opts.expiredEncryption, opts.futureEncryption, !opts.missingEncryptionCRLDP, ibmCRLURL, ibmIntermediateCert.SubjectKeyId)
-	attestationTemplate := leafTemplate(t, "Test Attestation Certificate", big.NewInt(500), now, opts.expiredAttestation, opts.futureAttestation, !opts.missingAttestationCRLDP, ibmCRLURL, ibmIntermediateCert.SubjectKeyId)
+	encryptionTemplate := testpki.LeafTemplate(t, "Test Encryption Certificate", big.NewInt(400), now, opts.expiredEncryption, opts.futureEncryption, !opts.missingEncryptionCRLDP, ibmCRLURL, ibmIntermediateCert.SubjectKeyId)
+	attestationTemplate := testpki.LeafTemplate(t, "Test Attestation Certificate", big.NewInt(500), now, opts.expiredAttestation, opts.futureAttestation, !opts.missingAttestationCRLDP, ibmCRLURL, ibmIntermediateCert.SubjectKeyId)
 
 	encryptionParent := ibmIntermediateCert
 	encryptionSigner := ibmIntermediateKey
 	if opts.badEncryptionSignature {
-		outsiderKey := mustGenerateRSAKey(t)
-		outsiderTemplate := caTemplate(t, "Outsider CA", big.NewInt(600), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 0)
-		outsiderDER := mustCreateCertificate(t, outsiderTemplate, outsiderTemplate, &outsiderKey.PublicKey, outsiderKey)
-		encryptionParent = mustParseCertificate(t, outsiderDER)
+		outsiderKey := testpki.MustGenerateRSAKey(t)
+		outsiderTemplate := testpki.CATemplate(t, "Outsider CA", big.NewInt(600), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 0)
+		outsiderDER := testpki.MustCreateCertificate(t, outsiderTemplate, outsiderTemplate, &outsiderKey.PublicKey, outsiderKey)
+		encryptionParent = testpki.MustParseCertificate(t, outsiderDER)
 		encryptionSigner = outsiderKey
 	}
 
 	attestationParent := ibmIntermediateCert
 	attestationSigner := ibmIntermediateKey
 	if opts.badAttestationSignature {
-		outsiderKey := mustGenerateRSAKey(t)
-		outsiderTemplate := caTemplate(t, "Outsider Attestation CA", big.NewInt(700), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 0)
-		outsiderDER := mustCreateCertificate(t, outsiderTemplate, outsiderTemplate, &outsiderKey.PublicKey, outsiderKey)
-		attestationParent = mustParseCertificate(t, outsiderDER)
+		outsiderKey := testpki.MustGenerateRSAKey(t)
+		outsiderTemplate := testpki.CATemplate(t, "Outsider Attestation CA", big.NewInt(700), now.Add(-2*time.Hour), now.Add(72*time.Hour), nil, nil, 0)
+		outsiderDER := testpki.MustCreateCertificate(t, outsiderTemplate, outsiderTemplate, &outsiderKey.PublicKey, outsiderKey)
+		attestationParent = testpki.MustParseCertificate(t, outsiderDER)
 		attestationSigner = outsiderKey
 	}
 
-	encryptionDER := mustCreateCertificate(t, encryptionTemplate, encryptionParent, &encryptionKey.PublicKey, encryptionSigner)
-	attestationDER := mustCreateCertificate(t, attestationTemplate, attestationParent, &attestationKey.PublicKey, attestationSigner)
+	encryptionDER := testpki.MustCreateCertificate(t, encryptionTemplate, encryptionParent, &encryptionKey.PublicKey, encryptionSigner)
+	attestationDER := testpki.MustCreateCertificate(t, attestationTemplate, attestationParent, &attestationKey.PublicKey, attestationSigner)
 
-	encryptionCert := mustParseCertificate(t, encryptionDER)
-	attestationCert := mustParseCertificate(t, attestationDER)
+	encryptionCert := testpki.MustParseCertificate(t, encryptionDER)
+	attestationCert := testpki.MustParseCertificate(t, attestationDER)
 
-	rootCRLDER := mustCreateCRL(t, rootCert, rootKey, nil, now)
-	digicertCRLDER := mustCreateCRL(t, digicertIntermediateCert, digicertIntermediateKey, nil, now)
+	rootCRLDER := testpki.MustCreateCRL(t, rootCert, rootKey, nil, now)
+	var revokedIntermediates []*big.Int
+	if opts.revokeIbmIntermediate {
+		revokedIntermediates = append(revokedIntermediates, ibmIntermediateCert.SerialNumber)
+	}
+	digicertCRLDER := testpki.MustCreateCRL(t, digicertIntermediateCert, digicertIntermediateKey, revokedIntermediates, now)
 
 	revoked := make([]*big.Int, 0, 2)
 	if opts.revokeEncryption {
@@ -485,9 +612,9 @@ func newDocValidationFixture(t *testing.T, opts docFixtureOptions) *docValidatio
 
 	ibmCRLSigner := ibmIntermediateKey
 	if opts.invalidCRLSignature {
-		ibmCRLSigner = mustGenerateRSAKey(t)
+		ibmCRLSigner = testpki.MustGenerateRSAKey(t)
 	}
-	ibmCRLDER := mustCreateCRL(t, ibmIntermediateCert, ibmCRLSigner, revoked, now)
+	ibmCRLDER := testpki.MustCreateCRL(t, ibmIntermediateCert, ibmCRLSigner, revoked, now)
 
 	if opts.malformedCRL {
 		require.NoError(t, os.WriteFile(ibmCRLPath, []byte("malformed-crl"), 0600))
@@ -498,123 +625,10 @@ func newDocValidationFixture(t *testing.T, opts docFixtureOptions) *docValidatio
 	require.NoError(t, os.WriteFile(digiCRLPath, digicertCRLDER, 0600))
 
 	return &docValidationFixture{
-		encryptionCert:           pemEncodeCert(encryptionDER),
-		attestationCert:          pemEncodeCert(attestationDER),
-		ibmIntermediateCert:      pemEncodeCert(ibmIntermediateDER),
-		digicertIntermediateCert: pemEncodeCert(digicertIntermediateDER),
-		digicertRootCert:         pemEncodeCert(rootDER),
-	}
-}
-
-func mustGenerateRSAKey(t *testing.T) *rsa.PrivateKey {
-	t.Helper()
-	key, err := rsa.GenerateKey(rand.Reader, 2048)
-	require.NoError(t, err)
-	return key
-}
-
-func caTemplate(t *testing.T, commonName string, serial *big.Int, notBefore, notAfter time.Time, crlDP []string, authorityKeyID []byte, maxPathLen int) *x509.Certificate {
-	t.Helper()
-	return &x509.Certificate{
-		SerialNumber:          serial,
-		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Test Org"}},
-		NotBefore:             notBefore,
-		NotAfter:              notAfter,
-		SignatureAlgorithm:    x509.SHA512WithRSA,
-		BasicConstraintsValid: true,
-		IsCA:                  true,
-		MaxPathLenZero:        maxPathLen == 0,
-		MaxPathLen:            maxPathLen,
-		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
-		CRLDistributionPoints: crlDP,
-		SubjectKeyId:          mustRandomBytes(t, 20),
-		AuthorityKeyId:        authorityKeyID,
-	}
-}
-
-func leafTemplate(t *testing.T, commonName string, serial *big.Int, now time.Time, expired, future, includeCRLDP bool, crlDP string, authorityKeyID []byte) *x509.Certificate {
-	t.Helper()
-
-	notBefore := now.Add(-2 * time.Hour)
-	notAfter := now.Add(24 * time.Hour)
-	switch {
-	case expired:
-		notBefore = now.Add(-48 * time.Hour)
-		notAfter = now.Add(-2 * time.Hour)
-	case future:
-		notBefore = now.Add(2 * time.Hour)
-		notAfter = now.Add(48 * time.Hour)
-	}
-
-	var crlDistributionPoints []string
-	if includeCRLDP {
-		crlDistributionPoints = []string{crlDP}
+		encryptionCert:           testpki.PEMEncodeCert(encryptionDER),
+		attestationCert:          testpki.PEMEncodeCert(attestationDER),
+		ibmIntermediateCert:      testpki.PEMEncodeCert(ibmIntermediateDER),
+		digicertIntermediateCert: testpki.PEMEncodeCert(digicertIntermediateDER),
+		digicertRootCert:         testpki.PEMEncodeCert(rootDER),
 	}
-
-	return &x509.Certificate{
-		SerialNumber:          serial,
-		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Test Org"}},
-		NotBefore:             notBefore,
-		NotAfter:              notAfter,
-		SignatureAlgorithm:    x509.SHA512WithRSA,
-		BasicConstraintsValid: true,
-		IsCA:                  false,
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
-		CRLDistributionPoints: crlDistributionPoints,
-		AuthorityKeyId:        authorityKeyID,
-		SubjectKeyId:          mustRandomBytes(t, 20),
-	}
-}
-
-func mustCreateCertificate(t *testing.T, template, parent *x509.Certificate, publicKey *rsa.PublicKey, parentKey *rsa.PrivateKey) []byte {
-	t.Helper()
-	certDER, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
-	require.NoError(t, err)
-	return certDER
-}
-
-func mustParseCertificate(t *testing.T, certDER []byte) *x509.Certificate {
-	t.Helper()
-	cert, err := x509.ParseCertificate(certDER)
-	require.NoError(t, err)
-	return cert
-}
-
-func mustCreateCRL(t *testing.T, issuer *x509.Certificate, signer *rsa.PrivateKey, revokedSerials []*big.Int, now time.Time) []byte {
-	t.Helper()
-
-	entries := make([]x509.RevocationListEntry, 0, len(revokedSerials))
-	for _, serial := range revokedSerials {
-		entries = append(entries, x509.RevocationListEntry{
-			SerialNumber:   serial,
-			RevocationTime: now.Add(-30 * time.Minute),
-		})
-	}
-
-	template := &x509.RevocationList{
-		Number:                    big.NewInt(1),
-		ThisUpdate:                now.Add(-1 * time.Hour),
-		NextUpdate:                now.Add(24 * time.Hour),
-		SignatureAlgorithm:        x509.SHA512WithRSA,
-		RevokedCertificateEntries: entries,
-	}
-
-	crlDER, err := x509.CreateRevocationList(rand.Reader, template, issuer, signer)
-	require.NoError(t, err)
-	return crlDER
-}
-
-func pemEncodeCert(certDER []byte) string {
-	return string(pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: certDER,
-	}))
-}
-
-func mustRandomBytes(t *testing.T, size int) []byte {
-	t.Helper()
-	buffer := make([]byte, size)
-	_, err := rand.Read(buffer)
-	require.NoError(t, err)
-	return buffer
 }
@@ -0,0 +1,295 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	crlStoreDataExtension     = ".crl"
+	crlStoreMetadataExtension = ".json"
+)
+
+// CRLStore provides the CRLs used for certificate revocation checks.
+type CRLStore interface {
+	// CRL returns the CRL (DER or PEM) published at crlURL.
+	CRL(crlURL string) ([]byte, error)
+	// Import adds a CRL obtained out of band for crlURL.
+	Import(crlURL string, crl []byte) error
+}
+
+// CRLStoreAt is a CRLStore that judges whether a cached CRL is current at a given time. Validation
+// functions look up CRLs with CRLAt when the store implements it, so validating at a past time reuses
+// the CRLs that were current then.
+type CRLStoreAt interface {
+	CRLStore
+	// CRLAt returns the CRL published at crlURL that is current at the given time.
+	CRLAt(crlURL string, at time.Time) ([]byte, error)
+}
+
+// CRLDownloader is a CRLStore that downloads every CRL it is asked for. It is used when no store is
+// given and lets callers choose the HTTP client, retries and context of CRL downloads.
+type CRLDownloader struct {
+	// Context controlling cancellation of downloads; context.Background() is used when nil
+	Context context.Context
+	// Download configuration for http and https CRL URLs
+	Config gen.DownloadConfig
+}
+
+// CRL downloads the CRL published at crlURL.
+//
+// Parameters:
+//   - crlURL: CRL distribution point URL (http, https or file)
+//
+// Returns:
+//   - CRL content
+//   - Error if the download fails
+func (d CRLDownloader) CRL(crlURL string) ([]byte, error) {
+	ctx := d.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return downloadCRLFromURL(ctx, crlURL, d.Config)
+}
+
+// Import always fails because a CRLDownloader does not keep CRLs; use a DirectoryCRLStore or a
+// CRLSnapshot to import CRLs.
+func (d CRLDownloader) Import(crlURL string, crl []byte) error {
+	return fmt.Errorf("CRL downloader does not store CRLs")
+}
+
+// CRLMetadata describes a CRL saved in a DirectoryCRLStore.
+type CRLMetadata struct {
+	// CRL distribution point URL the CRL belongs to
+	URL string `json:"url"`
+	// Distinguished name of the CRL issuer
+	Issuer string `json:"issuer"`
+	// Time the CRL was issued
+	ThisUpdate time.Time `json:"thisUpdate"`
+	// Time by which the next CRL will be issued; the cached CRL is reused until then
+	NextUpdate time.Time `json:"nextUpdate"`
+	// Time the CRL was saved in the store
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// DirectoryCRLStore is a CRLStore that caches CRLs and their metadata in a directory.
+//
+// A cached CRL is reused until its nextUpdate time. In online mode a missing or stale CRL is
+// downloaded and saved; in offline mode nothing is downloaded and only a missing or stale CRL
+// is an error, so air-gapped systems can validate with CRLs imported by hand.
+type DirectoryCRLStore struct {
+	dir        string
+	offline    bool
+	downloader CRLDownloader
+}
+
+// NewDirectoryCRLStore creates a CRL store backed by a directory, creating the directory if needed.
+//
+// Parameters:
+//   - dir: Directory holding the cached CRLs
+//   - offline: true to never download CRLs and only use cached or imported ones
+//
+// Returns:
+//   - Directory backed CRL store
+//   - Error if the directory cannot be created
+func NewDirectoryCRLStore(dir string, offline bool) (*DirectoryCRLStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("required parameter is missing")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create CRL store directory - %v", err)
+	}
+
+	return &DirectoryCRLStore{dir: dir, offline: offline}, nil
+}
+
+// SetDownloader sets how missing or stale CRLs are downloaded in online mode.
+//
+// Parameters:
+//   - downloader: CRL downloader holding the context and download configuration
+func (s *DirectoryCRLStore) SetDownloader(downloader CRLDownloader) {
+	s.downloader = downloader
+}
+
+// CRL returns the cached CRL for crlURL while it is fresh. Otherwise the CRL is downloaded and saved,
+// or an error is returned in offline mode.
+//
+// Parameters:
+//   - crlURL: CRL distribution point URL
+//
+// Returns:
+//   - CRL content
+//   - Error if no fresh CRL is available
+func (s *DirectoryCRLStore) CRL(crlURL string) ([]byte, error) {
+	return s.CRLAt(crlURL, time.Now())
+}
+
+// CRLAt returns the cached CRL for crlURL if it is fresh at the given time, i.e. its nextUpdate time is
+// after at. Otherwise the CRL is downloaded and saved, or an error is returned in offline mode.
+//
+// Parameters:
+//   - crlURL: CRL distribution point URL
+//   - at: Time the CRL must be fresh at, usually the validation time
+//
+// Returns:
+//   - CRL content
+//   - Error if no fresh CRL is available
+func (s *DirectoryCRLStore) CRLAt(crlURL string, at time.Time) ([]byte, error) {
+	data, metadata, err := s.load(crlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil && at.Before(metadata.NextUpdate) {
+		return data, nil
+	}
+
+	if s.offline {
+		if data == nil {
+			return nil, fmt.Errorf("no cached CRL for %s (offline mode)", crlURL)
+		}
+		return nil, fmt.Errorf("cached CRL for %s is stale (nextUpdate=%s, offline mode)", crlURL, formatCertificateTime(metadata.NextUpdate))
+	}
+
+	data, err = s.downloader.CRL(crlURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Import(crlURL, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Import saves a CRL for crlURL together with its metadata, replacing any cached CRL.
+//
+// Parameters:
+//   - crlURL: CRL distribution point URL the CRL belongs to
+//   - crl: CRL content (DER or PEM)
+//
+// Returns:
+//   - Error if the CRL cannot be parsed or saved
+func (s *DirectoryCRLStore) Import(crlURL string, crl []byte) error {
+	if crlURL == "" || len(crl) == 0 {
+		return fmt.Errorf("required parameter is missing")
+	}
+
+	parsed, err := parseCRL(crl)
+	if err != nil {
+		return fmt.Errorf("failed to parse CRL - %v", err)
+	}
+
+	metadata, err := json.MarshalIndent(CRLMetadata{
+		URL:        crlURL,
+		Issuer:     parsed.Issuer.String(),
+		ThisUpdate: parsed.ThisUpdate.UTC(),
+		NextUpdate: parsed.NextUpdate.UTC(),
+		SavedAt:    time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode CRL metadata - %v", err)
+	}
+
+	base := s.basePath(crlURL)
+	if err := writeFileAtomic(base+crlStoreDataExtension, crl); err != nil {
+		return fmt.Errorf("failed to save CRL - %v", err)
+	}
+	if err := writeFileAtomic(base+crlStoreMetadataExtension, metadata); err != nil {
+		return fmt.Errorf("failed to save CRL metadata - %v", err)
+	}
+
+	return nil
+}
+
+// Metadata returns the metadata of the cached CRL for crlURL.
+//
+// Parameters:
+//   - crlURL: CRL distribution point URL
+//
+// Returns:
+//   - Metadata of the cached CRL
+//   - true if a CRL is cached for crlURL
+//   - Error if the metadata cannot be read
+func (s *DirectoryCRLStore) Metadata(crlURL string) (CRLMetadata, bool, error) {
+	data, metadata, err := s.load(crlURL)
+	return metadata, data != nil, err
+}
+
+// load reads the cached CRL and its metadata, returning nil data if nothing is cached.
+func (s *DirectoryCRLStore) load(crlURL string) ([]byte, CRLMetadata, error) {
+	base := s.basePath(crlURL)
+
+	data, err := os.ReadFile(base + crlStoreDataExtension)
+	if os.IsNotExist(err) {
+		return nil, CRLMetadata{}, nil
+	}
+	if err != nil {
+		return nil, CRLMetadata{}, fmt.Errorf("failed to read cached CRL - %v", err)
+	}
+
+	metadataJson, err := os.ReadFile(base + crlStoreMetadataExtension)
+	if os.IsNotExist(err) {
+		return nil, CRLMetadata{}, nil
+	}
+	if err != nil {
+		return nil, CRLMetadata{}, fmt.Errorf("failed to read cached CRL metadata - %v", err)
+	}
+
+	var metadata CRLMetadata
+	if err := json.Unmarshal(metadataJson, &metadata); err != nil {
+		return nil, CRLMetadata{}, fmt.Errorf("failed to parse cached CRL metadata - %v", err)
+	}
+
+	return data, metadata, nil
+}
+
+// basePath returns the file path prefix of the cache entry for crlURL.
+func (s *DirectoryCRLStore) basePath(crlURL string) string {
+	digest := sha256.Sum256([]byte(crlURL))
+	return filepath.Join(s.dir, hex.EncodeToString(digest[:]))
+}
+
+// writeFileAtomic writes data to a temporary file in the target directory and renames it into place.
+func writeFileAtomic(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
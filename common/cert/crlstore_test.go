@@ -0,0 +1,192 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	"github.com/ibm-hyper-protect/contract-go/v2/internal/testpki"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createStaleCRLForTest returns a CRL whose nextUpdate has already passed.
+func createStaleCRLForTest(t *testing.T) []byte {
+	t.Helper()
+
+	now := time.Now().UTC()
+	issuerKey := testpki.MustGenerateRSAKey(t)
+	issuerTemplate := testpki.CATemplate(t, "Test Issuer", big.NewInt(100), now.Add(-72*time.Hour), now.Add(72*time.Hour), nil, nil, 0)
+	issuerCert := testpki.MustParseCertificate(t, testpki.MustCreateCertificate(t, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey))
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:             big.NewInt(1),
+		ThisUpdate:         now.Add(-48 * time.Hour),
+		NextUpdate:         now.Add(-24 * time.Hour),
+		SignatureAlgorithm: x509.SHA512WithRSA,
+	}, issuerCert, issuerKey)
+	require.NoError(t, err)
+	return crl
+}
+
+// Test DirectoryCRLStore downloads a CRL once and reuses it until nextUpdate
+func TestDirectoryCRLStoreCachesCRL(t *testing.T) {
+	fixture := newDocValidationFixture(t, docFixtureOptions{})
+	attestationCert, err := parseCertificatePEM(fixture.attestationCert)
+	require.NoError(t, err)
+	crlURL := attestationCert.CRLDistributionPoints[0]
+
+	store, err := NewDirectoryCRLStore(filepath.Join(t.TempDir(), "crls"), false)
+	require.NoError(t, err)
+
+	first, err := store.CRL(crlURL)
+	require.NoError(t, err)
+
+	metadata, ok, err := store.Metadata(crlURL)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, crlURL, metadata.URL)
+	assert.Contains(t, metadata.Issuer, "Test IBM Intermediate")
+	assert.True(t, metadata.NextUpdate.After(time.Now()))
+
+	parsedURL, err := url.Parse(crlURL)
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(parsedURL.Path))
+
+	second, err := store.CRL(crlURL)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+// Test DirectoryCRLStore refreshes stale CRLs online and rejects them offline
+func TestDirectoryCRLStoreStaleCRL(t *testing.T) {
+	dir := t.TempDir()
+	crlPath := filepath.Join(dir, "current.crl")
+	crlURL := testpki.FileURL(crlPath)
+
+	fixture := newDocValidationFixture(t, docFixtureOptions{})
+	current, err := SnapshotCRLs(fixture.encryptionCert)
+	require.NoError(t, err)
+	var currentCRL []byte
+	for _, data := range current {
+		currentCRL = data
+	}
+	require.NoError(t, os.WriteFile(crlPath, currentCRL, 0600))
+
+	storeDir := filepath.Join(dir, "store")
+	offline, err := NewDirectoryCRLStore(storeDir, true)
+	require.NoError(t, err)
+
+	_, err = offline.CRL(crlURL)
+	assert.EqualError(t, err, "no cached CRL for "+crlURL+" (offline mode)")
+
+	require.NoError(t, offline.Import(crlURL, createStaleCRLForTest(t)))
+	_, err = offline.CRL(crlURL)
+	assert.ErrorContains(t, err, "cached CRL for "+crlURL+" is stale")
+
+	_, err = offline.CRLAt(crlURL, time.Now().Add(-36*time.Hour))
+	assert.NoError(t, err, "stale CRL is fresh at a validation time before its nextUpdate")
+
+	online, err := NewDirectoryCRLStore(storeDir, false)
+	require.NoError(t, err)
+	refreshed, err := online.CRL(crlURL)
+	require.NoError(t, err)
+	assert.Equal(t, currentCRL, refreshed)
+
+	_, err = offline.CRL(crlURL)
+	assert.NoError(t, err)
+}
+
+// Test the WithCRLs validation functions with an offline DirectoryCRLStore populated by imports
+func TestValidateWithOfflineDirectoryCRLStore(t *testing.T) {
+	fixture := newDocValidationFixture(t, docFixtureOptions{})
+	crls, err := SnapshotCRLs(fixture.digicertIntermediateCert, fixture.ibmIntermediateCert, fixture.encryptionCert)
+	require.NoError(t, err)
+
+	store, err := NewDirectoryCRLStore(t.TempDir(), true)
+	require.NoError(t, err)
+
+	_, _, err = ValidateEncryptionCertificateDocumentWithCRLs(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, store)
+	assert.ErrorContains(t, err, StageCAVerify)
+	assert.ErrorContains(t, err, "offline mode")
+
+	for crlURL, data := range crls {
+		require.NoError(t, store.Import(crlURL, data))
+	}
+
+	valid, msg, err := ValidateEncryptionCertificateDocumentWithCRLs(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, store)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, "encryption certificate document is valid", msg)
+
+	valid, _, err = ValidateCertificateRevocationListWithCRLs(fixture.encryptionCert, fixture.ibmIntermediateCert, store)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+// Test DirectoryCRLStore rejects invalid input
+func TestDirectoryCRLStoreInvalid(t *testing.T) {
+	_, err := NewDirectoryCRLStore("", false)
+	assert.EqualError(t, err, "required parameter is missing")
+
+	store, err := NewDirectoryCRLStore(t.TempDir(), false)
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, store.Import("file:///crl", []byte("not a crl")), "failed to parse CRL")
+	assert.EqualError(t, store.Import("", nil), "required parameter is missing")
+
+	_, err = store.CRL("ldap://example.com/crl")
+	assert.EqualError(t, err, "unsupported CRL URL scheme: ldap")
+}
+
+// Test CRLDownloader downloads CRLs with the configured client and retries
+func TestCRLDownloader(t *testing.T) {
+	crl := createStaleCRLForTest(t)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(crl)
+	}))
+	defer server.Close()
+
+	downloader := CRLDownloader{Config: gen.DownloadConfig{Fetcher: server.Client(), MaxAttempts: 2, InitialBackoff: time.Millisecond}}
+	data, err := downloader.CRL(server.URL + "/intermediate.crl")
+	require.NoError(t, err)
+	assert.Equal(t, crl, data)
+	assert.Equal(t, int32(2), requests.Load())
+
+	assert.EqualError(t, downloader.Import(server.URL, crl), "CRL downloader does not store CRLs")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = CRLDownloader{Context: ctx, Config: gen.DownloadConfig{Fetcher: server.Client()}}.CRL(server.URL + "/intermediate.crl")
+	assert.ErrorContains(t, err, "context canceled")
+}
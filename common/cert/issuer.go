@@ -0,0 +1,186 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"embed"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+// DigicertTrustedRootG4 is the PEM-formatted DigiCert Trusted Root G4 certificate that anchors the
+// IBM certificate chains.
+//
+//go:embed roots/digicert-trusted-root-g4.pem
+var DigicertTrustedRootG4 string
+
+// roots holds the embedded certificates of the IBM certificate chains. Every certificate in it that is
+// not self-signed is an IBM or DigiCert intermediate certificate.
+//
+//go:embed roots/*.pem
+var roots embed.FS
+
+// embeddedIntermediates parses the embedded intermediate certificates once.
+var embeddedIntermediates = sync.OnceValue(func() []string {
+	var intermediates []string
+	files, _ := fs.Glob(roots, "roots/*.pem")
+	for _, name := range files {
+		data, err := roots.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		certificate, err := parseCertificatePEM(string(data))
+		if err != nil || bytes.Equal(certificate.RawIssuer, certificate.RawSubject) {
+			continue
+		}
+		intermediates = append(intermediates, string(data))
+	}
+	return intermediates
+})
+
+// EmbeddedIntermediateCertificates returns the embedded IBM and DigiCert intermediate certificates that
+// encryption and attestation certificates are verified against when no intermediates are given.
+//
+// Returns:
+//   - PEM-formatted intermediate certificates
+func EmbeddedIntermediateCertificates() []string {
+	return embeddedIntermediates()
+}
+
+// FindIssuerCertificate returns the certificate among candidates that issued a certificate, i.e. whose
+// subject is the issuer of the certificate and whose key signed it.
+//
+// Parameters:
+//   - certificatePEM: PEM-formatted certificate whose issuer should be found
+//   - candidates: PEM-formatted candidate issuer certificates, e.g. [EmbeddedIntermediateCertificates]
+//
+// Returns:
+//   - PEM-formatted issuer certificate
+//   - Error if the certificate cannot be parsed or none of the candidates issued it
+func FindIssuerCertificate(certificatePEM string, candidates []string) (string, error) {
+	if gen.CheckIfEmpty(certificatePEM) {
+		return "", fmt.Errorf("required parameter is missing")
+	}
+
+	certificate, err := parseCertificatePEM(certificatePEM)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse certificate - %v", err)
+	}
+
+	for _, candidate := range candidates {
+		issuer, err := parseCertificatePEM(candidate)
+		if err != nil || !bytes.Equal(issuer.RawSubject, certificate.RawIssuer) {
+			continue
+		}
+		if certificate.CheckSignatureFrom(issuer) == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("issuer %q of certificate %q is not a known intermediate certificate", certificate.Issuer.CommonName, certificate.Subject.CommonName)
+}
+
+// IssuerCertificateURL returns the first CA Issuers URL of the Authority Information Access
+// extension of a certificate.
+//
+// Parameters:
+//   - certificatePEM: PEM-formatted certificate
+//
+// Returns:
+//   - URL the issuer certificate is published at
+//   - Error if the certificate cannot be parsed or has no CA Issuers URL
+func IssuerCertificateURL(certificatePEM string) (string, error) {
+	if gen.CheckIfEmpty(certificatePEM) {
+		return "", fmt.Errorf("required parameter is missing")
+	}
+
+	certificate, err := parseCertificatePEM(certificatePEM)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse certificate - %v", err)
+	}
+
+	return issuerCertificateURL(certificate)
+}
+
+// DownloadIssuerCertificate downloads the issuer of a certificate from its CA Issuers URL and checks
+// that the downloaded certificate signed it. The downloaded certificate is not trusted on its own; it
+// must still be validated against a trusted root.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the download
+//   - certificatePEM: PEM-formatted certificate whose issuer should be downloaded
+//   - config: Download configuration
+//
+// Returns:
+//   - PEM-formatted issuer certificate
+//   - Error if the URL is missing, the download fails or the downloaded certificate is not the issuer
+func DownloadIssuerCertificate(ctx context.Context, certificatePEM string, config gen.DownloadConfig) (string, error) {
+	if gen.CheckIfEmpty(certificatePEM) {
+		return "", fmt.Errorf("required parameter is missing")
+	}
+
+	certificate, err := parseCertificatePEM(certificatePEM)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse certificate - %v", err)
+	}
+
+	issuerURL, err := issuerCertificateURL(certificate)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := gen.CertificateDownloaderContext(ctx, issuerURL, config)
+	if err != nil {
+		return "", fmt.Errorf("failed to download issuer certificate - %v", err)
+	}
+
+	issuer, err := parseCertificateData([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse issuer certificate from %s - %v", issuerURL, err)
+	}
+
+	if err := certificate.CheckSignatureFrom(issuer); err != nil {
+		return "", fmt.Errorf("certificate downloaded from %s did not issue %q - %v", issuerURL, certificate.Subject.CommonName, err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer.Raw})), nil
+}
+
+// issuerCertificateURL returns the first CA Issuers URL of a certificate.
+func issuerCertificateURL(certificate *x509.Certificate) (string, error) {
+	if len(certificate.IssuingCertificateURL) == 0 {
+		return "", fmt.Errorf("CA issuers URL not found in certificate %q", certificate.Subject.CommonName)
+	}
+	return certificate.IssuingCertificateURL[0], nil
+}
+
+// parseCertificateData parses a PEM or DER encoded certificate.
+func parseCertificateData(data []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+		}
+		data = block.Bytes
+	}
+	return x509.ParseCertificate(data)
+}
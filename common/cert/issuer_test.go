@@ -0,0 +1,131 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cert
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	"github.com/ibm-hyper-protect/contract-go/v2/internal/testpki"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the embedded DigiCert Trusted Root G4 certificate is a valid self-signed root
+func TestDigicertTrustedRootG4(t *testing.T) {
+	root, err := parseCertificatePEM(DigicertTrustedRootG4)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DigiCert Trusted Root G4", root.Subject.CommonName)
+	assert.True(t, root.IsCA)
+	assert.NoError(t, root.CheckSignatureFrom(root))
+}
+
+// Test DownloadIssuerCertificate downloads the issuer from the CA Issuers URL and checks it signed the certificate
+func TestDownloadIssuerCertificate(t *testing.T) {
+	now := time.Now().UTC()
+	issuerKey := testpki.MustGenerateRSAKey(t)
+	issuerTemplate := testpki.CATemplate(t, "Test Issuer", big.NewInt(100), now.Add(-time.Hour), now.Add(24*time.Hour), nil, nil, 0)
+	issuerDER := testpki.MustCreateCertificate(t, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey)
+	issuerCert := testpki.MustParseCertificate(t, issuerDER)
+
+	otherKey := testpki.MustGenerateRSAKey(t)
+	otherTemplate := testpki.CATemplate(t, "Other Issuer", big.NewInt(200), now.Add(-time.Hour), now.Add(24*time.Hour), nil, nil, 0)
+	otherDER := testpki.MustCreateCertificate(t, otherTemplate, otherTemplate, &otherKey.PublicKey, otherKey)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/issuer.crt":
+			w.Write(issuerDER)
+		case "/other.crt":
+			w.Write([]byte(testpki.PEMEncodeCert(otherDER)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	leafWithIssuerURL := func(issuerURL string) string {
+		template := testpki.LeafTemplate(t, "Test Leaf", big.NewInt(300), now, false, false, false, "", issuerCert.SubjectKeyId)
+		if issuerURL != "" {
+			template.IssuingCertificateURL = []string{issuerURL}
+		}
+		return testpki.PEMEncodeCert(testpki.MustCreateCertificate(t, template, issuerCert, &testpki.MustGenerateRSAKey(t).PublicKey, issuerKey))
+	}
+	config := gen.DownloadConfig{Fetcher: server.Client()}
+
+	issuerURL, err := IssuerCertificateURL(leafWithIssuerURL(server.URL + "/issuer.crt"))
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/issuer.crt", issuerURL)
+
+	issuer, err := DownloadIssuerCertificate(context.Background(), leafWithIssuerURL(server.URL+"/issuer.crt"), config)
+	require.NoError(t, err)
+	assert.Equal(t, testpki.PEMEncodeCert(issuerDER), issuer)
+
+	_, err = DownloadIssuerCertificate(context.Background(), leafWithIssuerURL(server.URL+"/other.crt"), config)
+	assert.ErrorContains(t, err, "did not issue \"Test Leaf\"")
+
+	_, err = DownloadIssuerCertificate(context.Background(), leafWithIssuerURL(server.URL+"/missing.crt"), config)
+	assert.ErrorContains(t, err, "failed to download issuer certificate - unexpected HTTP status: 404 Not Found")
+
+	_, err = DownloadIssuerCertificate(context.Background(), leafWithIssuerURL(""), config)
+	assert.EqualError(t, err, "CA issuers URL not found in certificate \"Test Leaf\"")
+
+	_, err = DownloadIssuerCertificate(context.Background(), "", config)
+	assert.EqualError(t, err, "required parameter is missing")
+}
+
+// Test the embedded intermediate certificates are CA certificates issued by another certificate
+func TestEmbeddedIntermediateCertificates(t *testing.T) {
+	for _, intermediatePEM := range EmbeddedIntermediateCertificates() {
+		intermediate, err := parseCertificatePEM(intermediatePEM)
+		require.NoError(t, err)
+
+		assert.True(t, intermediate.IsCA)
+		assert.NotEqual(t, intermediate.Subject.String(), intermediate.Issuer.String())
+	}
+}
+
+// Test FindIssuerCertificate only returns the candidate that signed the certificate
+func TestFindIssuerCertificate(t *testing.T) {
+	now := time.Now().UTC()
+	issuerKey := testpki.MustGenerateRSAKey(t)
+	issuerTemplate := testpki.CATemplate(t, "Test Issuer", big.NewInt(100), now.Add(-time.Hour), now.Add(24*time.Hour), nil, nil, 0)
+	issuerDER := testpki.MustCreateCertificate(t, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey)
+	issuerCert := testpki.MustParseCertificate(t, issuerDER)
+
+	// Same subject as the issuer, but a different key
+	impostorKey := testpki.MustGenerateRSAKey(t)
+	impostorTemplate := testpki.CATemplate(t, "Test Issuer", big.NewInt(200), now.Add(-time.Hour), now.Add(24*time.Hour), nil, nil, 0)
+	impostorDER := testpki.MustCreateCertificate(t, impostorTemplate, impostorTemplate, &impostorKey.PublicKey, impostorKey)
+
+	leafTemplate := testpki.LeafTemplate(t, "Test Leaf", big.NewInt(300), now, false, false, false, "", issuerCert.SubjectKeyId)
+	leaf := testpki.PEMEncodeCert(testpki.MustCreateCertificate(t, leafTemplate, issuerCert, &testpki.MustGenerateRSAKey(t).PublicKey, issuerKey))
+
+	issuer, err := FindIssuerCertificate(leaf, []string{"invalid", testpki.PEMEncodeCert(impostorDER), testpki.PEMEncodeCert(issuerDER)})
+	require.NoError(t, err)
+	assert.Equal(t, testpki.PEMEncodeCert(issuerDER), issuer)
+
+	_, err = FindIssuerCertificate(leaf, []string{testpki.PEMEncodeCert(impostorDER)})
+	assert.EqualError(t, err, "issuer \"Test Issuer\" of certificate \"Test Leaf\" is not a known intermediate certificate")
+
+	_, err = FindIssuerCertificate("", nil)
+	assert.EqualError(t, err, "required parameter is missing")
+}
@@ -0,0 +1,382 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+// Certificate validation stages, in the order they are run.
+const (
+	// DigiCert intermediate chains to the DigiCert root and is not revoked
+	StageCAVerify = "CA verify"
+	// IBM intermediate chains to the DigiCert root and is not revoked
+	StageSigningCertVerify = "signing cert verify"
+	// Certificate document is signed by the IBM intermediate
+	StageDocSignatureVerify = "doc signature verify"
+	// Certificate document is within its validity period
+	StageDateVerify = "date verify"
+	// CRL of the IBM intermediate is current and signed by it
+	StageCRLSignatureVerify = "CRL signature verify"
+	// Certificate document is not listed in the CRL
+	StageSerialRevoked = "serial revoked"
+)
+
+// Stage statuses. Only StagePassed counts as success; a stage that could not run is a failure.
+const (
+	StagePassed = "passed"
+	StageFailed = "failed"
+	StageNotRun = "not-run"
+)
+
+// Roles of the certificates involved in a validation stage.
+const (
+	RoleDigicertRoot         = "DigiCert root"
+	RoleDigicertIntermediate = "DigiCert intermediate"
+	RoleIbmIntermediate      = "IBM intermediate"
+	RoleDocument             = "document"
+)
+
+// ValidationReport is the result of validating a certificate document, with one entry per stage.
+type ValidationReport struct {
+	// True only if every stage passed
+	Valid bool `json:"valid" yaml:"valid"`
+	// Time validity periods were checked at
+	ValidatedAt time.Time `json:"validatedAt" yaml:"validatedAt"`
+	// Result of each stage, in the order they were run
+	Stages []StageResult `json:"stages" yaml:"stages"`
+}
+
+// StageResult is the result of a single validation stage.
+type StageResult struct {
+	// Stage name, e.g. StageCAVerify
+	Stage string `json:"stage" yaml:"stage"`
+	// StagePassed, StageFailed or StageNotRun
+	Status string `json:"status" yaml:"status"`
+	// Details of the result or the reason for the failure
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+	// Certificates checked by the stage
+	Certificates []CertificateInfo `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	// CRL checked by the stage, nil if the stage uses no CRL or it could not be loaded
+	CRL *CRLInfo `json:"crl,omitempty" yaml:"crl,omitempty"`
+}
+
+// CertificateInfo identifies a certificate involved in a validation stage.
+type CertificateInfo struct {
+	// RoleDigicertRoot, RoleDigicertIntermediate, RoleIbmIntermediate or RoleDocument
+	Role string `json:"role" yaml:"role"`
+	// Subject distinguished name
+	Subject string `json:"subject" yaml:"subject"`
+	// Issuer distinguished name
+	Issuer string `json:"issuer" yaml:"issuer"`
+	// Serial number in hexadecimal
+	Serial string `json:"serial" yaml:"serial"`
+	// Start of the validity period
+	NotBefore time.Time `json:"notBefore" yaml:"notBefore"`
+	// End of the validity period
+	NotAfter time.Time `json:"notAfter" yaml:"notAfter"`
+}
+
+// CRLInfo identifies the CRL used by a validation stage.
+type CRLInfo struct {
+	// CRL distribution point URL
+	URL string `json:"url" yaml:"url"`
+	// Issuer distinguished name, empty if the CRL could not be parsed
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Start of the CRL validity period, zero if the CRL could not be parsed
+	ThisUpdate time.Time `json:"thisUpdate" yaml:"thisUpdate"`
+	// End of the CRL validity period
+	NextUpdate time.Time `json:"nextUpdate" yaml:"nextUpdate"`
+}
+
+// Stage returns the result of a stage by name.
+func (r *ValidationReport) Stage(name string) (StageResult, bool) {
+	for _, stage := range r.Stages {
+		if stage.Stage == name {
+			return stage, true
+		}
+	}
+	return StageResult{}, false
+}
+
+// Err returns the error of the first failed stage, in the "<stage> failed - <detail>" form of the
+// validation functions, or nil if every stage passed.
+func (r *ValidationReport) Err() error {
+	for _, stage := range r.Stages {
+		if stage.Status != StagePassed {
+			return stageError(stage.Stage, stage.Detail)
+		}
+	}
+	return nil
+}
+
+// EncryptionCertificateValidationReport validates an encryption certificate document and its
+// revocation status and reports the result of every stage.
+//
+// The stages are those of ValidateEncryptionCertificateDocumentAt followed by those of
+// ValidateCertificateRevocationListAt. Every stage is always reported; a stage that depends on a stage
+// that did not pass is reported as not run.
+//
+// Parameters:
+//   - encryptionCert: PEM-formatted encryption certificate document
+//   - ibmIntermediateCert: PEM-formatted IBM intermediate certificate
+//   - digicertIntermediateCert: PEM-formatted DigiCert intermediate certificate
+//   - digicertRootCert: PEM-formatted DigiCert root certificate
+//   - crls: CRL store to take CRLs from; CRLs are downloaded if nil
+//   - at: Time validity periods are checked at; the current time if zero
+//
+// Returns:
+//   - Validation report
+//   - Error if a parameter is missing
+func EncryptionCertificateValidationReport(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, crls CRLStore, at time.Time) (*ValidationReport, error) {
+	return certificateValidationReport(encryptionCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, "encryption", crls, at)
+}
+
+// AttestationCertificateValidationReport validates an attestation certificate document and its
+// revocation status and reports the result of every stage, like EncryptionCertificateValidationReport.
+//
+// Parameters:
+//   - attestationCert: PEM-formatted attestation certificate document
+//   - ibmIntermediateCert: PEM-formatted IBM intermediate certificate
+//   - digicertIntermediateCert: PEM-formatted DigiCert intermediate certificate
+//   - digicertRootCert: PEM-formatted DigiCert root certificate
+//   - crls: CRL store to take CRLs from; CRLs are downloaded if nil
+//   - at: Time validity periods are checked at; the current time if zero
+//
+// Returns:
+//   - Validation report
+//   - Error if a parameter is missing
+func AttestationCertificateValidationReport(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert string, crls CRLStore, at time.Time) (*ValidationReport, error) {
+	return certificateValidationReport(attestationCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, "attestation", crls, at)
+}
+
+// certificateValidationReport runs the document and revocation stages for a certificate document.
+func certificateValidationReport(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, certType string, crls CRLStore, at time.Time) (*ValidationReport, error) {
+	if gen.CheckIfEmpty(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert) {
+		return nil, fmt.Errorf("required parameter is missing")
+	}
+
+	report := newValidationReport(at)
+	report.validateDocument(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, certType, crls)
+	report.validateRevocation(targetCert, ibmIntermediateCert, crls)
+	report.Valid = report.Err() == nil
+
+	return report, nil
+}
+
+// newValidationReport creates an empty report validated at the given time.
+func newValidationReport(at time.Time) *ValidationReport {
+	return &ValidationReport{ValidatedAt: validationTime(at)}
+}
+
+// validateDocument runs the chain, document signature and date stages.
+func (r *ValidationReport) validateDocument(targetCert, ibmIntermediateCert, digicertIntermediateCert, digicertRootCert, certType string, crls CRLStore) {
+	target, targetErr := parseCertificatePEM(targetCert)
+	ibmIntermediate, ibmIntermediateErr := parseCertificatePEM(ibmIntermediateCert)
+	digicertIntermediate, digicertIntermediateErr := parseCertificatePEM(digicertIntermediateCert)
+	digicertRoot, digicertRootErr := parseCertificatePEM(digicertRootCert)
+
+	// Step 1: Verify DigiCert intermediate certificate.
+	certificates := certificateInfos(
+		namedCertificate{RoleDigicertIntermediate, digicertIntermediate},
+		namedCertificate{RoleDigicertRoot, digicertRoot},
+	)
+	switch {
+	case digicertIntermediateErr != nil:
+		r.fail(StageCAVerify, fmt.Sprintf("failed to parse DigiCert intermediate certificate - %v", digicertIntermediateErr), certificates, nil)
+	case digicertRootErr != nil:
+		r.fail(StageCAVerify, fmt.Sprintf("failed to parse DigiCert root certificate - %v", digicertRootErr), certificates, nil)
+	default:
+		crl, err := verifyCertificateWithCRL(digicertIntermediate, digicertRoot, nil, r.ValidatedAt, crls)
+		if err != nil {
+			r.fail(StageCAVerify, err.Error(), certificates, crl)
+		} else {
+			r.pass(StageCAVerify, "DigiCert intermediate certificate chains to the root and is not revoked", certificates, crl)
+		}
+	}
+
+	// Step 2: Verify IBM intermediate certificate.
+	certificates = certificateInfos(
+		namedCertificate{RoleIbmIntermediate, ibmIntermediate},
+		namedCertificate{RoleDigicertIntermediate, digicertIntermediate},
+		namedCertificate{RoleDigicertRoot, digicertRoot},
+	)
+	switch {
+	case ibmIntermediateErr != nil:
+		r.fail(StageSigningCertVerify, fmt.Sprintf("failed to parse IBM intermediate certificate - %v", ibmIntermediateErr), certificates, nil)
+	case !r.passed(StageCAVerify):
+		r.notRun(StageSigningCertVerify, StageCAVerify, certificates)
+	default:
+		crl, err := verifyCertificateWithCRL(ibmIntermediate, digicertRoot, digicertIntermediate, r.ValidatedAt, crls)
+		if err != nil {
+			r.fail(StageSigningCertVerify, err.Error(), certificates, crl)
+		} else {
+			r.pass(StageSigningCertVerify, "IBM intermediate certificate chains to the root and is not revoked", certificates, crl)
+		}
+	}
+
+	// Step 3: Verify certificate document signature using IBM intermediate public key.
+	certificates = certificateInfos(
+		namedCertificate{RoleDocument, target},
+		namedCertificate{RoleIbmIntermediate, ibmIntermediate},
+	)
+	switch {
+	case targetErr != nil:
+		r.fail(StageDocSignatureVerify, fmt.Sprintf("failed to parse %s certificate - %v", certType, targetErr), certificates, nil)
+	case !r.passed(StageSigningCertVerify):
+		r.notRun(StageDocSignatureVerify, StageSigningCertVerify, certificates)
+	default:
+		if err := target.CheckSignatureFrom(ibmIntermediate); err != nil {
+			r.fail(StageDocSignatureVerify, fmt.Sprintf("failed to verify signature - %v", err), certificates, nil)
+		} else {
+			r.pass(StageDocSignatureVerify, fmt.Sprintf("%s certificate is signed by the IBM intermediate certificate", certType), certificates, nil)
+		}
+	}
+
+	// Step 4: Verify validity dates.
+	certificates = certificateInfos(namedCertificate{RoleDocument, target})
+	if targetErr != nil {
+		r.notRun(StageDateVerify, StageDocSignatureVerify, certificates)
+	} else if err := validateCertificateDateWindow(target, r.ValidatedAt); err != nil {
+		r.fail(StageDateVerify, err.Error(), certificates, nil)
+	} else {
+		r.pass(StageDateVerify, fmt.Sprintf("certificate is valid until %s", formatCertificateTime(target.NotAfter)), certificates, nil)
+	}
+}
+
+// validateRevocation runs the CRL signature and revocation stages.
+func (r *ValidationReport) validateRevocation(certificateDocument, ibmIntermediateCert string, crls CRLStore) {
+	certificate, certificateErr := parseCertificatePEM(certificateDocument)
+	ibmIntermediate, ibmIntermediateErr := parseCertificatePEM(ibmIntermediateCert)
+	certificates := certificateInfos(
+		namedCertificate{RoleDocument, certificate},
+		namedCertificate{RoleIbmIntermediate, ibmIntermediate},
+	)
+
+	crl, crlInfo, err := loadDocumentCRL(certificate, certificateErr, ibmIntermediate, ibmIntermediateErr, r.ValidatedAt, crls)
+	if err != nil {
+		r.fail(StageCRLSignatureVerify, err.Error(), certificates, crlInfo)
+		r.notRun(StageSerialRevoked, StageCRLSignatureVerify, certificateInfos(namedCertificate{RoleDocument, certificate}))
+		return
+	}
+	r.pass(StageCRLSignatureVerify, "CRL is valid and signed by the IBM intermediate certificate", certificates, crlInfo)
+
+	certificates = certificateInfos(namedCertificate{RoleDocument, certificate})
+	if revoked, _ := isSerialRevoked(crl, certificate.SerialNumber); revoked {
+		r.fail(StageSerialRevoked, "certificate is listed in CRL", certificates, crlInfo)
+	} else {
+		r.pass(StageSerialRevoked, "certificate is not listed in CRL", certificates, crlInfo)
+	}
+}
+
+// loadDocumentCRL loads the CRL of a certificate document and checks its validity window and signature.
+func loadDocumentCRL(certificate *x509.Certificate, certificateErr error, ibmIntermediate *x509.Certificate, ibmIntermediateErr error, at time.Time, crls CRLStore) (*x509.RevocationList, *CRLInfo, error) {
+	if certificateErr != nil {
+		return nil, nil, fmt.Errorf("failed to parse certificate - %v", certificateErr)
+	}
+
+	if ibmIntermediateErr != nil {
+		return nil, nil, fmt.Errorf("failed to parse IBM intermediate certificate - %v", ibmIntermediateErr)
+	}
+
+	crlURL, err := crlDistributionPoint(certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to extract CRL URL - %v", err)
+	}
+
+	crlInfo := &CRLInfo{URL: crlURL}
+	crlData, err := fetchCRL(crlURL, crls, at)
+	if err != nil {
+		return nil, crlInfo, fmt.Errorf("failed to download CRL - %v", err)
+	}
+
+	crl, err := parseCRL(crlData)
+	if err != nil {
+		return nil, crlInfo, fmt.Errorf("failed to parse CRL - %v", err)
+	}
+	crlInfo = newCRLInfo(crlURL, crl)
+
+	if err := validateCRLWindow(crl, at); err != nil {
+		return nil, crlInfo, err
+	}
+
+	if err := crl.CheckSignatureFrom(ibmIntermediate); err != nil {
+		return nil, crlInfo, fmt.Errorf("CRL signature verification failed - %v", err)
+	}
+
+	return crl, crlInfo, nil
+}
+
+// passed reports whether a stage was run and passed.
+func (r *ValidationReport) passed(stage string) bool {
+	result, ok := r.Stage(stage)
+	return ok && result.Status == StagePassed
+}
+
+// pass records a passed stage.
+func (r *ValidationReport) pass(stage, detail string, certificates []CertificateInfo, crl *CRLInfo) {
+	r.Stages = append(r.Stages, StageResult{Stage: stage, Status: StagePassed, Detail: detail, Certificates: certificates, CRL: crl})
+}
+
+// fail records a failed stage.
+func (r *ValidationReport) fail(stage, detail string, certificates []CertificateInfo, crl *CRLInfo) {
+	r.Stages = append(r.Stages, StageResult{Stage: stage, Status: StageFailed, Detail: strings.TrimSpace(detail), Certificates: certificates, CRL: crl})
+}
+
+// notRun records a stage that could not run because the stage it depends on did not pass.
+func (r *ValidationReport) notRun(stage, dependency string, certificates []CertificateInfo) {
+	r.Stages = append(r.Stages, StageResult{Stage: stage, Status: StageNotRun, Detail: fmt.Sprintf("%s stage did not pass", dependency), Certificates: certificates})
+}
+
+// namedCertificate is a parsed certificate and its role in a validation stage.
+type namedCertificate struct {
+	role        string
+	certificate *x509.Certificate
+}
+
+// certificateInfos describes the certificates that could be parsed.
+func certificateInfos(certificates ...namedCertificate) []CertificateInfo {
+	infos := make([]CertificateInfo, 0, len(certificates))
+	for _, named := range certificates {
+		if named.certificate == nil {
+			continue
+		}
+		infos = append(infos, CertificateInfo{
+			Role:      named.role,
+			Subject:   named.certificate.Subject.String(),
+			Issuer:    named.certificate.Issuer.String(),
+			Serial:    fmt.Sprintf("%X", named.certificate.SerialNumber),
+			NotBefore: named.certificate.NotBefore.UTC(),
+			NotAfter:  named.certificate.NotAfter.UTC(),
+		})
+	}
+	return infos
+}
+
+// newCRLInfo describes a parsed CRL.
+func newCRLInfo(crlURL string, crl *x509.RevocationList) *CRLInfo {
+	return &CRLInfo{
+		URL:        crlURL,
+		Issuer:     crl.Issuer.String(),
+		ThisUpdate: crl.ThisUpdate.UTC(),
+		NextUpdate: crl.NextUpdate.UTC(),
+	}
+}
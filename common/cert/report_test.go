@@ -0,0 +1,108 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cert
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stageStatuses returns the status of every stage of a report keyed by stage name.
+func stageStatuses(report *ValidationReport) map[string]string {
+	statuses := make(map[string]string, len(report.Stages))
+	for _, stage := range report.Stages {
+		statuses[stage.Stage] = stage.Status
+	}
+	return statuses
+}
+
+// Test EncryptionCertificateValidationReport reports every stage of a valid certificate document
+func TestEncryptionCertificateValidationReport(t *testing.T) {
+	fixture := newDocValidationFixture(t, docFixtureOptions{})
+
+	report, err := EncryptionCertificateValidationReport(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, time.Time{})
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.NoError(t, report.Err())
+
+	stages := make([]string, 0, len(report.Stages))
+	for _, stage := range report.Stages {
+		stages = append(stages, stage.Stage)
+		assert.Equal(t, StagePassed, stage.Status, stage.Stage)
+		assert.NotEmpty(t, stage.Certificates, stage.Stage)
+	}
+	assert.Equal(t, []string{StageCAVerify, StageSigningCertVerify, StageDocSignatureVerify, StageDateVerify, StageCRLSignatureVerify, StageSerialRevoked}, stages)
+
+	caVerify, ok := report.Stage(StageCAVerify)
+	require.True(t, ok)
+	require.NotNil(t, caVerify.CRL)
+	assert.NotEmpty(t, caVerify.CRL.URL)
+	assert.Equal(t, RoleDigicertIntermediate, caVerify.Certificates[0].Role)
+	assert.Equal(t, RoleDigicertRoot, caVerify.Certificates[1].Role)
+
+	crlVerify, ok := report.Stage(StageCRLSignatureVerify)
+	require.True(t, ok)
+	require.NotNil(t, crlVerify.CRL)
+	assert.Equal(t, crlVerify.Certificates[1].Subject, crlVerify.CRL.Issuer)
+	assert.True(t, crlVerify.CRL.ThisUpdate.Before(crlVerify.CRL.NextUpdate))
+
+	encoded, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"stage":"serial revoked"`)
+}
+
+// Test validation reports name the failed stage and skip the stages depending on it
+func TestCertificateValidationReport_Failures(t *testing.T) {
+	fixture := newDocValidationFixture(t, docFixtureOptions{revokeIbmIntermediate: true, revokeAttestation: true})
+
+	report, err := EncryptionCertificateValidationReport(fixture.encryptionCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, time.Time{})
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	assert.Equal(t, map[string]string{
+		StageCAVerify:           StagePassed,
+		StageSigningCertVerify:  StageFailed,
+		StageDocSignatureVerify: StageNotRun,
+		StageDateVerify:         StagePassed,
+		StageCRLSignatureVerify: StagePassed,
+		StageSerialRevoked:      StagePassed,
+	}, stageStatuses(report))
+	assert.ErrorContains(t, report.Err(), StageSigningCertVerify+" failed")
+
+	fixture = newDocValidationFixture(t, docFixtureOptions{revokeAttestation: true})
+	report, err = AttestationCertificateValidationReport(fixture.attestationCert, fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, time.Time{})
+	require.NoError(t, err)
+	assert.False(t, report.Valid)
+	serialRevoked, ok := report.Stage(StageSerialRevoked)
+	require.True(t, ok)
+	assert.Equal(t, StageFailed, serialRevoked.Status)
+	assert.Equal(t, "certificate is listed in CRL", serialRevoked.Detail)
+	assert.NotNil(t, serialRevoked.CRL)
+
+	report, err = EncryptionCertificateValidationReport("invalid", fixture.ibmIntermediateCert, fixture.digicertIntermediateCert, fixture.digicertRootCert, nil, time.Time{})
+	require.NoError(t, err)
+	statuses := stageStatuses(report)
+	assert.Equal(t, StageFailed, statuses[StageDocSignatureVerify])
+	assert.Equal(t, StageNotRun, statuses[StageDateVerify])
+	assert.Equal(t, StageFailed, statuses[StageCRLSignatureVerify])
+	assert.Equal(t, StageNotRun, statuses[StageSerialRevoked])
+
+	_, err = EncryptionCertificateValidationReport("", "", "", "", nil, time.Time{})
+	assert.EqualError(t, err, "required parameter is missing")
+}
@@ -16,8 +16,15 @@
 package encrypt
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"os"
+	"time"
 
 	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
 )
@@ -197,6 +204,7 @@ func EncryptFinalStr(encryptedPassword, encryptedContract, confidentialComputing
 // CreateSigningCert generates a signing certificate using a Certificate Authority (CA).
 // It can either generate a Certificate Signing Request (CSR) from the provided CSR data and private key,
 // or use an existing CSR in PEM format. The certificate is signed by the CA and returned as Base64-encoded data.
+// The certificate is valid from now; see [CreateSigningCertAt].
 //
 // Parameters:
 //   - privateKey: RSA private key (PEM format) for generating CSR (ignored if csrPemData is provided)
@@ -210,6 +218,26 @@ func EncryptFinalStr(encryptedPassword, encryptedContract, confidentialComputing
 //   - Base64-encoded signing certificate
 //   - Error if OpenSSL is not found, CSR generation fails, or certificate signing fails
 func CreateSigningCert(privateKey, cacert, cakey, csrData, csrPemData string, expiryDays int) (string, error) {
+	return CreateSigningCertAt(privateKey, cacert, cakey, csrData, csrPemData, expiryDays, time.Now())
+}
+
+// CreateSigningCertAt generates a signing certificate like [CreateSigningCert] whose validity period
+// starts at notBefore and ends expiryDays later, e.g. to issue an already expired or a future-dated
+// certificate.
+//
+// Parameters:
+//   - privateKey: RSA private key (PEM format) for generating CSR (ignored if csrPemData is provided)
+//   - cacert: CA certificate (PEM format) used to sign the certificate
+//   - cakey: CA private key (PEM format) used to sign the certificate
+//   - csrData: JSON string with CSR fields (country, state, location, org, unit, domain, mail) - ignored if csrPemData is provided
+//   - csrPemData: Existing CSR in PEM format (if empty, generates new CSR from csrData and privateKey)
+//   - expiryDays: Number of days until certificate expiration, counted from notBefore
+//   - notBefore: Start of the validity period of the certificate
+//
+// Returns:
+//   - Base64-encoded signing certificate
+//   - Error if OpenSSL is not found, CSR generation fails, or certificate signing fails
+func CreateSigningCertAt(privateKey, cacert, cakey, csrData, csrPemData string, expiryDays int, notBefore time.Time) (string, error) {
 	err := OpensslCheck()
 	if err != nil {
 		return "", fmt.Errorf("openssl not found - %v", err)
@@ -258,7 +286,7 @@ func CreateSigningCert(privateKey, cacert, cakey, csrData, csrPemData string, ex
 	}
 	defer gen.RemoveTempFile(caKeyPath)
 
-	signingCert, err := CreateCert(csrPath, caCertPath, caKeyPath, expiryDays)
+	signingCert, err := CreateCertAt(csrPath, caCertPath, caKeyPath, expiryDays, notBefore)
 	if err != nil {
 		return "", fmt.Errorf("failed to create signing certificate - %v", err)
 	}
@@ -267,28 +295,121 @@ func CreateSigningCert(privateKey, cacert, cakey, csrData, csrPemData string, ex
 }
 
 // CreateCert creates an X.509 certificate by signing a CSR with a Certificate Authority.
-// It uses OpenSSL to generate a certificate from the CSR, signed by the provided CA certificate and key.
+// The certificate is valid from now; see [CreateCertAt].
 //
 // Parameters:
 //   - csrPath: File path to the Certificate Signing Request (PEM format)
 //   - caCertPath: File path to the CA certificate (PEM format)
-//   - caKeyPath: File path to the CA private key (PEM format)
+//   - caKeyPath: File path to the CA private key (PEM format, RSA or EC key in PKCS #1, SEC 1 or PKCS #8 encoding)
 //   - expiryDays: Number of days until certificate expiration
 //
 // Returns:
 //   - Signed certificate in PEM format
-//   - Error if OpenSSL execution fails or certificate generation fails
+//   - Error if a file cannot be read or parsed, the CSR signature is invalid or certificate generation fails
 func CreateCert(csrPath, caCertPath, caKeyPath string, expiryDays int) (string, error) {
-	// -CAcreateserial writes <caCertPath>.srl alongside the CA cert file.
-	// Defer its removal — it is the only file CreateCert itself causes to be created.
-	defer gen.RemoveTempFile(caCertPath + ".srl")
+	return CreateCertAt(csrPath, caCertPath, caKeyPath, expiryDays, time.Now())
+}
+
+// CreateCertAt creates an X.509 certificate by signing a CSR with a Certificate Authority. The
+// validity period starts at notBefore and ends expiryDays later. The certificate takes the subject and
+// public key of the CSR and gets a random serial number.
+//
+// Parameters:
+//   - csrPath: File path to the Certificate Signing Request (PEM format)
+//   - caCertPath: File path to the CA certificate (PEM format)
+//   - caKeyPath: File path to the CA private key (PEM format, RSA or EC key in PKCS #1, SEC 1 or PKCS #8 encoding)
+//   - expiryDays: Number of days until certificate expiration, counted from notBefore
+//   - notBefore: Start of the validity period of the certificate
+//
+// Returns:
+//   - Signed certificate in PEM format
+//   - Error if a file cannot be read or parsed, the CSR signature is invalid or certificate generation fails
+func CreateCertAt(csrPath, caCertPath, caKeyPath string, expiryDays int, notBefore time.Time) (string, error) {
+	csr, err := readPEMFile(csrPath, "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST")
+	if err != nil {
+		return "", fmt.Errorf("failed to read CSR - %v", err)
+	}
+	request, err := x509.ParseCertificateRequest(csr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse CSR - %v", err)
+	}
+	if err := request.CheckSignature(); err != nil {
+		return "", fmt.Errorf("CSR signature verification failed - %v", err)
+	}
 
-	signingCert, err := gen.ExecCommand(gen.GetOpenSSLPath(), "", "x509", "-req", "-in", csrPath, "-CA", caCertPath, "-CAkey", caKeyPath, "-CAcreateserial", "-days", fmt.Sprintf("%d", expiryDays))
+	caCertDER, err := readPEMFile(caCertPath, "CERTIFICATE")
 	if err != nil {
-		return "", fmt.Errorf("failed to execute openssl command - %v", err)
+		return "", fmt.Errorf("failed to read CA certificate - %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caCertDER)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse CA certificate - %v", err)
 	}
 
-	return signingCert, nil
+	caKeyDER, err := readPEMFile(caKeyPath, "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY")
+	if err != nil {
+		return "", fmt.Errorf("failed to read CA key - %v", err)
+	}
+	caKey, err := parseSigner(caKeyDER)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse CA key - %v", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate serial number - %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		RawSubject:   request.RawSubject,
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(0, 0, expiryDays),
+	}
+
+	certificate, err := x509.CreateCertificate(rand.Reader, template, caCert, request.PublicKey, caKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to create certificate - %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})), nil
+}
+
+// readPEMFile reads a file and returns the content of its first PEM block of one of the given types.
+func readPEMFile(path string, types ...string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		for _, blockType := range types {
+			if block.Type == blockType {
+				return block.Bytes, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no PEM encoded %s found", types[0])
+}
+
+// parseSigner parses a PKCS #1, SEC 1 or PKCS #8 private key.
+func parseSigner(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
 }
 
 // SignContract creates a digital signature for an encrypted contract using an RSA private key.
@@ -305,6 +426,21 @@ func CreateCert(csrPath, caCertPath, caKeyPath string, expiryDays int) (string,
 //   - Base64-encoded SHA-256 signature of the combined contract sections
 //   - Error if OpenSSL is not found or signing fails
 func SignContract(encryptedWorkload, encryptedEnv, privateKey, password string) (string, error) {
+	return SignData(encryptedWorkload+encryptedEnv, privateKey, password)
+}
+
+// SignData creates a SHA-256 signature of arbitrary data using an RSA private key.
+// The signature can be verified with [VerifySignature] after Base64 decoding.
+//
+// Parameters:
+//   - data: Data to sign
+//   - privateKey: RSA private key (PEM format) used to sign the data
+//   - password: Optional password to unlock the private key if it's encrypted (empty string "" for unencrypted keys)
+//
+// Returns:
+//   - Base64-encoded SHA-256 signature of the data
+//   - Error if OpenSSL is not found or signing fails
+func SignData(data, privateKey, password string) (string, error) {
 	err := OpensslCheck()
 	if err != nil {
 		return "", fmt.Errorf("openssl not found - %v", err)
@@ -314,8 +450,6 @@ func SignContract(encryptedWorkload, encryptedEnv, privateKey, password string)
 		return "", fmt.Errorf("private key is encrypted but no password provided - use the password parameter to unlock the key")
 	}
 
-	combinedContract := encryptedWorkload + encryptedEnv
-
 	privateKeyPath, err := gen.CreateTempFile(privateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file - %v", err)
@@ -325,12 +459,12 @@ func SignContract(encryptedWorkload, encryptedEnv, privateKey, password string)
 	args := []string{"dgst", "-sha256", "-sign", privateKeyPath}
 	args = gen.AppendPasswordFdArgs(args, password)
 
-	workloadEnvSignature, err := gen.ExecCommandWithPassword(gen.GetOpenSSLPath(), combinedContract, password, args...)
+	signature, err := gen.ExecCommandWithPassword(gen.GetOpenSSLPath(), data, password, args...)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute openssl command - %v", err)
 	}
 
-	return gen.EncodeToBase64([]byte(workloadEnvSignature)), nil
+	return gen.EncodeToBase64([]byte(signature)), nil
 }
 
 // GenFinalSignedContract assembles the final signed contract in YAML format.
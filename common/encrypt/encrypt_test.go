@@ -16,12 +16,20 @@
 package encrypt
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	_ "embed"
 	"encoding/json"
+	"encoding/pem"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
@@ -363,6 +371,24 @@ func TestSignContractInvalidPrivateKey(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to execute openssl command")
 }
 
+// Testcase to check if SignData() creates a signature that VerifySignature() accepts
+func TestSignDataVerifySignature(t *testing.T) {
+	privateKey, err := gen.ReadDataFromFile(samplePrivateKeyPath)
+	assert.NoError(t, err)
+
+	signature, err := SignData("certificate bundle", privateKey, "")
+	assert.NoError(t, err)
+
+	publicKey, err := GeneratePublicKey(privateKey, "")
+	assert.NoError(t, err)
+
+	decodedSignature, err := gen.DecodeBase64String(signature)
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifySignature("certificate bundle", decodedSignature, publicKey))
+	assert.Error(t, VerifySignature("modified bundle", decodedSignature, publicKey))
+}
+
 // Testcase to check if RandomPasswordGenerator() works correctly
 func TestRandomPasswordGeneratorSuccess(t *testing.T) {
 	password, err := RandomPasswordGenerator()
@@ -376,6 +402,91 @@ func TestEncryptPasswordEmptyCertificate(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// Testcase to check if CreateSigningCertAt() issues certificates valid from the given time
+func TestCreateSigningCertAt(t *testing.T) {
+	privateKey, err := gen.ReadDataFromFile(samplePrivateKeyPath)
+	if err != nil {
+		t.Errorf("failed to read private key - %v", err)
+	}
+
+	cacert, err := gen.ReadDataFromFile(sampleCaCertPath)
+	if err != nil {
+		t.Errorf("failed to read CA certificate - %v", err)
+	}
+
+	caKey, err := gen.ReadDataFromFile(sampleCaKeyPath)
+	if err != nil {
+		t.Errorf("failed to read CA key - %v", err)
+	}
+
+	csr, err := gen.ReadDataFromFile(sampleCsrFilePath)
+	if err != nil {
+		t.Errorf("failed to read CSR file - %v", err)
+	}
+
+	for _, notBefore := range []time.Time{
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),             // expired
+		time.Now().UTC().AddDate(1, 0, 0).Truncate(time.Second), // not yet valid
+	} {
+		signingCert, err := CreateSigningCertAt(privateKey, cacert, caKey, "", csr, 10, notBefore)
+		if err != nil {
+			t.Errorf("failed to create signing certificate - %v", err)
+		}
+
+		decoded, err := gen.DecodeBase64String(signingCert)
+		assert.NoError(t, err)
+		block, _ := pem.Decode([]byte(decoded))
+		if assert.NotNil(t, block) {
+			parsed, err := x509.ParseCertificate(block.Bytes)
+			assert.NoError(t, err)
+			assert.True(t, notBefore.Equal(parsed.NotBefore))
+			assert.True(t, notBefore.AddDate(0, 0, 10).Equal(parsed.NotAfter))
+			assert.Equal(t, "HPVS", parsed.Subject.CommonName)
+		}
+	}
+}
+
+// Testcase to check if CreateCert() signs with a CA key in SEC 1 "EC PRIVATE KEY" encoding
+func TestCreateCertEcCaKey(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "EC CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caCertDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	caCertPath := filepath.Join(dir, "ca.crt")
+	caKeyPath := filepath.Join(dir, "ca.key")
+	assert.NoError(t, os.WriteFile(caCertPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCertDER}), 0600))
+	assert.NoError(t, os.WriteFile(caKeyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: caKeyDER}), 0600))
+
+	signingCert, err := CreateCert(sampleCsrFilePath, caCertPath, caKeyPath, sampleExpiryDays)
+	if err != nil {
+		t.Fatalf("failed to create certificate - %v", err)
+	}
+
+	block, _ := pem.Decode([]byte(signingCert))
+	if assert.NotNil(t, block) {
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		assert.Equal(t, "HPVS", parsed.Subject.CommonName)
+		assert.Equal(t, x509.ECDSAWithSHA256, parsed.SignatureAlgorithm)
+		caCert, err := x509.ParseCertificate(caCertDER)
+		assert.NoError(t, err)
+		assert.NoError(t, parsed.CheckSignatureFrom(caCert))
+	}
+}
+
 // Testcase to check if CreateSigningCert() handles invalid CA certificate
 func TestCreateSigningCertInvalidCaCert(t *testing.T) {
 	privateKey, err := gen.ReadDataFromFile(samplePrivateKeyPath)
@@ -0,0 +1,191 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package general
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultFetchTimeout   = 30 * time.Second
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	// Largest response body accepted, well above the size of certificates and CRLs
+	maxDownloadSize = 16 << 20
+)
+
+// defaultFetcher sends requests when no fetcher is configured. Unlike http.DefaultClient it gives up
+// on a server that stops responding.
+var defaultFetcher Fetcher = &http.Client{Timeout: defaultFetchTimeout}
+
+// Fetcher sends HTTP requests. *http.Client implements Fetcher, so callers can pass a client
+// configured with proxies, custom CA roots or timeouts, or a stand-in for tests.
+type Fetcher interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DownloadConfig configures how files are downloaded.
+type DownloadConfig struct {
+	// Fetcher sends the requests. An HTTP client with a 30 second timeout is used when nil.
+	Fetcher Fetcher
+	// UserAgent is sent as the User-Agent header when set.
+	UserAgent string
+	// MaxAttempts is the number of attempts made for each request. Defaults to 3; set 1 to disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. It doubles after every retry. Defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries. Defaults to 10s.
+	MaxBackoff time.Duration
+}
+
+// CertificateDownloaderContext downloads a file from a URL using the given download configuration.
+// Network errors and 429 or 5xx responses are retried with exponential backoff; waiting stops as soon
+// as the context is cancelled.
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the download and of retry waits
+//   - url: URL to download
+//   - config: Download configuration
+//
+// Returns:
+//   - Response body as string
+//   - Error if the request fails, the response status is not 2xx or the context is cancelled
+func CertificateDownloaderContext(ctx context.Context, url string, config DownloadConfig) (string, error) {
+	resp, err := fetchWithRetry(ctx, http.MethodGet, url, config)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxDownloadSize {
+		return "", fmt.Errorf("response body exceeds %d bytes", maxDownloadSize)
+	}
+
+	return string(body), nil
+}
+
+// CheckUrlExistsContext verifies if a URL is accessible by sending an HTTP HEAD request using the
+// given download configuration. Retries follow the same rules as [CertificateDownloaderContext].
+//
+// Parameters:
+//   - ctx: Context controlling cancellation of the request and of retry waits
+//   - url: URL to check
+//   - config: Download configuration
+//
+// Returns:
+//   - true if URL returns 2xx status code, false otherwise
+//   - Error if HTTP request fails or the context is cancelled
+func CheckUrlExistsContext(ctx context.Context, url string, config DownloadConfig) (bool, error) {
+	resp, err := fetchWithRetry(ctx, http.MethodHead, url, config)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300, nil
+}
+
+// fetchWithRetry sends a request and retries network errors and retryable status codes with backoff.
+// The response of the last attempt is returned when all attempts receive a retryable status.
+func fetchWithRetry(ctx context.Context, method, url string, config DownloadConfig) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	fetcher := config.Fetcher
+	if fetcher == nil {
+		fetcher = defaultFetcher
+	}
+	maxAttempts := config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	backoff := config.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultInitialBackoff
+	}
+	maxBackoff := config.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if config.UserAgent != "" {
+			req.Header.Set("User-Agent", config.UserAgent)
+		}
+
+		resp, err := fetcher.Do(req)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			closeResponse(resp)
+			return nil, ctxErr
+		}
+		if attempt >= maxAttempts {
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+		closeResponse(resp)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// isRetryableStatus reports whether a response status is worth retrying.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// closeResponse drains and closes a response body so the connection can be reused.
+func closeResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
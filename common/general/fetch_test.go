@@ -0,0 +1,152 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package general
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testcase to check if CertificateDownloaderContext() retries 5xx responses and sends the user agent
+func TestCertificateDownloaderContextRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "contract-go-test", r.UserAgent())
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("certificate"))
+	}))
+	defer server.Close()
+
+	body, err := CertificateDownloaderContext(context.Background(), server.URL, DownloadConfig{
+		Fetcher:        server.Client(),
+		UserAgent:      "contract-go-test",
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "certificate", body)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+// Testcase to check if CertificateDownloaderContext() does not retry client errors and reports the status
+func TestCertificateDownloaderContextNotFound(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := CertificateDownloaderContext(context.Background(), server.URL, DownloadConfig{
+		Fetcher:        server.Client(),
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	})
+	assert.EqualError(t, err, "unexpected HTTP status: 404 Not Found")
+	assert.Equal(t, int32(1), requests.Load())
+
+	exists, err := CheckUrlExistsContext(context.Background(), server.URL, DownloadConfig{Fetcher: server.Client()})
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// Testcase to check if CertificateDownloaderContext() rejects response bodies larger than a certificate or CRL can be
+func TestCertificateDownloaderContextTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, maxDownloadSize+1))
+	}))
+	defer server.Close()
+
+	_, err := CertificateDownloaderContext(context.Background(), server.URL, DownloadConfig{Fetcher: server.Client()})
+	assert.EqualError(t, err, "response body exceeds 16777216 bytes")
+}
+
+// Testcase to check if CertificateDownloaderContext() stops waiting for a retry when the context is cancelled
+func TestCertificateDownloaderContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := CertificateDownloaderContext(ctx, server.URL, DownloadConfig{
+		Fetcher:        server.Client(),
+		MaxAttempts:    5,
+		InitialBackoff: time.Minute,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+// Testcase to check if CheckUrlExistsContext() returns the status of the last attempt when retries are exhausted
+func TestCheckUrlExistsContextRetriesExhausted(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		requests.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	exists, err := CheckUrlExistsContext(context.Background(), server.URL, DownloadConfig{
+		Fetcher:        server.Client(),
+		MaxAttempts:    2,
+		InitialBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+// Testcase to check if CertificateDownloaderContext() retries and uses a client with a timeout by default
+func TestCertificateDownloaderContextDefaults(t *testing.T) {
+	client, ok := defaultFetcher.(*http.Client)
+	require.True(t, ok)
+	assert.Equal(t, defaultFetchTimeout, client.Timeout)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < defaultMaxAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("certificate"))
+	}))
+	defer server.Close()
+
+	body, err := CertificateDownloaderContext(context.Background(), server.URL, DownloadConfig{InitialBackoff: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, "certificate", body)
+	assert.Equal(t, int32(defaultMaxAttempts), requests.Load())
+
+	requests.Store(0)
+	_, err = CertificateDownloaderContext(context.Background(), server.URL, DownloadConfig{MaxAttempts: 1})
+	assert.EqualError(t, err, "unexpected HTTP status: 503 Service Unavailable")
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -19,6 +19,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
@@ -27,12 +28,13 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -484,7 +486,8 @@ func MapToYaml(m map[string]interface{}) (string, error) {
 }
 
 // KeyValueInjector adds or updates a key-value pair in a YAML string.
-// It parses the YAML contract, injects the new key-value pair, and returns the modified YAML.
+// It edits the parsed YAML document in place, so comments, key order and scalar styles of the
+// rest of the document are preserved. An existing key keeps its position; a new key is appended.
 //
 // Parameters:
 //   - contract: YAML string to modify
@@ -495,45 +498,260 @@ func MapToYaml(m map[string]interface{}) (string, error) {
 //   - Modified YAML string with the injected key-value pair
 //   - Error if YAML parsing or marshaling fails
 func KeyValueInjector(contract, key, value string) (string, error) {
-	yamlData := make(map[string]interface{})
-	if err := yaml.Unmarshal([]byte(contract), &yamlData); err != nil {
+	return SetPath(contract, []string{key}, value)
+}
+
+// SetPath sets the value at a path in a YAML document, preserving comments, key order and scalar styles.
+// Missing intermediate maps are created. List elements are addressed by index ("0" or "[0]"), and the
+// index equal to the list length appends a new element.
+//
+// Parameters:
+//   - document: YAML string to modify (may be empty)
+//   - path: Keys from the document root to the value, e.g. []string{"logging", "syslog", "port"}
+//   - value: Value to set; strings, numbers, booleans, maps and slices are encoded as YAML
+//
+// Returns:
+//   - Modified YAML string
+//   - Error if the path is empty, YAML parsing fails or the path crosses a scalar value
+func SetPath(document string, path []string, value interface{}) (string, error) {
+	if len(path) == 0 {
+		return "", fmt.Errorf("path is empty")
+	}
+
+	root, err := parseYamlDocument(document)
+	if err != nil {
 		return "", fmt.Errorf("failed to parse contract - %v", err)
 	}
 
-	yamlData[key] = value
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to encode value - %v", err)
+	}
 
-	modifiedYAMLBytes, err := yaml.Marshal(yamlData)
-	if err != nil {
+	if err := setNodePath(root.Content[0], path, valueNode); err != nil {
 		return "", err
 	}
 
-	return string(modifiedYAMLBytes), nil
+	return encodeYamlDocument(root, detectYamlIndent(document))
 }
 
-// CertificateDownloader downloads a certificate from a URL.
-// It sends an HTTP GET request to the specified URL and returns the response body.
+// DeletePath removes the value at a path from a YAML document, preserving comments, key order and
+// scalar styles of the rest of the document. Deleting a path that does not exist is a no-op.
 //
 // Parameters:
-//   - url: URL to download the certificate from
+//   - document: YAML string to modify
+//   - path: Keys from the document root to the value to remove; list elements are addressed by index
 //
 // Returns:
-//   - Certificate content as string
-//   - Error if HTTP request fails or response cannot be read
-func CertificateDownloader(url string) (string, error) {
-	// Send a GET request to the URL
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", err
+//   - Modified YAML string
+//   - Error if the path is empty, YAML parsing fails or the path crosses a scalar value
+func DeletePath(document string, path []string) (string, error) {
+	if len(path) == 0 {
+		return "", fmt.Errorf("path is empty")
 	}
-	defer resp.Body.Close()
 
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
+	root, err := parseYamlDocument(document)
 	if err != nil {
+		return "", fmt.Errorf("failed to parse contract - %v", err)
+	}
+
+	if err := deleteNodePath(root.Content[0], path); err != nil {
 		return "", err
 	}
 
-	return string(body), nil
+	return encodeYamlDocument(root, detectYamlIndent(document))
+}
+
+// EncodeYamlDocument renders a YAML node with the indentation used by the document it was parsed from,
+// so that edits keep the layout of the original document.
+//
+// Parameters:
+//   - root: YAML node to render, usually the document node parsed from original
+//   - original: YAML string the node was parsed from
+//
+// Returns:
+//   - Rendered YAML string
+//   - Error if encoding fails
+func EncodeYamlDocument(root *yaml.Node, original string) (string, error) {
+	return encodeYamlDocument(root, detectYamlIndent(original))
+}
+
+// parseYamlDocument parses a YAML string into a document node whose root is a map or list.
+// An empty document yields an empty map.
+func parseYamlDocument(document string) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(document), &root); err != nil {
+		return nil, err
+	}
+
+	if len(root.Content) == 0 {
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}, nil
+	}
+
+	if root.Content[0].Kind != yaml.MappingNode && root.Content[0].Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("document root is not a map or list")
+	}
+
+	return &root, nil
+}
+
+// setNodePath sets value at path below node, creating missing maps.
+func setNodePath(node *yaml.Node, path []string, value *yaml.Node) error {
+	segment := path[0]
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != segment {
+				continue
+			}
+			if len(path) == 1 {
+				copyNodeValue(node.Content[i+1], value)
+				return nil
+			}
+			child := node.Content[i+1]
+			if child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+				*child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			return setNodePath(child, path[1:], value)
+		}
+
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}
+		if len(path) == 1 {
+			node.Content = append(node.Content, keyNode, value)
+			return nil
+		}
+		child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		node.Content = append(node.Content, keyNode, child)
+		return setNodePath(child, path[1:], value)
+	case yaml.SequenceNode:
+		index, err := sequenceIndex(segment)
+		if err != nil {
+			return err
+		}
+		if index > len(node.Content) {
+			return fmt.Errorf("list index %d out of range", index)
+		}
+		if index == len(node.Content) {
+			child := value
+			if len(path) > 1 {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			node.Content = append(node.Content, child)
+			if len(path) == 1 {
+				return nil
+			}
+		}
+		if len(path) == 1 {
+			copyNodeValue(node.Content[index], value)
+			return nil
+		}
+		return setNodePath(node.Content[index], path[1:], value)
+	default:
+		return fmt.Errorf("cannot set %q - parent value is not a map or list", segment)
+	}
+}
+
+// deleteNodePath removes the value at path below node.
+func deleteNodePath(node *yaml.Node, path []string) error {
+	segment := path[0]
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != segment {
+				continue
+			}
+			if len(path) == 1 {
+				node.Content = append(node.Content[:i], node.Content[i+2:]...)
+				return nil
+			}
+			return deleteNodePath(node.Content[i+1], path[1:])
+		}
+		return nil
+	case yaml.SequenceNode:
+		index, err := sequenceIndex(segment)
+		if err != nil {
+			return err
+		}
+		if index >= len(node.Content) {
+			return nil
+		}
+		if len(path) == 1 {
+			node.Content = append(node.Content[:index], node.Content[index+1:]...)
+			return nil
+		}
+		return deleteNodePath(node.Content[index], path[1:])
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot delete %q - parent value is not a map or list", segment)
+}
+
+// copyNodeValue replaces the content of target with value, keeping the comments attached to target.
+func copyNodeValue(target, value *yaml.Node) {
+	headComment, lineComment, footComment := target.HeadComment, target.LineComment, target.FootComment
+	*target = *value
+	if target.HeadComment == "" {
+		target.HeadComment = headComment
+	}
+	if target.LineComment == "" {
+		target.LineComment = lineComment
+	}
+	if target.FootComment == "" {
+		target.FootComment = footComment
+	}
+}
+
+// sequenceIndex parses a list index path segment written as "n" or "[n]".
+func sequenceIndex(segment string) (int, error) {
+	index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]"))
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid list index %q", segment)
+	}
+	return index, nil
+}
+
+// detectYamlIndent returns the indentation width of the first nested map line of a YAML document, defaulting to 2.
+func detectYamlIndent(document string) int {
+	for _, line := range strings.Split(document, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if indent > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(trimmed, "- ") {
+			return indent
+		}
+	}
+	return 2
+}
+
+// encodeYamlDocument renders a YAML document node with the given indentation.
+func encodeYamlDocument(root *yaml.Node, indent int) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(root); err != nil {
+		return "", fmt.Errorf("failed to encode YAML - %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode YAML - %v", err)
+	}
+	return buf.String(), nil
+}
+
+// CertificateDownloader downloads a certificate from a URL.
+// It sends an HTTP GET request to the specified URL with the default HTTP client and returns the response body.
+// Use CertificateDownloaderContext to pass a custom client, retries or a context.
+//
+// Parameters:
+//   - url: URL to download the certificate from
+//
+// Returns:
+//   - Certificate content as string
+//   - Error if HTTP request fails, the response status is not 2xx or the response cannot be read
+func CertificateDownloader(url string) (string, error) {
+	return CertificateDownloaderContext(context.Background(), url, DownloadConfig{})
 }
 
 // GetEncryptPassWorkload extracts the encrypted password and workload from encrypted data.
@@ -555,8 +773,228 @@ func GetEncryptPassWorkload(encryptedData string) (string, string, error) {
 	return parts[1], parts[2], nil
 }
 
+// Prefixes of the encrypted token formats produced by the library.
+const (
+	EncryptedTokenPrefixHyperProtectBasic = "hyper-protect-basic"
+	EncryptedTokenPrefixContractBasic     = "contract-basic"
+	EncryptedTokenPrefixSealed            = "sealed"
+)
+
+const (
+	// Header written by "openssl enc" in front of the salt of salted ciphertexts.
+	opensslSaltedHeader = "Salted__"
+	// Salt header plus the 8 byte salt.
+	opensslSaltedHeaderLength = 16
+	aesBlockSize              = 16
+	// Plausible range of RSA key sizes for wrapped keys.
+	minWrappedKeyBits = 1024
+	maxWrappedKeyBits = 8192
+	// Length of the AES-GCM IV used by sealed secrets.
+	sealedIvLength = 12
+)
+
+// EncryptedToken is the parsed representation of an encrypted contract section or sealed secret.
+//
+// For "hyper-protect-basic" and "contract-basic" tokens, WrappedKey is the RSA encrypted password
+// and Ciphertext the AES-256-CBC encrypted data (including the OpenSSL salt header).
+// For "sealed" tokens, WrappedKey is the RSA encrypted AES key and Ciphertext the AES-256-GCM
+// encrypted data; IV, KeyID and Signature are set from the JWS envelope.
+type EncryptedToken struct {
+	Prefix      string
+	WrappedKey  []byte
+	Ciphertext  []byte
+	KeySizeBits int
+	IV          []byte
+	KeyID       string
+	Signature   []byte
+}
+
+// sealedEnvelope is the JSON payload of a sealed secret.
+type sealedEnvelope struct {
+	Version       string `json:"version"`
+	Type          string `json:"type"`
+	KeyID         string `json:"key_id"`
+	EncryptedKey  string `json:"encrypted_key"`
+	EncryptedData string `json:"encrypted_data"`
+	WrapType      string `json:"wrap_type"`
+	IV            string `json:"iv"`
+}
+
+// ParseEncryptedToken parses and validates an encrypted token.
+// It checks the prefix against the platform, decodes the wrapped key and the ciphertext,
+// checks that their lengths are plausible and reports the RSA key size implied by the wrapped key.
+//
+// Parameters:
+//   - token: Encrypted token, e.g. "hyper-protect-basic.<password>.<data>", "contract-basic.<password>.<data>"
+//     or "sealed.<header>.<payload>.<signature>"
+//   - confidentialComputingOs: Expected platform ("hpvs", "ccrt", "ccrv", "ccco"); empty accepts any prefix
+//
+// Returns:
+//   - Parsed encrypted token
+//   - Error if the prefix does not match the platform or the token is malformed
+func ParseEncryptedToken(token, confidentialComputingOs string) (*EncryptedToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("malformed encrypted token: token is empty")
+	}
+
+	prefix, _, _ := strings.Cut(token, ".")
+	if err := checkEncryptedTokenPrefix(prefix, confidentialComputingOs); err != nil {
+		return nil, err
+	}
+
+	if prefix == EncryptedTokenPrefixSealed {
+		return parseSealedToken(token)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed encrypted token: expected exactly 3 dot-separated segments, got %d", len(parts))
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid base64 in encrypted password - %v", err)
+	}
+	keySize, err := wrappedKeySize(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid base64 in encrypted data - %v", err)
+	}
+	if !bytes.HasPrefix(ciphertext, []byte(opensslSaltedHeader)) || len(ciphertext) < opensslSaltedHeaderLength+aesBlockSize ||
+		(len(ciphertext)-opensslSaltedHeaderLength)%aesBlockSize != 0 {
+		return nil, fmt.Errorf("malformed encrypted token: encrypted data is not a salted AES-256-CBC ciphertext")
+	}
+
+	return &EncryptedToken{
+		Prefix:      prefix,
+		WrappedKey:  wrappedKey,
+		Ciphertext:  ciphertext,
+		KeySizeBits: keySize,
+	}, nil
+}
+
+// IsEncryptedToken reports whether a value, such as a contract section, is a valid encrypted token.
+//
+// Parameters:
+//   - value: Value to check
+//
+// Returns:
+//   - true if the value parses as an encrypted token of any platform, false otherwise
+func IsEncryptedToken(value string) bool {
+	_, err := ParseEncryptedToken(value, "")
+	return err == nil
+}
+
+// checkEncryptedTokenPrefix validates a token prefix against the expected platform.
+func checkEncryptedTokenPrefix(prefix, confidentialComputingOs string) error {
+	var allowed []string
+	switch confidentialComputingOs {
+	case "":
+		allowed = []string{EncryptedTokenPrefixHyperProtectBasic, EncryptedTokenPrefixContractBasic, EncryptedTokenPrefixSealed}
+	case ConfidentialComputingOsCcrt, ConfidentialComputingOsCcrv:
+		allowed = []string{EncryptedTokenPrefixContractBasic}
+	case HyperProtectOsHpvs:
+		allowed = []string{EncryptedTokenPrefixHyperProtectBasic}
+	case ConfidentialComputingOsCcco:
+		allowed = []string{EncryptedTokenPrefixHyperProtectBasic, EncryptedTokenPrefixSealed}
+	default:
+		return fmt.Errorf("unsupported platform: %s", confidentialComputingOs)
+	}
+
+	for _, allowedPrefix := range allowed {
+		if prefix == allowedPrefix {
+			return nil
+		}
+	}
+
+	if confidentialComputingOs == "" {
+		return fmt.Errorf("malformed encrypted token: unknown prefix %q", prefix)
+	}
+	return fmt.Errorf("malformed encrypted token: prefix %q is not valid for platform %s, expected %s", prefix, confidentialComputingOs, strings.Join(allowed, " or "))
+}
+
+// parseSealedToken parses a "sealed.<header>.<payload>.<signature>" JWS token.
+func parseSealedToken(token string) (*EncryptedToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("malformed encrypted token: expected exactly 4 dot-separated segments for sealed token, got %d", len(parts))
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid base64 in sealed header - %v", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid sealed header - %v", err)
+	}
+	if header.Alg != "RS512" {
+		return nil, fmt.Errorf("malformed encrypted token: unsupported sealed signature algorithm %q", header.Alg)
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid base64 in sealed payload - %v", err)
+	}
+	var envelope sealedEnvelope
+	if err := json.Unmarshal(payloadJSON, &envelope); err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid sealed payload - %v", err)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(envelope.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted token: invalid base64 in sealed encrypted key - %v", err)
+	}
+	keySize, err := wrappedKeySize(wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.EncryptedData)
+	if err != nil || len(ciphertext) <= aesBlockSize {
+		return nil, fmt.Errorf("malformed encrypted token: sealed encrypted data is not an AES-GCM ciphertext")
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(envelope.IV)
+	if err != nil || len(iv) != sealedIvLength {
+		return nil, fmt.Errorf("malformed encrypted token: sealed IV must be %d bytes", sealedIvLength)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil || len(signature) == 0 {
+		return nil, fmt.Errorf("malformed encrypted token: invalid sealed signature")
+	}
+
+	return &EncryptedToken{
+		Prefix:      EncryptedTokenPrefixSealed,
+		WrappedKey:  wrappedKey,
+		Ciphertext:  ciphertext,
+		KeySizeBits: keySize,
+		IV:          iv,
+		KeyID:       envelope.KeyID,
+		Signature:   signature,
+	}, nil
+}
+
+// wrappedKeySize returns the RSA key size in bits implied by an RSA encrypted key.
+func wrappedKeySize(wrappedKey []byte) (int, error) {
+	keySize := len(wrappedKey) * 8
+	if keySize < minWrappedKeyBits || keySize > maxWrappedKeyBits {
+		return 0, fmt.Errorf("malformed encrypted token: wrapped key of %d bytes does not match an RSA key size", len(wrappedKey))
+	}
+	return keySize, nil
+}
+
 // CheckUrlExists verifies if a URL is accessible by sending an HTTP HEAD request.
 // It checks if the response status code is in the 2xx range (success).
+// Use CheckUrlExistsContext to pass a custom client, retries or a context.
 //
 // Parameters:
 //   - url: URL to check
@@ -565,12 +1003,7 @@ func GetEncryptPassWorkload(encryptedData string) (string, string, error) {
 //   - true if URL returns 2xx status code, false otherwise
 //   - Error if HTTP request fails
 func CheckUrlExists(url string) (bool, error) {
-	response, err := http.Head(url)
-	if err != nil {
-		return false, err
-	}
-
-	return response.StatusCode >= 200 && response.StatusCode < 300, nil
+	return CheckUrlExistsContext(context.Background(), url, DownloadConfig{})
 }
 
 // GetDataFromLatestVersion retrieves the latest version data matching semantic version constraints.
@@ -622,25 +1055,67 @@ func GetDataFromLatestVersion(jsonData, version string) (string, map[string]stri
 }
 
 // FetchEncryptionCertificate retrieves the appropriate encryption certificate for a IBM Confidential Computing platform.
-// If a custom certificate is provided, it returns that. Otherwise, it returns the embedded certificate
-// for the specified platform (ccrt, ccrv, or ccco) and certificate version.
+// If a custom certificate is provided, it returns that. Otherwise, it returns the certificate of the
+// default certificate store (the embedded certificates plus any added at runtime) for the specified
+// platform (ccrt, ccrv, ccco or hpvs) and certificate version. Versions only added to the deprecated
+// encryption.CertificateMap and a replaced encryption.LatestEncryptionCertificate variable are still used.
 //
 // Parameters:
-//   - confidentialComputingOs: Confidential Computing platform version ("ccrt", "ccrv", "ccco") - defaults to "ccrt" if empty
-//   - encryptionCertificate: Custom encryption certificate (PEM format) - uses embedded default if empty
-//   - certVersion: Certificate version (e.g., "26.2.0", "25.11.0") - uses latest if empty
+//   - confidentialComputingOs: Confidential Computing platform version ("ccrt", "ccrv", "ccco", "hpvs") - defaults to "hpvs" if empty
+//   - encryptionCertificate: Custom encryption certificate (PEM format) - uses the store if empty
+//   - certVersion: Certificate version (e.g., "26.2.0") or semantic version constraint (e.g., "~26.5", ">=26.4.0 <27") - uses latest if empty
 //
 // Returns:
 //   - Encryption certificate in PEM format
 //   - Error if platform or certificate version is invalid
 func FetchEncryptionCertificate(confidentialComputingOs, encryptionCertificate, certVersion string) (string, error) {
+	certificate, _, err := ResolveEncryptionCertificate(nil, confidentialComputingOs, encryptionCertificate, certVersion, false, time.Time{})
+	return certificate, err
+}
+
+// FetchEncryptionCertificateFromStore retrieves the encryption certificate for a IBM Confidential
+// Computing platform from a certificate store. If a custom certificate is provided, it returns that.
+//
+// Parameters:
+//   - store: Certificate store to resolve the platform and version in
+//   - confidentialComputingOs: Confidential Computing platform version ("ccrt", "ccrv", "ccco", "hpvs") - defaults to "hpvs" if empty
+//   - encryptionCertificate: Custom encryption certificate (PEM format) - uses the store if empty
+//   - certVersion: Certificate version (e.g., "26.2.0") or semantic version constraint (e.g., "~26.5", ">=26.4.0 <27") - uses latest if empty
+//
+// Returns:
+//   - Encryption certificate in PEM format
+//   - Error if platform or certificate version is invalid
+func FetchEncryptionCertificateFromStore(store cert.CertStore, confidentialComputingOs, encryptionCertificate, certVersion string) (string, error) {
+	certificate, _, err := ResolveEncryptionCertificate(store, confidentialComputingOs, encryptionCertificate, certVersion, false, time.Time{})
+	return certificate, err
+}
+
+// ResolveEncryptionCertificate resolves the encryption certificate for a IBM Confidential Computing
+// platform and reports the version it resolved to. certVersion is either an exact version of the
+// store or a semantic version constraint, in which case the highest matching version is used.
+// If a custom certificate is provided, it is returned with an empty version.
+//
+// Parameters:
+//   - store: Certificate store to resolve the platform and version in - uses the default store if nil,
+//     falling back to the deprecated encryption.CertificateMap and LatestEncryptionCertificate variables
+//   - confidentialComputingOs: Confidential Computing platform version ("ccrt", "ccrv", "ccco", "hpvs") - defaults to "hpvs" if empty
+//   - encryptionCertificate: Custom encryption certificate (PEM format) - uses the store if empty
+//   - certVersion: Certificate version (e.g., "26.2.0") or semantic version constraint (e.g., "~26.5", ">=26.4.0 <27") - uses latest if empty
+//   - skipExpired: Skip expired certificates of the store when resolving the version
+//   - at: Time certificates must not be expired at when skipExpired is set; the current time if zero
+//
+// Returns:
+//   - Encryption certificate in PEM format
+//   - Resolved certificate version, empty for a custom certificate
+//   - Error if platform or certificate version is invalid or no matching certificate is found
+func ResolveEncryptionCertificate(store cert.CertStore, confidentialComputingOs, encryptionCertificate, certVersion string, skipExpired bool, at time.Time) (string, string, error) {
 	if confidentialComputingOs == "" {
 		confidentialComputingOs = HyperProtectOsHpvs
 	}
 
 	// If custom certificate is provided, use it
 	if encryptionCertificate != "" {
-		return encryptionCertificate, nil
+		return encryptionCertificate, "", nil
 	}
 
 	// Validate platform type
@@ -648,33 +1123,137 @@ func FetchEncryptionCertificate(confidentialComputingOs, encryptionCertificate,
 		confidentialComputingOs != ConfidentialComputingOsCcrv &&
 		confidentialComputingOs != ConfidentialComputingOsCcco &&
 		confidentialComputingOs != HyperProtectOsHpvs {
-		return "", fmt.Errorf("invalid Confidential Computing platform: %s (must be ccrt, ccrv, ccco, or hpvs)", confidentialComputingOs)
-	}
-
-	var selectedCert string
-	// If no version specified, return latest certificate
-	if certVersion == "" {
-		if confidentialComputingOs == ConfidentialComputingOsCcrt {
-			selectedCert = cert.LatestEncryptionCertificateCcrt
-		} else if confidentialComputingOs == ConfidentialComputingOsCcrv {
-			selectedCert = cert.LatestEncryptionCertificateCcrv
-		} else if confidentialComputingOs == ConfidentialComputingOsCcco {
-			selectedCert = cert.LatestEncryptionCertificateCcco
-		} else {
-			selectedCert = cert.LatestEncryptionCertificateHpvs
+		return "", "", fmt.Errorf("invalid Confidential Computing platform: %s (must be ccrt, ccrv, ccco, or hpvs)", confidentialComputingOs)
+	}
+
+	if store == nil {
+		if latest, ok := replacedLatestCertificate(confidentialComputingOs); ok && certVersion == "" && !(skipExpired && isCertificateExpired(latest, at)) {
+			return latest, "", nil
+		}
+		store = legacyCertStore{store: cert.DefaultCertStore()}
+	}
+
+	versions := store.Versions(confidentialComputingOs)
+	if len(versions) == 0 {
+		return "", "", fmt.Errorf("no certificates found for platform %s", confidentialComputingOs)
+	}
+
+	// Exact versions are looked up directly so that versions which are not valid semver still resolve
+	if certVersion != "" {
+		if certificate, exists := store.Certificate(confidentialComputingOs, certVersion); exists && !(skipExpired && isCertificateExpired(certificate, at)) {
+			return certificate, certVersion, nil
+		}
+	}
+
+	// An empty version matches every version
+	var targetConstraint *semver.Constraints
+	if certVersion != "" {
+		var err error
+		targetConstraint, err = semver.NewConstraint(certVersion)
+		if err != nil {
+			return "", "", fmt.Errorf("certificate version %s not found for platform %s", certVersion, confidentialComputingOs)
+		}
+	}
+
+	expired := false
+	// Versions are sorted latest first, so the first match is the highest matching version
+	for _, versionStr := range versions {
+		if targetConstraint != nil {
+			version, err := semver.NewVersion(versionStr)
+			if err != nil || !targetConstraint.Check(version) {
+				continue
+			}
+		}
+
+		certificate, exists := store.Certificate(confidentialComputingOs, versionStr)
+		if !exists {
+			continue
+		}
+		if skipExpired && isCertificateExpired(certificate, at) {
+			expired = true
+			continue
+		}
+		return certificate, versionStr, nil
+	}
+
+	if expired {
+		if certVersion == "" {
+			return "", "", fmt.Errorf("all certificates for platform %s have expired", confidentialComputingOs)
 		}
-		return selectedCert, nil
+		return "", "", fmt.Errorf("all certificates matching version %s for platform %s have expired", certVersion, confidentialComputingOs)
+	}
+
+	return "", "", fmt.Errorf("certificate version %s not found for platform %s", certVersion, confidentialComputingOs)
+}
+
+// embeddedLatestCertificates holds the initial values of the deprecated LatestEncryptionCertificate
+// variables, to detect callers that replaced them.
+var embeddedLatestCertificates = map[string]string{
+	ConfidentialComputingOsCcrt: cert.LatestEncryptionCertificateCcrt,
+	ConfidentialComputingOsCcrv: cert.LatestEncryptionCertificateCcrv,
+	ConfidentialComputingOsCcco: cert.LatestEncryptionCertificateCcco,
+	HyperProtectOsHpvs:          cert.LatestEncryptionCertificateHpvs,
+}
+
+// replacedLatestCertificate returns the deprecated LatestEncryptionCertificate variable of a platform
+// if a caller replaced it.
+func replacedLatestCertificate(confidentialComputingOs string) (string, bool) {
+	var latest string
+	switch confidentialComputingOs {
+	case ConfidentialComputingOsCcrt:
+		latest = cert.LatestEncryptionCertificateCcrt
+	case ConfidentialComputingOsCcrv:
+		latest = cert.LatestEncryptionCertificateCcrv
+	case ConfidentialComputingOsCcco:
+		latest = cert.LatestEncryptionCertificateCcco
+	default:
+		latest = cert.LatestEncryptionCertificateHpvs
 	}
+	return latest, latest != "" && latest != embeddedLatestCertificates[confidentialComputingOs]
+}
 
-	// Fetch specific version from CertificateMap
-	if platformCerts, exists := cert.CertificateMap[confidentialComputingOs]; exists {
-		if certificate, versionExists := platformCerts[certVersion]; versionExists {
-			return certificate, nil
+// legacyCertStore resolves certificates in a store and falls back to the deprecated
+// encryption.CertificateMap for versions the store does not hold, so callers that still add
+// certificates to the map keep working.
+type legacyCertStore struct {
+	store cert.CertStore
+}
+
+// Certificate returns the certificate of the store, or of CertificateMap if the store does not hold the version.
+func (s legacyCertStore) Certificate(osType, version string) (string, bool) {
+	if version == "" {
+		versions := s.Versions(osType)
+		if len(versions) == 0 {
+			return "", false
 		}
-		return "", fmt.Errorf("certificate version %s not found for platform %s", certVersion, confidentialComputingOs)
+		version = versions[0]
+	}
+	if certificate, ok := s.store.Certificate(osType, version); ok {
+		return certificate, true
 	}
+	certificate, ok := cert.CertificateMap[osType][version]
+	return certificate, ok
+}
 
-	return "", fmt.Errorf("no certificates found for platform %s", confidentialComputingOs)
+// Versions returns the versions of the store and of CertificateMap, latest first.
+func (s legacyCertStore) Versions(osType string) []string {
+	versions := s.store.Versions(osType)
+	for version := range cert.CertificateMap[osType] {
+		if !slices.Contains(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return cert.CompareVersions(versions[i], versions[j]) > 0
+	})
+	return versions
+}
+
+// isCertificateExpired reports whether an encryption certificate has expired at the given time.
+// Certificates that cannot be parsed are not considered expired; they fail later when they are used.
+func isCertificateExpired(encryptionCert string, at time.Time) bool {
+	status, _, _, err := CheckEncryptionCertValidityAt(encryptionCert, at)
+	return err == nil && status == "expired"
 }
 
 // GenerateTgzBase64 creates a compressed tar.gz archive from files and folders.
@@ -739,6 +1318,121 @@ func GenerateTgzBase64(folderFilesPath []string) (string, error) {
 	return EncodeToBase64(buf.Bytes()), nil
 }
 
+// ExtractTgzBase64 decodes a Base64-encoded tar.gz archive and returns its regular files.
+// It is the inverse of [GenerateTgzBase64]; directories and other non-regular entries are skipped.
+//
+// Parameters:
+//   - tgzBase64: Base64-encoded tar.gz archive
+//
+// Returns:
+//   - Map of archive file path to file content
+//   - Error if Base64 decoding, decompression or archive reading fails
+func ExtractTgzBase64(tgzBase64 string) (map[string]string, error) {
+	archive, err := base64.StdEncoding.DecodeString(strings.TrimSpace(tgzBase64))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 archive - %v", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip archive - %v", err)
+	}
+	defer gr.Close()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive - %v", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive file %s - %v", header.Name, err)
+		}
+		files[filepath.ToSlash(header.Name)] = string(content)
+	}
+
+	return files, nil
+}
+
+// RewriteTgzBase64 decodes a Base64-encoded tar.gz archive, passes the content of every regular file
+// through rewrite and returns the re-encoded archive. Entry headers other than the size are kept.
+//
+// Parameters:
+//   - tgzBase64: Base64-encoded tar.gz archive
+//   - rewrite: Function returning the new content of a file from its path and content
+//
+// Returns:
+//   - Base64-encoded tar.gz archive with the rewritten files
+//   - Error if the archive cannot be read or written, or rewrite fails
+func RewriteTgzBase64(tgzBase64 string, rewrite func(name, content string) (string, error)) (string, error) {
+	archive, err := base64.StdEncoding.DecodeString(strings.TrimSpace(tgzBase64))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64 archive - %v", err)
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", fmt.Errorf("failed to read gzip archive - %v", err)
+	}
+	defer gr.Close()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read tar archive - %v", err)
+		}
+
+		var content []byte
+		if header.Typeflag == tar.TypeReg {
+			original, err := io.ReadAll(tr)
+			if err != nil {
+				return "", fmt.Errorf("failed to read archive file %s - %v", header.Name, err)
+			}
+
+			rewritten, err := rewrite(filepath.ToSlash(header.Name), string(original))
+			if err != nil {
+				return "", fmt.Errorf("failed to rewrite archive file %s - %v", header.Name, err)
+			}
+			content = []byte(rewritten)
+			header.Size = int64(len(content))
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return "", fmt.Errorf("failed to write tar archive - %v", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return "", fmt.Errorf("failed to write archive file %s - %v", header.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write tar archive - %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write gzip archive - %v", err)
+	}
+
+	return EncodeToBase64(buf.Bytes()), nil
+}
+
 // VerifyContractWithSchema validates a contract against the schema for a specific Confidential Computing platform.
 // It parses the contract YAML, retrieves the appropriate schema for the platform version,
 // and validates the contract structure against that schema.
@@ -1156,6 +1850,22 @@ func yamlParse(data string) (map[string]any, error) {
 //   - Expiry date in "DD-MM-YY HH:MM:SS GMT" format
 //   - Error if PEM parsing or certificate parsing fails
 func CheckEncryptionCertValidity(encryptionCert string) (string, int, string, error) {
+	return CheckEncryptionCertValidityAt(encryptionCert, time.Now())
+}
+
+// CheckEncryptionCertValidityAt checks the validity status of an encryption certificate like
+// [CheckEncryptionCertValidity], at the given time instead of the current time.
+//
+// Parameters:
+//   - encryptionCert: Encryption certificate in PEM format
+//   - at: Time the certificate is checked at, e.g. a planned deployment date; the current time if zero
+//
+// Returns:
+//   - Certificate status ("valid" or "expired")
+//   - Days until expiration at the given time (negative if expired)
+//   - Expiry date in "DD-MM-YY HH:MM:SS GMT" format
+//   - Error if PEM parsing or certificate parsing fails
+func CheckEncryptionCertValidityAt(encryptionCert string, at time.Time) (string, int, string, error) {
 	block, _ := pem.Decode([]byte(encryptionCert))
 	if block == nil {
 		return "", 0, "", fmt.Errorf("failed to parse PEM block")
@@ -1166,7 +1876,7 @@ func CheckEncryptionCertValidity(encryptionCert string) (string, int, string, er
 		return "", 0, "", fmt.Errorf("failed to parse certificate %v", err)
 	}
 
-	now := time.Now()
+	now := timeOrNow(at)
 	daysLeft := cert.NotAfter.Sub(now).Hours() / 24
 	gmtTime := cert.NotAfter.UTC()
 	formattedExpiryDays := gmtTime.Format("02-01-06 15:04:05") + " GMT"
@@ -1193,6 +1903,21 @@ func CheckEncryptionCertValidity(encryptionCert string) (string, int, string, er
 //   - Status message indicating validity, warning (< 180 days), or empty string if error
 //   - Error if certificate has expired or PEM/certificate parsing fails
 func CheckEncryptionCertValidityForContractEncryption(encryptionCert string) (string, error) {
+	return CheckEncryptionCertValidityForContractEncryptionAt(encryptionCert, time.Now())
+}
+
+// CheckEncryptionCertValidityForContractEncryptionAt validates an encryption certificate for contract
+// encryption like [CheckEncryptionCertValidityForContractEncryption], at the given time instead of the
+// current time.
+//
+// Parameters:
+//   - encryptionCert: Encryption certificate in PEM format
+//   - at: Time the certificate must be valid at, e.g. a planned deployment date; the current time if zero
+//
+// Returns:
+//   - Status message indicating validity, warning (< 180 days), or empty string if error
+//   - Error if certificate has expired at the given time or PEM/certificate parsing fails
+func CheckEncryptionCertValidityForContractEncryptionAt(encryptionCert string, at time.Time) (string, error) {
 	block, _ := pem.Decode([]byte(encryptionCert))
 	if block == nil {
 		return "", fmt.Errorf("failed to parse PEM block")
@@ -1203,7 +1928,7 @@ func CheckEncryptionCertValidityForContractEncryption(encryptionCert string) (st
 		return "", fmt.Errorf("failed to parse certificate %v", err)
 	}
 
-	now := time.Now()
+	now := timeOrNow(at)
 	daysLeft := cert.NotAfter.Sub(now).Hours() / 24
 
 	switch {
@@ -1221,6 +1946,14 @@ func CheckEncryptionCertValidityForContractEncryption(encryptionCert string) (st
 	}
 }
 
+// timeOrNow returns at, or the current time if at is zero.
+func timeOrNow(at time.Time) time.Time {
+	if at.IsZero() {
+		return time.Now()
+	}
+	return at
+}
+
 // GzipInitData creates gzipped string from initdata.toml string
 // It creates zipped bytes from the input string
 //
@@ -18,6 +18,7 @@ package general
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -25,8 +26,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
 	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
@@ -65,7 +68,8 @@ const (
 		}
 	}`
 
-	sampleComposeFolder = "../../samples/tgz"
+	sampleComposeFolder      = "../../samples/tgz"
+	sampleEncryptedTokenPath = "../../samples/decrypt/encrypt.txt"
 	// active.crt will be valid up to November 9, 2030
 	sampleEncryptionCertificate        = "../../samples/encryption-cert/active.crt"
 	sampleEncryptionCertificateExpired = "../../samples/encryption-cert/expired.crt"
@@ -250,6 +254,118 @@ func TestKeyValueInjector(t *testing.T) {
 	assert.Contains(t, finalContract, fmt.Sprintf("%s: %s", key, value))
 }
 
+// Testcase to check if KeyValueInjector() preserves comments, key order and scalar styles
+func TestKeyValueInjectorPreservesDocument(t *testing.T) {
+	env := `# reviewed env
+type: env
+logging:
+  # syslog endpoint
+  syslog:
+    hostname: "logs.example.com"
+    port: 6514
+signingKey: old-key # replaced on every build
+volumes:
+  data:
+    seed: 'seed-value'
+`
+
+	result, err := KeyValueInjector(env, "signingKey", "new-key")
+	if err != nil {
+		t.Errorf("failed to inject signingKey - %v", err)
+	}
+
+	expected := strings.Replace(env, "signingKey: old-key", "signingKey: new-key", 1)
+	assert.Equal(t, expected, result)
+
+	result, err = KeyValueInjector(env, "envWorkloadSignature", "testing123")
+	if err != nil {
+		t.Errorf("failed to inject envWorkloadSignature - %v", err)
+	}
+
+	assert.Equal(t, env+"envWorkloadSignature: testing123\n", result)
+}
+
+// Testcase to check if SetPath() creates and updates nested map and list values
+func TestSetPath(t *testing.T) {
+	document := `type: env
+volumes:
+  # first volume
+  data:
+    seed: seed-value
+auths:
+  - user: a
+`
+
+	result, err := SetPath(document, []string{"volumes", "data", "previousSeed"}, "old-seed")
+	assert.NoError(t, err)
+	assert.Contains(t, result, "  # first volume\n  data:\n    seed: seed-value\n    previousSeed: old-seed\n")
+
+	result, err = SetPath(result, []string{"logging", "syslog", "port"}, 6514)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(result, "logging:\n  syslog:\n    port: 6514\n"))
+
+	result, err = SetPath(result, []string{"auths", "[0]", "user"}, "b")
+	assert.NoError(t, err)
+	assert.Contains(t, result, "  - user: b\n")
+
+	result, err = SetPath(result, []string{"auths", "1"}, map[string]string{"user": "c"})
+	assert.NoError(t, err)
+	assert.Contains(t, result, "  - user: b\n  - user: c\n")
+
+	result, err = SetPath("", []string{"type"}, "env")
+	assert.NoError(t, err)
+	assert.Equal(t, "type: env\n", result)
+}
+
+// Testcase to check if SetPath() handles invalid paths
+func TestSetPathInvalid(t *testing.T) {
+	document := "type: env\nauths:\n  - user: a\n"
+
+	_, err := SetPath(document, nil, "value")
+	assert.EqualError(t, err, "path is empty")
+
+	_, err = SetPath(document, []string{"type", "nested"}, "value")
+	assert.EqualError(t, err, `cannot set "nested" - parent value is not a map or list`)
+
+	_, err = SetPath(document, []string{"auths", "5"}, "value")
+	assert.EqualError(t, err, "list index 5 out of range")
+
+	_, err = SetPath(document, []string{"auths", "first"}, "value")
+	assert.EqualError(t, err, `invalid list index "first"`)
+
+	_, err = SetPath("not: valid: yaml: structure", []string{"key"}, "value")
+	assert.ErrorContains(t, err, "failed to parse contract")
+}
+
+// Testcase to check if DeletePath() removes nested values and ignores missing paths
+func TestDeletePath(t *testing.T) {
+	document := `# reviewed env
+type: env
+volumes:
+  data:
+    seed: seed-value
+    previousSeed: old-seed
+auths:
+  - user: a
+  - user: b
+`
+
+	result, err := DeletePath(document, []string{"volumes", "data", "previousSeed"})
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Replace(document, "    previousSeed: old-seed\n", "", 1), result)
+
+	result, err = DeletePath(result, []string{"auths", "0"})
+	assert.NoError(t, err)
+	assert.Contains(t, result, "auths:\n  - user: b\n")
+
+	unchanged, err := DeletePath(document, []string{"logging", "syslog"})
+	assert.NoError(t, err)
+	assert.Equal(t, document, unchanged)
+
+	_, err = DeletePath(document, []string{})
+	assert.EqualError(t, err, "path is empty")
+}
+
 // Testcase to check if CertificateDownloader() can download encryption certificate
 func TestCertificateDownloader(t *testing.T) {
 	certificate, err := CertificateDownloader(certificateDownloadUrl)
@@ -409,6 +525,83 @@ func TestGenerateTgzBase64(t *testing.T) {
 	assert.NotEmpty(t, result)
 }
 
+// Testcase to check if ExtractTgzBase64() is able to read back the files of a generated archive
+func TestExtractTgzBase64(t *testing.T) {
+	filesFoldersList, err := ListFoldersAndFiles(sampleComposeFolder)
+	if err != nil {
+		t.Errorf("failed to list files and folders - %v", err)
+	}
+
+	archive, err := GenerateTgzBase64(filesFoldersList)
+	if err != nil {
+		t.Errorf("failed to generate TGZ base64 - %v", err)
+	}
+
+	files, err := ExtractTgzBase64(archive)
+	if err != nil {
+		t.Errorf("failed to extract TGZ base64 - %v", err)
+	}
+
+	expected, err := ReadDataFromFile(sampleComposeFolder + "/docker-compose.yaml")
+	if err != nil {
+		t.Errorf("failed to read compose file - %v", err)
+	}
+
+	assert.Equal(t, expected, files["docker-compose.yaml"])
+}
+
+// Testcase to check if ExtractTgzBase64() handles invalid archive data
+func TestExtractTgzBase64Invalid(t *testing.T) {
+	_, err := ExtractTgzBase64("not-base64!")
+	assert.ErrorContains(t, err, "failed to decode base64 archive")
+
+	_, err = ExtractTgzBase64(EncodeToBase64([]byte("plain text")))
+	assert.ErrorContains(t, err, "failed to read gzip archive")
+}
+
+// Testcase to check if RewriteTgzBase64() rewrites archive files and keeps the other entries
+func TestRewriteTgzBase64(t *testing.T) {
+	filesFoldersList, err := ListFoldersAndFiles(sampleComposeFolder)
+	if err != nil {
+		t.Errorf("failed to list files and folders - %v", err)
+	}
+
+	archive, err := GenerateTgzBase64(filesFoldersList)
+	if err != nil {
+		t.Errorf("failed to generate TGZ base64 - %v", err)
+	}
+
+	rewritten, err := RewriteTgzBase64(archive, func(name, content string) (string, error) {
+		if name == "docker-compose.yaml" {
+			return "services: {}\n", nil
+		}
+		return content, nil
+	})
+	if err != nil {
+		t.Errorf("failed to rewrite TGZ base64 - %v", err)
+	}
+
+	original, err := ExtractTgzBase64(archive)
+	if err != nil {
+		t.Errorf("failed to extract TGZ base64 - %v", err)
+	}
+	files, err := ExtractTgzBase64(rewritten)
+	if err != nil {
+		t.Errorf("failed to extract TGZ base64 - %v", err)
+	}
+
+	assert.Len(t, files, len(original))
+	assert.Equal(t, "services: {}\n", files["docker-compose.yaml"])
+
+	_, err = RewriteTgzBase64(archive, func(name, content string) (string, error) {
+		return "", fmt.Errorf("rewrite failed")
+	})
+	assert.ErrorContains(t, err, "failed to rewrite archive file docker-compose.yaml - rewrite failed")
+
+	_, err = RewriteTgzBase64("not-base64!", nil)
+	assert.ErrorContains(t, err, "failed to decode base64 archive")
+}
+
 // Testcase to check if VerifyContractWithSchema() is able to verify schema of contract
 func TestVerifyContractWithSchema(t *testing.T) {
 	contract, err := ReadDataFromFile(simpleContractPath)
@@ -661,6 +854,144 @@ func TestKeyValueInjectorInvalidYaml(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// Testcase to check if FetchEncryptionCertificateFromStore() resolves versions added to a store at runtime
+func TestFetchEncryptionCertificateFromStore(t *testing.T) {
+	store := cert.NewCertStore()
+
+	_, err := FetchEncryptionCertificateFromStore(store, ConfidentialComputingOsCcrt, "", "")
+	assert.EqualError(t, err, "no certificates found for platform ccrt")
+
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "99.1.0", cert.LatestEncryptionCertificateCcrt))
+
+	result, err := FetchEncryptionCertificateFromStore(store, ConfidentialComputingOsCcrt, "", "99.1.0")
+	assert.NoError(t, err)
+	assert.Equal(t, cert.LatestEncryptionCertificateCcrt, result)
+
+	result, err = FetchEncryptionCertificateFromStore(store, ConfidentialComputingOsCcrt, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, cert.LatestEncryptionCertificateCcrt, result)
+
+	_, err = FetchEncryptionCertificateFromStore(store, ConfidentialComputingOsCcrt, "", "26.2.0")
+	assert.EqualError(t, err, "certificate version 26.2.0 not found for platform ccrt")
+}
+
+// Testcase to check if FetchEncryptionCertificate() still uses certificates added to the deprecated CertificateMap and Latest variables
+func TestFetchEncryptionCertificateLegacyMap(t *testing.T) {
+	embedded := cert.LatestEncryptionCertificateHpvs
+	older := cert.CertificateMap[HyperProtectOsHpvs]["1.0.27"]
+	require.NotEmpty(t, older)
+
+	cert.CertificateMap[HyperProtectOsHpvs]["1.0.99"] = older
+	t.Cleanup(func() { delete(cert.CertificateMap[HyperProtectOsHpvs], "1.0.99") })
+
+	result, err := FetchEncryptionCertificate(HyperProtectOsHpvs, "", "1.0.99")
+	assert.NoError(t, err)
+	assert.Equal(t, older, result)
+
+	result, err = FetchEncryptionCertificate(HyperProtectOsHpvs, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, older, result)
+
+	cert.LatestEncryptionCertificateHpvs = "replaced"
+	t.Cleanup(func() { cert.LatestEncryptionCertificateHpvs = embedded })
+
+	result, err = FetchEncryptionCertificate(HyperProtectOsHpvs, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "replaced", result)
+
+	// An explicit store does not fall back to the deprecated variables
+	_, err = FetchEncryptionCertificateFromStore(cert.NewEmbeddedCertStore(), HyperProtectOsHpvs, "", "1.0.99")
+	assert.EqualError(t, err, "certificate version 1.0.99 not found for platform hpvs")
+}
+
+// Testcase to check if ResolveEncryptionCertificate() resolves semantic version constraints and skips expired certificates
+func TestResolveEncryptionCertificate(t *testing.T) {
+	activeCert, err := ReadDataFromFile(sampleEncryptionCertificate)
+	require.NoError(t, err)
+	expiredCert, err := ReadDataFromFile(sampleEncryptionCertificateExpired)
+	require.NoError(t, err)
+
+	store := cert.NewCertStore()
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "26.4.0", activeCert))
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "26.5.0", activeCert))
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "26.5.1", expiredCert))
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "27.0.0", activeCert))
+
+	certificate, version, err := ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "~26.5", false, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "26.5.1", version)
+	assert.Equal(t, expiredCert, certificate)
+
+	certificate, version, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "~26.5", true, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "26.5.0", version)
+	assert.Equal(t, activeCert, certificate)
+
+	_, version, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", ">=26.4.0 <27", false, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "26.5.1", version)
+
+	_, version, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "", false, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "27.0.0", version)
+
+	_, version, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "26.4.0", true, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "26.4.0", version)
+
+	_, _, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "26.5.1", true, time.Time{})
+	assert.EqualError(t, err, "all certificates matching version 26.5.1 for platform ccrt have expired")
+
+	_, _, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "^28", false, time.Time{})
+	assert.EqualError(t, err, "certificate version ^28 not found for platform ccrt")
+
+	_, _, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "not-a-version", false, time.Time{})
+	assert.EqualError(t, err, "certificate version not-a-version not found for platform ccrt")
+
+	certificate, version, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, expiredCert, "~26.5", true, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, expiredCert, certificate)
+	assert.Empty(t, version)
+}
+
+// Testcase to check if the certificate validity checks and version resolution use the given time
+func TestCheckEncryptionCertValidityAt(t *testing.T) {
+	activeCert, err := ReadDataFromFile(sampleEncryptionCertificate)
+	require.NoError(t, err)
+	expiredCert, err := ReadDataFromFile(sampleEncryptionCertificateExpired)
+	require.NoError(t, err)
+
+	before := time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2031, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	status, daysLeft, _, err := CheckEncryptionCertValidityAt(expiredCert, before)
+	require.NoError(t, err)
+	assert.Equal(t, "valid", status)
+	assert.Equal(t, 214, daysLeft)
+
+	status, _, _, err = CheckEncryptionCertValidityAt(activeCert, after)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", status)
+
+	_, err = CheckEncryptionCertValidityForContractEncryptionAt(activeCert, after)
+	assert.ErrorContains(t, err, "Encryption certificate has already expired")
+
+	message, err := CheckEncryptionCertValidityForContractEncryptionAt(expiredCert, before)
+	require.NoError(t, err)
+	assert.Contains(t, message, "valid for another 214 days")
+
+	store := cert.NewCertStore()
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "26.5.0", activeCert))
+	require.NoError(t, store.Add(ConfidentialComputingOsCcrt, "26.5.1", expiredCert))
+
+	_, version, err := ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "~26.5", true, before)
+	require.NoError(t, err)
+	assert.Equal(t, "26.5.1", version)
+
+	_, _, err = ResolveEncryptionCertificate(store, ConfidentialComputingOsCcrt, "", "", true, after)
+	assert.EqualError(t, err, "all certificates for platform ccrt have expired")
+}
+
 // Testcase to check if FetchEncryptionCertificate() handles invalid confidentialComputingOs
 func TestFetchEncryptionCertificateInvalidOs(t *testing.T) {
 	_, err := FetchEncryptionCertificate("invalid-os", "", "")
@@ -854,3 +1185,118 @@ func TestExecCommandWithPasswordStderrInError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error detail")
 }
+
+// buildSealedTokenForTest assembles a sealed token with the given wrapped key length.
+func buildSealedTokenForTest(wrappedKeyLength int) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS512","kid":"env_verify"}`))
+	envelope, _ := json.Marshal(map[string]string{
+		"version":        "0.1.0",
+		"type":           "kms",
+		"key_id":         "env_decrypt",
+		"encrypted_key":  EncodeToBase64(make([]byte, wrappedKeyLength)),
+		"encrypted_data": EncodeToBase64(make([]byte, 32)),
+		"wrap_type":      "A256GCM",
+		"iv":             EncodeToBase64(make([]byte, 12)),
+		"provider":       "ibm-hpcr-contract",
+	})
+	payload := base64.RawURLEncoding.EncodeToString(envelope)
+	signature := base64.RawURLEncoding.EncodeToString(make([]byte, 512))
+	return "sealed." + header + "." + payload + "." + signature
+}
+
+// Testcase to check if ParseEncryptedToken() parses a hyper-protect-basic token and reports the key size
+func TestParseEncryptedToken(t *testing.T) {
+	token, err := ReadDataFromFile(sampleEncryptedTokenPath)
+	if err != nil {
+		t.Errorf("failed to read encrypted token - %v", err)
+	}
+
+	parsed, err := ParseEncryptedToken(token, HyperProtectOsHpvs)
+	if err != nil {
+		t.Errorf("failed to parse encrypted token - %v", err)
+	}
+
+	assert.Equal(t, EncryptedTokenPrefixHyperProtectBasic, parsed.Prefix)
+	assert.Equal(t, 2048, parsed.KeySizeBits)
+	assert.Len(t, parsed.WrappedKey, 256)
+	assert.True(t, bytes.HasPrefix(parsed.Ciphertext, []byte("Salted__")))
+
+	_, err = ParseEncryptedToken(token, ConfidentialComputingOsCcco)
+	assert.NoError(t, err)
+}
+
+// Testcase to check if ParseEncryptedToken() validates the prefix against the platform
+func TestParseEncryptedTokenPlatform(t *testing.T) {
+	token, err := ReadDataFromFile(sampleEncryptedTokenPath)
+	if err != nil {
+		t.Errorf("failed to read encrypted token - %v", err)
+	}
+	ccrtToken := strings.Replace(strings.TrimSpace(token), EncryptedTokenPrefixHyperProtectBasic, EncryptedTokenPrefixContractBasic, 1)
+
+	_, err = ParseEncryptedToken(token, ConfidentialComputingOsCcrt)
+	assert.EqualError(t, err, `malformed encrypted token: prefix "hyper-protect-basic" is not valid for platform ccrt, expected contract-basic`)
+
+	parsed, err := ParseEncryptedToken(ccrtToken, ConfidentialComputingOsCcrv)
+	assert.NoError(t, err)
+	assert.Equal(t, EncryptedTokenPrefixContractBasic, parsed.Prefix)
+
+	_, err = ParseEncryptedToken(ccrtToken, HyperProtectOsHpvs)
+	assert.Error(t, err)
+
+	_, err = ParseEncryptedToken(ccrtToken, "invalid")
+	assert.EqualError(t, err, "unsupported platform: invalid")
+}
+
+// Testcase to check if ParseEncryptedToken() parses sealed tokens
+func TestParseEncryptedTokenSealed(t *testing.T) {
+	parsed, err := ParseEncryptedToken(buildSealedTokenForTest(512), ConfidentialComputingOsCcco)
+	if err != nil {
+		t.Errorf("failed to parse sealed token - %v", err)
+	}
+
+	assert.Equal(t, EncryptedTokenPrefixSealed, parsed.Prefix)
+	assert.Equal(t, 4096, parsed.KeySizeBits)
+	assert.Equal(t, "env_decrypt", parsed.KeyID)
+	assert.Len(t, parsed.IV, 12)
+
+	_, err = ParseEncryptedToken(buildSealedTokenForTest(512), ConfidentialComputingOsCcrt)
+	assert.Error(t, err)
+
+	_, err = ParseEncryptedToken(buildSealedTokenForTest(10), "")
+	assert.EqualError(t, err, "malformed encrypted token: wrapped key of 10 bytes does not match an RSA key size")
+}
+
+// Testcase to check if ParseEncryptedToken() rejects malformed tokens
+func TestParseEncryptedTokenMalformed(t *testing.T) {
+	wrappedKey := EncodeToBase64(make([]byte, 256))
+	ciphertext := EncodeToBase64(append([]byte("Salted__12345678"), make([]byte, 16)...))
+
+	cases := map[string]string{
+		"":                                      "malformed encrypted token: token is empty",
+		"plain-text":                            `malformed encrypted token: unknown prefix "plain-text"`,
+		"hyper-protect-basic.abc":               "malformed encrypted token: expected exactly 3 dot-separated segments, got 2",
+		"hyper-protect-basic.!!!." + ciphertext: "malformed encrypted token: invalid base64 in encrypted password - illegal base64 data at input byte 0",
+		"hyper-protect-basic." + EncodeToBase64([]byte("short")) + "." + ciphertext:            "malformed encrypted token: wrapped key of 5 bytes does not match an RSA key size",
+		"hyper-protect-basic." + wrappedKey + "." + EncodeToBase64([]byte("not salted data!")): "malformed encrypted token: encrypted data is not a salted AES-256-CBC ciphertext",
+	}
+	for token, expected := range cases {
+		_, err := ParseEncryptedToken(token, "")
+		assert.EqualError(t, err, expected, "token=%s", token)
+	}
+
+	_, err := ParseEncryptedToken("hyper-protect-basic."+wrappedKey+"."+ciphertext, "")
+	assert.NoError(t, err)
+}
+
+// Testcase to check if IsEncryptedToken() distinguishes encrypted and plaintext sections
+func TestIsEncryptedToken(t *testing.T) {
+	token, err := ReadDataFromFile(sampleEncryptedTokenPath)
+	if err != nil {
+		t.Errorf("failed to read encrypted token - %v", err)
+	}
+
+	assert.True(t, IsEncryptedToken(token))
+	assert.True(t, IsEncryptedToken(buildSealedTokenForTest(256)))
+	assert.False(t, IsEncryptedToken("type: env\nlogging: {}\n"))
+	assert.False(t, IsEncryptedToken("hyper-protect-basic.aaa.bbb"))
+}
@@ -17,31 +17,51 @@ package contract
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/ibm-hyper-protect/contract-go/v2/certificate"
 	dec "github.com/ibm-hyper-protect/contract-go/v2/common/decrypt"
 	enc "github.com/ibm-hyper-protect/contract-go/v2/common/encrypt"
 	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
 )
 
 const (
 	emptyParameterErrStatement = "required parameter is empty"
+	// Time allowed for downloading the intermediates and CRLs of a strict trust verification.
+	strictTrustTimeout = 2 * time.Minute
 
 	// Contract template locations relative to this package.
 	contractTemplateDirPath             = "template"
 	workloadCcrtTemplateFilePath        = "workload_ccrt.yaml"
+	workloadCcrtComposeTemplateFilePath = "workload_ccrt_compose.yaml"
+	workloadCcrtPlayTemplateFilePath    = "workload_ccrt_play.yaml"
 	workloadCcrvTemplateFilePath        = "workload_ccrv.yaml"
 	workloadCccoPeerpodTemplateFilePath = "workload_ccco_peerpod.yaml"
 	workloadCccoBmtlTemplateFilePath    = "workload_ccco_bmtl.yaml"
 	envTemplateFilePath                 = "env.yaml"
+	envKmsTemplateFilePath              = "env_kms.yaml"
 	envCccoPeerpodTemplateFilePath      = "env_ccco_peerpod.yaml"
 	envCccoBmtlTemplateFilePath         = "env_ccco_bmtl.yaml"
+	// Placeholder appended to combined templates that carry an attestation public key.
+	attestationPublicKeyTemplate = "attestationPublicKey: <RSA public key (PEM) used to encrypt the attestation records>\n"
+	// Default template used when no template name is given.
+	defaultTemplateName = "ccrt"
+	formatJson          = "json"
+	formatYaml          = "yaml"
 	// Section validation constants for HpcrVerifyContract.
 	// Use these when calling HpcrVerifyContract to specify which section(s) to validate.
 	SectionBoth     = ""         // Validate both workload and env sections (default, backward compatible)
@@ -49,6 +69,349 @@ const (
 	SectionEnv      = "env"      // Validate only env section
 )
 
+// EncryptOption configures optional behaviour of [HpcrContractSignedEncrypted],
+// [HpcrContractSignedEncryptedContractExpiry] and the [HpcrTextEncrypted], [HpcrJsonEncrypted] and
+// [HpcrTgzEncrypted] functions. Section selection only applies to contracts; the other functions
+// reject it.
+type EncryptOption func(*encryptConfig)
+
+// encryptConfig holds the settings applied by EncryptOption values.
+type encryptConfig struct {
+	section           string
+	sectionSet        bool
+	certStore         cert.CertStore
+	skipExpired       bool
+	resolvedVersion   *string
+	at                time.Time
+	skipIdentityCheck bool
+	trustVerifier     *certificate.TrustVerifier
+}
+
+// defaultTrustVerifier is shared by all strict trust verifications with the default trust chain. It
+// verifies against the embedded intermediates and caches CRLs on disk, so a CRL is downloaded once per
+// nextUpdate period rather than once per process.
+var defaultTrustVerifier = sync.OnceValue(func() *certificate.TrustVerifier {
+	return certificate.HpcrNewTrustVerifier(defaultTrustChain(defaultCRLCacheDir()))
+})
+
+// defaultCRLCacheDir returns the directory the default strict trust mode caches CRLs in, or "" if the
+// user has no cache directory.
+func defaultCRLCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "contract-go", "crls")
+}
+
+// defaultTrustChain returns the trust chain of the default strict trust mode, which caches CRLs in dir.
+// CRLs are only cached in memory if dir is empty or cannot be created.
+func defaultTrustChain(dir string) certificate.TrustChain {
+	if dir == "" {
+		return certificate.TrustChain{}
+	}
+	store, err := certificate.HpcrNewCRLStore(dir, false)
+	if err != nil {
+		return certificate.TrustChain{}
+	}
+	return certificate.TrustChain{CRLStore: store}
+}
+
+// WithEncryptSection restricts encryption to a single contract section. Functions that encrypt
+// anything other than a contract return an error when it is given.
+//
+// Parameters:
+//   - section: [SectionBoth] (default), [SectionWorkload] or [SectionEnv]. The other section is
+//     passed through unchanged, whether it is plaintext or already encrypted.
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithEncryptSection(section string) EncryptOption {
+	return func(c *encryptConfig) {
+		c.section = section
+		c.sectionSet = true
+	}
+}
+
+// WithCertStore resolves the encryption certificate of certVersion in the given store instead of
+// the default store. It has no effect when an encryption certificate is passed explicitly.
+//
+// Parameters:
+//   - store: Certificate store, e.g. one created with encryption.NewEmbeddedCertStore and extended with AddDir
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithCertStore(store cert.CertStore) EncryptOption {
+	return func(c *encryptConfig) {
+		c.certStore = store
+	}
+}
+
+// WithSkipExpiredCertificates skips expired certificates when certVersion is resolved in the
+// certificate store, so that the highest matching version that has not expired is used.
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithSkipExpiredCertificates() EncryptOption {
+	return func(c *encryptConfig) {
+		c.skipExpired = true
+	}
+}
+
+// WithResolvedCertVersion reports the encryption certificate version that certVersion resolved to.
+// The version is left unchanged when an encryption certificate is passed explicitly.
+//
+// Parameters:
+//   - version: Receives the resolved version (e.g. "26.5.1" for certVersion "~26.5")
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithResolvedCertVersion(version *string) EncryptOption {
+	return func(c *encryptConfig) {
+		c.resolvedVersion = version
+	}
+}
+
+// WithSkipCertificateIdentityCheck accepts an explicitly passed encryption certificate whose subject,
+// issuer or key usage does not match the target platform, e.g. a certificate of a test environment.
+// Without it such certificates are rejected (see encryption.CheckCertificateIdentity).
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithSkipCertificateIdentityCheck() EncryptOption {
+	return func(c *encryptConfig) {
+		c.skipIdentityCheck = true
+	}
+}
+
+// WithStrictTrust refuses to encrypt unless the encryption certificate is an authentic IBM encryption
+// certificate. Before every encryption the certificate document signature, the IBM and DigiCert
+// chain, the CRLs and the revocation status of the certificate are checked with
+// certificate.HpcrVerifyEncryptionCertificateTrust. This applies to certificates resolved in the
+// certificate store and to explicitly passed certificates.
+//
+// The certificate is verified against the embedded IBM and DigiCert intermediates and DigiCert Trusted
+// Root G4. With the zero chain and no download options CRLs are cached in the contract-go/crls
+// directory of the user cache directory (see os.UserCacheDir) and reused until their nextUpdate time,
+// so only stale CRLs are downloaded. Otherwise downloaded CRLs are kept in memory for later encryptions
+// with the same option. Downloads for one verification are canceled after two minutes.
+//
+// Parameters:
+//   - chain: Trust chain; the zero value uses the embedded certificates and the CRL cache directory.
+//     Set CRLStore to a store created with certificate.HpcrNewCRLStore to cache CRLs elsewhere or to
+//     verify offline with imported CRLs
+//   - opts: Download options for CRLs, e.g. certificate.WithHTTPClient, or certificate.WithIssuerDownload
+//     to download intermediates that are not embedded
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithStrictTrust(chain certificate.TrustChain, opts ...certificate.DownloadOption) EncryptOption {
+	var verifier *certificate.TrustVerifier
+	if chain == (certificate.TrustChain{}) && len(opts) == 0 {
+		verifier = defaultTrustVerifier()
+	} else {
+		verifier = certificate.HpcrNewTrustVerifier(chain, opts...)
+	}
+
+	return func(c *encryptConfig) {
+		c.trustVerifier = verifier
+	}
+}
+
+// WithDeployTime checks certificates at the given time, e.g. a planned deployment date, instead of the
+// current time. The encryption certificate must not be expired at that time; with
+// [WithSkipExpiredCertificates], certificates expired at that time are skipped. For
+// [HpcrContractSignedEncryptedContractExpiry], the contract signing certificate must be valid at that time.
+//
+// Parameters:
+//   - at: Time the certificates must be valid at
+//
+// Returns:
+//   - EncryptOption to pass to the contract encryption functions
+func WithDeployTime(at time.Time) EncryptOption {
+	return func(c *encryptConfig) {
+		c.at = at
+	}
+}
+
+// newEncryptConfig applies the given options on top of the defaults.
+func newEncryptConfig(opts []EncryptOption) (encryptConfig, error) {
+	cfg := encryptConfig{section: SectionBoth}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.section != SectionBoth && cfg.section != SectionWorkload && cfg.section != SectionEnv {
+		return cfg, fmt.Errorf("invalid section %q: must be \"\" (both), \"workload\", or \"env\"", cfg.section)
+	}
+
+	return cfg, nil
+}
+
+// fetchCertificate resolves the encryption certificate in the configured store and reports the
+// resolved version. An explicitly passed certificate must match the identity of the platform, and in
+// strict trust mode the certificate must pass the chain and CRL checks.
+func (c encryptConfig) fetchCertificate(confidentialComputingOs, encryptionCertificate, certVersion string) (string, error) {
+	if encryptionCertificate != "" && !c.skipIdentityCheck {
+		platform := confidentialComputingOs
+		if platform == "" {
+			platform = gen.HyperProtectOsHpvs
+		}
+		if err := cert.CheckCertificateIdentity(platform, encryptionCertificate); err != nil {
+			return "", fmt.Errorf("encryption certificate does not match platform %s - %v", platform, err)
+		}
+	}
+
+	encCert, version, err := gen.ResolveEncryptionCertificate(c.certStore, confidentialComputingOs, encryptionCertificate, certVersion, c.skipExpired, c.at)
+	if err != nil {
+		return "", err
+	}
+
+	if c.trustVerifier != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), strictTrustTimeout)
+		defer cancel()
+		if err := c.trustVerifier.VerifyAt(ctx, encCert, c.at); err != nil {
+			return "", fmt.Errorf("encryption certificate failed strict trust verification - %v", err)
+		}
+	}
+
+	if encryptionCertificate == "" && c.resolvedVersion != nil {
+		*c.resolvedVersion = version
+	}
+	return encCert, nil
+}
+
+// Feature identifiers reported by the template catalog. See [HpcrListContractTemplates].
+const (
+	TemplateFeatureCompose                = "compose"
+	TemplateFeaturePlay                   = "play"
+	TemplateFeatureVolumes                = "volumes"
+	TemplateFeatureKmsVolumes             = "kms-volumes"
+	TemplateFeatureAttestationPublicKey   = "attestation-public-key"
+	TemplateFeatureHostAttestation        = "host-attestation"
+	TemplateFeatureConfidentialContainers = "confidential-containers"
+)
+
+// TemplateInfo describes a contract template available through [HpcrContractTemplate].
+type TemplateInfo struct {
+	Name             string   `json:"name" yaml:"name"`
+	Platform         string   `json:"platform" yaml:"platform"`
+	Description      string   `json:"description" yaml:"description"`
+	Features         []string `json:"features" yaml:"features"`
+	WorkloadTemplate string   `json:"workloadTemplate" yaml:"workloadTemplate"`
+	EnvTemplate      string   `json:"envTemplate" yaml:"envTemplate"`
+}
+
+// hasFeature reports whether the template provides the given feature.
+func (t TemplateInfo) hasFeature(feature string) bool {
+	for _, f := range t.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// templateCatalog lists every template known to HpcrContractTemplate, in display order.
+var templateCatalog = []TemplateInfo{
+	{
+		Name:             "ccrt",
+		Platform:         gen.ConfidentialComputingOsCcrt,
+		Description:      "Standard CCRT contract with compose and play workload options and encrypted data volumes",
+		Features:         []string{TemplateFeatureCompose, TemplateFeaturePlay, TemplateFeatureVolumes},
+		WorkloadTemplate: workloadCcrtTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "ccrt-compose",
+		Platform:         gen.ConfidentialComputingOsCcrt,
+		Description:      "CCRT contract running a docker compose archive",
+		Features:         []string{TemplateFeatureCompose, TemplateFeatureVolumes},
+		WorkloadTemplate: workloadCcrtComposeTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "ccrt-play",
+		Platform:         gen.ConfidentialComputingOsCcrt,
+		Description:      "CCRT contract running a podman play kube archive",
+		Features:         []string{TemplateFeaturePlay, TemplateFeatureVolumes},
+		WorkloadTemplate: workloadCcrtPlayTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "ccrt-kms",
+		Platform:         gen.ConfidentialComputingOsCcrt,
+		Description:      "CCRT contract with data volume seeds wrapped by Hyper Protect Crypto Services root keys",
+		Features:         []string{TemplateFeatureCompose, TemplateFeaturePlay, TemplateFeatureVolumes, TemplateFeatureKmsVolumes},
+		WorkloadTemplate: workloadCcrtTemplateFilePath,
+		EnvTemplate:      envKmsTemplateFilePath,
+	},
+	{
+		Name:             "ccrt-attestation",
+		Platform:         gen.ConfidentialComputingOsCcrt,
+		Description:      "Standard CCRT contract with an attestation public key for encrypted attestation records",
+		Features:         []string{TemplateFeatureCompose, TemplateFeaturePlay, TemplateFeatureVolumes, TemplateFeatureAttestationPublicKey},
+		WorkloadTemplate: workloadCcrtTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "hpvs",
+		Platform:         gen.HyperProtectOsHpvs,
+		Description:      "Standard HPVS contract with compose and play workload options and encrypted data volumes",
+		Features:         []string{TemplateFeatureCompose, TemplateFeaturePlay, TemplateFeatureVolumes},
+		WorkloadTemplate: workloadCcrtTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "hpvs-kms",
+		Platform:         gen.HyperProtectOsHpvs,
+		Description:      "HPVS contract with data volume seeds wrapped by Hyper Protect Crypto Services root keys",
+		Features:         []string{TemplateFeatureCompose, TemplateFeaturePlay, TemplateFeatureVolumes, TemplateFeatureKmsVolumes},
+		WorkloadTemplate: workloadCcrtTemplateFilePath,
+		EnvTemplate:      envKmsTemplateFilePath,
+	},
+	{
+		Name:             "ccrv",
+		Platform:         gen.ConfidentialComputingOsCcrv,
+		Description:      "CCRV contract running a podman play kube archive (compose is not supported)",
+		Features:         []string{TemplateFeaturePlay, TemplateFeatureVolumes},
+		WorkloadTemplate: workloadCcrvTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "ccrv-kms",
+		Platform:         gen.ConfidentialComputingOsCcrv,
+		Description:      "CCRV contract with data volume seeds wrapped by Hyper Protect Crypto Services root keys",
+		Features:         []string{TemplateFeaturePlay, TemplateFeatureVolumes, TemplateFeatureKmsVolumes},
+		WorkloadTemplate: workloadCcrvTemplateFilePath,
+		EnvTemplate:      envKmsTemplateFilePath,
+	},
+	{
+		Name:             "ccrv-attestation",
+		Platform:         gen.ConfidentialComputingOsCcrv,
+		Description:      "CCRV contract with an attestation public key for encrypted attestation records",
+		Features:         []string{TemplateFeaturePlay, TemplateFeatureVolumes, TemplateFeatureAttestationPublicKey},
+		WorkloadTemplate: workloadCcrvTemplateFilePath,
+		EnvTemplate:      envTemplateFilePath,
+	},
+	{
+		Name:             "ccco-peerpod",
+		Platform:         gen.ConfidentialComputingOsCcco,
+		Description:      "CCCO peer pod contract with confidential-containers configuration",
+		Features:         []string{TemplateFeatureConfidentialContainers},
+		WorkloadTemplate: workloadCccoPeerpodTemplateFilePath,
+		EnvTemplate:      envCccoPeerpodTemplateFilePath,
+	},
+	{
+		Name:             "ccco-bmtl",
+		Platform:         gen.ConfidentialComputingOsCcco,
+		Description:      "CCCO bare metal contract with confidential-containers configuration, data volumes and host-attestation",
+		Features:         []string{TemplateFeatureConfidentialContainers, TemplateFeatureVolumes, TemplateFeatureHostAttestation},
+		WorkloadTemplate: workloadCccoBmtlTemplateFilePath,
+		EnvTemplate:      envCccoBmtlTemplateFilePath,
+	},
+}
+
 // HPCC initdata.toml file template without sehdr bin.
 const tomlTemplate = `
 algorithm = "sha384"
@@ -138,7 +501,10 @@ func HpcrJson(plainJson string) (string, string, string, error) {
 //     Defaults to "ccrt" if empty.
 //   - encryptionCertificate: PEM-formatted IBM encryption certificate. If empty, the library
 //     uses the embedded default certificate for the specified platform.
-//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0"). Uses latest if empty.
+//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0").
+//     Also accepts a semantic version constraint (e.g., "~26.5", ">=26.4.0 <27"),
+//     resolved to the highest matching version. Uses latest if empty.
+//   - opts: Optional settings, e.g. [WithResolvedCertVersion] to report the resolved certificate version
 //
 // Returns:
 //   - Encrypted data in format "contract-basic.<encrypted-password>.<encrypted-data>" for CCRT/CCRV
@@ -146,12 +512,12 @@ func HpcrJson(plainJson string) (string, string, string, error) {
 //   - SHA256 hash of the original text (input checksum)
 //   - SHA256 hash of the encrypted output (output checksum)
 //   - Error if encryption fails or certificate is invalid
-func HpcrTextEncrypted(plainText, confidentialComputingOs, certVersion, encryptionCertificate string) (string, string, string, error) {
+func HpcrTextEncrypted(plainText, confidentialComputingOs, certVersion, encryptionCertificate string, opts ...EncryptOption) (string, string, string, error) {
 	if gen.CheckIfEmpty(plainText) {
 		return "", "", "", fmt.Errorf(emptyParameterErrStatement)
 	}
 
-	hpcrTextEncryptedStr, err := encrypter(plainText, confidentialComputingOs, certVersion, encryptionCertificate)
+	hpcrTextEncryptedStr, err := encryptWithOptions(plainText, confidentialComputingOs, certVersion, encryptionCertificate, opts)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate encrypted string - %v", err)
 	}
@@ -204,7 +570,10 @@ func HpcrTextDecrypted(encryptedText, privateKey, password string) (string, stri
 //     Defaults to "ccrt" if empty.
 //   - encryptionCertificate: PEM-formatted IBM encryption certificate. If empty, the library
 //     uses the embedded default certificate for the specified platform.
-//   - certVersion: Certificate version (e.g., "26.2.0", "25.11.0"). Uses latest if empty.
+//   - certVersion: Certificate version (e.g., "26.2.0", "25.11.0").
+//     Also accepts a semantic version constraint (e.g., "~26.5", ">=26.4.0 <27"),
+//     resolved to the highest matching version. Uses latest if empty.
+//   - opts: Optional settings, e.g. [WithResolvedCertVersion] to report the resolved certificate version
 //
 // Returns:
 //   - Encrypted JSON in format "contract-basic.<encrypted-password>.<encrypted-data>" for CCRT/CCRV
@@ -212,12 +581,12 @@ func HpcrTextDecrypted(encryptedText, privateKey, password string) (string, stri
 //   - SHA256 hash of the original JSON (input checksum)
 //   - SHA256 hash of the encrypted output (output checksum)
 //   - Error if JSON is invalid or encryption fails
-func HpcrJsonEncrypted(plainJson, confidentialComputingOs, certVersion, encryptionCertificate string) (string, string, string, error) {
+func HpcrJsonEncrypted(plainJson, confidentialComputingOs, certVersion, encryptionCertificate string, opts ...EncryptOption) (string, string, string, error) {
 	if !gen.IsJSON(plainJson) {
 		return "", "", "", fmt.Errorf("contract is not a JSON data")
 	}
 
-	hpcrJsonEncrypted, err := encrypter(plainJson, confidentialComputingOs, certVersion, encryptionCertificate)
+	hpcrJsonEncrypted, err := encryptWithOptions(plainJson, confidentialComputingOs, certVersion, encryptionCertificate, opts)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate encrypted JSON - %v", err)
 	}
@@ -278,7 +647,10 @@ func HpcrTgz(folderPath string) (string, string, string, error) {
 //     Defaults to "hpvs" if empty.
 //   - encryptionCertificate: PEM-formatted IBM encryption certificate. If empty, the library
 //     uses the embedded default certificate for the specified platform.
-//   - certVersion: Certificate version (e.g., "26.2.0", "25.11.0"). Uses latest if empty.
+//   - certVersion: Certificate version (e.g., "26.2.0", "25.11.0").
+//     Also accepts a semantic version constraint (e.g., "~26.5", ">=26.4.0 <27"),
+//     resolved to the highest matching version. Uses latest if empty.
+//   - opts: Optional settings, e.g. [WithResolvedCertVersion] to report the resolved certificate version
 //
 // Returns:
 //   - Encrypted TGZ in format "contract-basic.<encrypted-password>.<encrypted-data>" for CCRT/CCRV
@@ -286,7 +658,7 @@ func HpcrTgz(folderPath string) (string, string, string, error) {
 //   - SHA256 hash of the folder path (input checksum)
 //   - SHA256 hash of the encrypted output (output checksum)
 //   - Error if folder is invalid or encryption fails
-func HpcrTgzEncrypted(folderPath, confidentialComputingOs, certVersion, encryptionCertificate string) (string, string, string, error) {
+func HpcrTgzEncrypted(folderPath, confidentialComputingOs, certVersion, encryptionCertificate string, opts ...EncryptOption) (string, string, string, error) {
 	if gen.CheckIfEmpty(folderPath) {
 		return "", "", "", fmt.Errorf(emptyParameterErrStatement)
 	}
@@ -296,7 +668,7 @@ func HpcrTgzEncrypted(folderPath, confidentialComputingOs, certVersion, encrypti
 		return "", "", "", err
 	}
 
-	hpcrTgzEncryptedStr, err := encrypter(tgzBase64, confidentialComputingOs, certVersion, encryptionCertificate)
+	hpcrTgzEncryptedStr, err := encryptWithOptions(tgzBase64, confidentialComputingOs, certVersion, encryptionCertificate, opts)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate encrypted tgz - %v", err)
 	}
@@ -363,15 +735,27 @@ func HpcrVerifyContract(contract, version, section string) error {
 //   - privateKey: RSA private key (PEM format) for signing the contract.
 //     Generate with: openssl genrsa -out private.pem 4096
 //   - password: Optional password to unlock the encrypted private key (empty string "" for unencrypted keys)
-//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0"). Uses latest if empty.
+//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0").
+//     Also accepts a semantic version constraint (e.g., "~26.5", ">=26.4.0 <27"),
+//     resolved to the highest matching version. Uses latest if empty.
+//   - opts: Optional settings, e.g. [WithEncryptSection] to encrypt only one section
+//
+// Sections that are already encrypted are not encrypted again: an encrypted workload or
+// attestationPublicKey is passed through unchanged, and only plaintext sections are validated
+// against the schema. An encrypted env is rejected because the signing key must be injected into it.
 //
 // Returns:
 //   - Signed and encrypted contract YAML containing workload, env, and envWorkloadSignature sections
 //   - SHA256 hash of the original contract (input checksum)
 //   - SHA256 hash of the final signed contract (output checksum)
 //   - Error if validation, encryption, or signing fails
-func HpcrContractSignedEncrypted(contract, confidentialComputingOs, certVersion, encryptionCertificate, privateKey, password string) (string, string, string, error) {
-	err := HpcrVerifyContract(contract, confidentialComputingOs, SectionBoth)
+func HpcrContractSignedEncrypted(contract, confidentialComputingOs, certVersion, encryptionCertificate, privateKey, password string, opts ...EncryptOption) (string, string, string, error) {
+	cfg, err := newEncryptConfig(opts)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	err = verifyPlaintextSections(contract, confidentialComputingOs)
 	if err != nil {
 		return "", "", "", fmt.Errorf("schema verification failed - %v", err)
 	}
@@ -380,12 +764,12 @@ func HpcrContractSignedEncrypted(contract, confidentialComputingOs, certVersion,
 		return "", "", "", fmt.Errorf(emptyParameterErrStatement)
 	}
 
-	encryptCertificate, err := gen.FetchEncryptionCertificate(confidentialComputingOs, encryptionCertificate, certVersion)
+	encryptCertificate, err := cfg.fetchCertificate(confidentialComputingOs, encryptionCertificate, certVersion)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to fetch encryption certificate - %v", err)
 	}
 
-	_, err = gen.CheckEncryptionCertValidityForContractEncryption(encryptCertificate)
+	_, err = gen.CheckEncryptionCertValidityForContractEncryptionAt(encryptCertificate, cfg.at)
 	if err != nil {
 		return "", "", "", fmt.Errorf("Failed to encrypt contract - %v", err)
 	}
@@ -395,7 +779,7 @@ func HpcrContractSignedEncrypted(contract, confidentialComputingOs, certVersion,
 		return "", "", "", fmt.Errorf("failed to generate public key - %v", err)
 	}
 
-	signedEncryptContract, err := encryptWrapper(contract, confidentialComputingOs, certVersion, encryptCertificate, privateKey, password, publicKey)
+	signedEncryptContract, err := encryptWrapper(contract, confidentialComputingOs, encryptCertificate, privateKey, password, publicKey, cfg.section)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to sign and encrypt contract - %v", err)
 	}
@@ -432,15 +816,25 @@ func HpcrContractSignedEncrypted(contract, confidentialComputingOs, certVersion,
 //   - csrPemData: Pre-generated Certificate Signing Request in PEM format.
 //     Provide this OR csrDataStr, not both.
 //   - expiryDays: Number of days until the contract expires (must be > 0)
-//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0"). Uses latest if empty.
+//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0").
+//     Also accepts a semantic version constraint (e.g., "~26.5", ">=26.4.0 <27"),
+//     resolved to the highest matching version. Uses latest if empty.
+//   - opts: Optional settings, e.g. [WithEncryptSection] to encrypt only one section
+//
+// Already encrypted sections are handled as described for [HpcrContractSignedEncrypted].
 //
 // Returns:
 //   - Signed and encrypted contract YAML with a time-limited signature
 //   - SHA256 hash of the original contract (input checksum)
 //   - SHA256 hash of the final signed contract (output checksum)
 //   - Error if validation, CSR generation, certificate creation, or signing fails
-func HpcrContractSignedEncryptedContractExpiry(contract, confidentialComputingOs, certVersion, encryptionCertificate, privateKey, password, cacert, caKey, csrDataStr, csrPemData string, expiryDays int) (string, string, string, error) {
-	err := HpcrVerifyContract(contract, confidentialComputingOs, SectionBoth)
+func HpcrContractSignedEncryptedContractExpiry(contract, confidentialComputingOs, certVersion, encryptionCertificate, privateKey, password, cacert, caKey, csrDataStr, csrPemData string, expiryDays int, opts ...EncryptOption) (string, string, string, error) {
+	cfg, err := newEncryptConfig(opts)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	err = verifyPlaintextSections(contract, confidentialComputingOs)
 	if err != nil {
 		return "", "", "", fmt.Errorf("schema verification failed - %v", err)
 	}
@@ -458,7 +852,22 @@ func HpcrContractSignedEncryptedContractExpiry(contract, confidentialComputingOs
 		return "", "", "", fmt.Errorf("failed to generate signing certificate - %v", err)
 	}
 
-	finalContract, err := encryptWrapper(contract, confidentialComputingOs, certVersion, encryptionCertificate, privateKey, password, signingCert)
+	encryptCertificate, err := cfg.fetchCertificate(confidentialComputingOs, encryptionCertificate, certVersion)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to fetch encryption certificate - %v", err)
+	}
+
+	if !cfg.at.IsZero() {
+		if _, err := gen.CheckEncryptionCertValidityForContractEncryptionAt(encryptCertificate, cfg.at); err != nil {
+			return "", "", "", fmt.Errorf("Failed to encrypt contract - %v", err)
+		}
+
+		if err := checkSigningCertValidity(signingCert, cfg.at); err != nil {
+			return "", "", "", err
+		}
+	}
+
+	finalContract, err := encryptWrapper(contract, confidentialComputingOs, encryptCertificate, privateKey, password, signingCert, cfg.section)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to generate signed and encrypted contract - %v", err)
 	}
@@ -512,42 +921,44 @@ func HpcrContractSign(contract, privateKey, password string) (string, string, st
 
 // HpcrContractTemplate returns contract template content for workload, env, or both.
 //
+// Use [HpcrListContractTemplates] to discover the available template names and the features
+// each of them covers (compose vs play, KMS volumes, attestation public key, host-attestation).
+//
 // Parameters:
 //   - templateType: "workload", "env", or "" (returns both templates combined)
-//   - os: Target IBM Confidential Computing platform:
-//     "ccrv" — workload template (podman play only, no compose)
-//     "ccco-peerpod" — confidential-containers workload/env template
-//     "ccco-bmtl" — confidential-containers workload/env template (with volumes and host-attestation)
-//     "hpvs", "ccrt", or "" — standard workload template (compose + play)
+//   - os: Template name from the catalog, e.g. "ccrt", "ccrt-play", "ccrt-kms", "ccrv",
+//     "ccco-peerpod" or "ccco-bmtl". Defaults to "ccrt" if empty. Unknown names are rejected.
 //
 // Returns:
-//   - Template content as string
-//   - Error if template type is unsupported or file read fails
+//   - Template content as string. Combined templates of catalog entries with the
+//     "attestation-public-key" feature also contain an attestationPublicKey placeholder.
+//   - Error if the template type or name is unsupported or file read fails
 func HpcrContractTemplate(templateType, os string) (string, error) {
-	workloadFile := resolveWorkloadTemplateFile(os)
-
-	envFile := resolveEnvTemplateFile(os)
+	templateInfo, err := resolveTemplate(os)
+	if err != nil {
+		return "", err
+	}
 
 	switch templateType {
 	case "workload":
-		workloadTemplate, err := readHpcrTemplateFile(workloadFile)
+		workloadTemplate, err := readHpcrTemplateFile(templateInfo.WorkloadTemplate)
 		if err != nil {
 			return "", fmt.Errorf("failed to read workload template - %v", err)
 		}
 		return workloadTemplate, nil
 	case "env":
-		envTemplate, err := readHpcrTemplateFile(envFile)
+		envTemplate, err := readHpcrTemplateFile(templateInfo.EnvTemplate)
 		if err != nil {
 			return "", fmt.Errorf("failed to read env template - %v", err)
 		}
 		return envTemplate, nil
 	case "":
-		workloadTemplate, err := readHpcrTemplateFile(workloadFile)
+		workloadTemplate, err := readHpcrTemplateFile(templateInfo.WorkloadTemplate)
 		if err != nil {
 			return "", fmt.Errorf("failed to read workload template - %v", err)
 		}
 
-		envTemplate, err := readHpcrTemplateFile(envFile)
+		envTemplate, err := readHpcrTemplateFile(templateInfo.EnvTemplate)
 		if err != nil {
 			return "", fmt.Errorf("failed to read env template - %v", err)
 		}
@@ -561,36 +972,81 @@ func HpcrContractTemplate(templateType, os string) (string, error) {
 		templateBuilder.WriteString("env: |\n")
 		templateBuilder.WriteString(indentTemplateContent(envTemplate))
 
+		if templateInfo.hasFeature(TemplateFeatureAttestationPublicKey) {
+			if !strings.HasSuffix(envTemplate, "\n") {
+				templateBuilder.WriteString("\n")
+			}
+			templateBuilder.WriteString(attestationPublicKeyTemplate)
+		}
+
 		return templateBuilder.String(), nil
 	default:
 		return "", fmt.Errorf("unsupported template type: %s", templateType)
 	}
 }
 
-// resolveWorkloadTemplateFile returns the workload template filename for the given OS.
-func resolveWorkloadTemplateFile(os string) string {
-	switch os {
-	case "ccrv":
-		return workloadCcrvTemplateFilePath
-	case "ccco-peerpod":
-		return workloadCccoPeerpodTemplateFilePath
-	case "ccco-bmtl":
-		return workloadCccoBmtlTemplateFilePath
+// HpcrListContractTemplates returns the contract template catalog in JSON or YAML format.
+//
+// Each entry carries the template name to pass to [HpcrContractTemplate], the target platform,
+// a description, the features it covers and the workload/env template files it is built from.
+//
+// Parameters:
+//   - platform: "ccrt", "ccrv", "ccco", "hpvs", or "" for the templates of all platforms
+//   - formatType: Output format — "json" or "yaml" (defaults to "json" if empty)
+//
+// Returns:
+//   - JSON or YAML list of template descriptions
+//   - Error if the platform or format is invalid or marshaling fails
+func HpcrListContractTemplates(platform, formatType string) (string, error) {
+	if formatType == "" {
+		formatType = formatJson
+	}
+
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	if platform != "" && platform != gen.ConfidentialComputingOsCcrt && platform != gen.ConfidentialComputingOsCcrv &&
+		platform != gen.ConfidentialComputingOsCcco && platform != gen.HyperProtectOsHpvs {
+		return "", fmt.Errorf("invalid platform: %s. Valid platforms are: ccrt, ccrv, ccco, hpvs", platform)
+	}
+
+	templates := []TemplateInfo{}
+	for _, templateInfo := range templateCatalog {
+		if platform == "" || templateInfo.Platform == platform {
+			templates = append(templates, templateInfo)
+		}
+	}
+
+	switch formatType {
+	case formatJson:
+		jsonBytes, err := json.Marshal(templates)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON - %v", err)
+		}
+		return string(jsonBytes), nil
+	case formatYaml:
+		yamlBytes, err := yaml.Marshal(templates)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal YAML - %v", err)
+		}
+		return string(yamlBytes), nil
 	default:
-		return workloadCcrtTemplateFilePath
+		return "", fmt.Errorf("invalid output format: %s. Valid formats are: json, yaml", formatType)
 	}
 }
 
-// resolveEnvTemplateFile returns the env template filename for the given OS.
-func resolveEnvTemplateFile(os string) string {
-	switch os {
-	case "ccco-peerpod":
-		return envCccoPeerpodTemplateFilePath
-	case "ccco-bmtl":
-		return envCccoBmtlTemplateFilePath
-	default:
-		return envTemplateFilePath
+// resolveTemplate looks up a template by name in the catalog. An empty name selects the default template.
+func resolveTemplate(name string) (TemplateInfo, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = defaultTemplateName
+	}
+
+	for _, templateInfo := range templateCatalog {
+		if templateInfo.Name == name {
+			return templateInfo, nil
+		}
 	}
+
+	return TemplateInfo{}, fmt.Errorf("unsupported template name: %s. Use HpcrListContractTemplates to list the available templates", name)
 }
 
 // HpccInitdata generates gzipped and Base64-encoded initdata for IBM Confidential Computing
@@ -657,67 +1113,82 @@ func HpccInitdata(contract, encodedHdrBin string) (string, string, string, error
 // It handles both regular signing and signing with contract expiry by accepting a publicKey
 // parameter that can be either a regular public key or a time-limited signing certificate.
 // The function encrypts the workload and env sections separately, injects the signing key
-// into the env section, and creates a signature over the encrypted sections.
+// into the env section, and creates a signature over the encrypted sections. Sections that are
+// already encrypted, or excluded by the section setting, are passed through unchanged.
+// The encryption certificate is expected to be resolved and checked by the caller.
 //
 // Parameters:
 //   - contract: YAML contract string with workload and env sections
 //   - confidentialComputingOs: Target platform — "ccrt", "ccrv", or "ccco" (default: ccrt)
-//   - encryptionCertificate: PEM-formatted encryption certificate (optional, uses default if empty)
+//   - encryptCertificate: Resolved PEM-formatted encryption certificate
 //   - privateKey: RSA private key (PEM format) for signing
 //   - password: Optional password to unlock the encrypted private key (empty string "" for unencrypted keys)
 //   - publicKey: Public key or signing certificate (PEM format) to inject into the env section
-//   - certVersion: Encryption Certificate version (e.g., "26.2.0", "25.11.0"). Uses latest if empty.
+//   - section: Section to encrypt - SectionWorkload, SectionEnv, or SectionBoth
 //
 // Returns:
 //   - Final contract YAML with encrypted workload, env, and envWorkloadSignature
 //   - Error if encryption or signing fails
-func encryptWrapper(contract, confidentialComputingOs, certVersion, encryptionCertificate, privateKey, password, publicKey string) (string, error) {
-	if gen.CheckIfEmpty(contract, privateKey, publicKey) {
+func encryptWrapper(contract, confidentialComputingOs, encryptCertificate, privateKey, password, publicKey, section string) (string, error) {
+	if gen.CheckIfEmpty(contract, privateKey, publicKey, encryptCertificate) {
 		return "", fmt.Errorf(emptyParameterErrStatement)
 	}
 
 	var contractMap map[string]interface{}
 
-	encryptCertificate, err := gen.FetchEncryptionCertificate(confidentialComputingOs, encryptionCertificate, certVersion)
+	err := yaml.Unmarshal([]byte(contract), &contractMap)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch encryption certificate - %v", err)
+		return "", fmt.Errorf("failed to unmarshal YAML - %v", err)
 	}
 
-	err = yaml.Unmarshal([]byte(contract), &contractMap)
+	workload, workloadEncrypted, err := contractSection(contractMap, SectionWorkload, confidentialComputingOs)
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal YAML - %v", err)
+		return "", err
 	}
 
-	encryptedWorkload, err := encrypter(contractMap["workload"].(string), confidentialComputingOs, certVersion, encryptCertificate)
+	env, envEncrypted, err := contractSection(contractMap, SectionEnv, confidentialComputingOs)
 	if err != nil {
-		return "", fmt.Errorf("failed to encrypt workload - %v", err)
+		return "", err
+	}
+	if envEncrypted {
+		return "", fmt.Errorf("env section is already encrypted - signingKey cannot be injected into an encrypted env, use HpcrContractSign to sign pre-encrypted contracts")
+	}
+
+	finalWorkload := workload
+	if !workloadEncrypted && section != SectionEnv {
+		finalWorkload, err = encrypter(workload, confidentialComputingOs, "", encryptCertificate)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt workload - %v", err)
+		}
 	}
 
-	updatedEnv, err := gen.KeyValueInjector(contractMap["env"].(string), "signingKey", gen.EncodeToBase64([]byte(publicKey)))
+	finalEnv, err := gen.KeyValueInjector(env, "signingKey", gen.EncodeToBase64([]byte(publicKey)))
 	if err != nil {
 		return "", fmt.Errorf("failed to inject signingKey to env - %v", err)
 	}
 
-	encryptedEnv, err := encrypter(updatedEnv, confidentialComputingOs, certVersion, encryptCertificate)
-	if err != nil {
-		return "", fmt.Errorf("failed to encrypt env - %v", err)
+	if section != SectionWorkload {
+		finalEnv, err = encrypter(finalEnv, confidentialComputingOs, "", encryptCertificate)
+		if err != nil {
+			return "", fmt.Errorf("failed to encrypt env - %v", err)
+		}
 	}
 
-	workloadEnvSignature, err := enc.SignContract(encryptedWorkload, encryptedEnv, privateKey, password)
+	workloadEnvSignature, err := enc.SignContract(finalWorkload, finalEnv, privateKey, password)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign contract - %v", err)
 	}
 
 	attestationPublicKey, _ := contractMap["attestationPublicKey"].(string)
-	var encryptedAttestationPublicKey string
-	if attestationPublicKey != "" {
-		encryptedAttestationPublicKey, err = encrypter(attestationPublicKey, confidentialComputingOs, certVersion, encryptCertificate)
+	encryptedAttestationPublicKey := attestationPublicKey
+	if attestationPublicKey != "" && !isEncryptedSection(attestationPublicKey) {
+		encryptedAttestationPublicKey, err = encrypter(attestationPublicKey, confidentialComputingOs, "", encryptCertificate)
 		if err != nil {
 			return "", fmt.Errorf("failed to encrypt attestationPublicKey - %v", err)
 		}
 	}
 
-	finalContract, err := enc.GenFinalSignedContract(encryptedWorkload, encryptedEnv, workloadEnvSignature, encryptedAttestationPublicKey)
+	finalContract, err := enc.GenFinalSignedContract(finalWorkload, finalEnv, workloadEnvSignature, encryptedAttestationPublicKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate final contract - %v", err)
 	}
@@ -725,6 +1196,74 @@ func encryptWrapper(contract, confidentialComputingOs, certVersion, encryptionCe
 	return finalContract, nil
 }
 
+// checkSigningCertValidity checks that a Base64-encoded contract signing certificate is valid at the given time.
+func checkSigningCertValidity(signingCert string, at time.Time) error {
+	decoded, err := gen.DecodeBase64String(signingCert)
+	if err != nil {
+		return fmt.Errorf("failed to decode signing certificate - %v", err)
+	}
+
+	block, _ := pem.Decode([]byte(decoded))
+	if block == nil {
+		return fmt.Errorf("failed to parse signing certificate - no PEM encoded certificate found")
+	}
+
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse signing certificate - %v", err)
+	}
+
+	if at.Before(parsed.NotBefore) || at.After(parsed.NotAfter) {
+		return fmt.Errorf("contract signing certificate is not valid at %s (valid from %s to %s)",
+			at.UTC().Format("02-01-06 15:04:05"), parsed.NotBefore.UTC().Format("02-01-06 15:04:05"), parsed.NotAfter.UTC().Format("02-01-06 15:04:05"))
+	}
+
+	return nil
+}
+
+// contractSection returns a workload or env section of a parsed contract and whether it is already encrypted.
+// Encrypted sections must be valid encrypted tokens for the target platform.
+func contractSection(contractMap map[string]interface{}, section, confidentialComputingOs string) (string, bool, error) {
+	value, ok := contractMap[section].(string)
+	if !ok || gen.CheckIfEmpty(value) {
+		return "", false, fmt.Errorf("%s section is missing or not a string", section)
+	}
+
+	if !isEncryptedSection(value) {
+		return value, false, nil
+	}
+
+	if _, err := gen.ParseEncryptedToken(value, confidentialComputingOs); err != nil {
+		return "", false, fmt.Errorf("%s section is encrypted but invalid - %v", section, err)
+	}
+
+	return strings.TrimSpace(value), true, nil
+}
+
+// verifyPlaintextSections validates the plaintext sections of a contract against the schema.
+// Already encrypted sections are skipped since their content cannot be inspected.
+func verifyPlaintextSections(contract, confidentialComputingOs string) error {
+	var contractMap map[string]interface{}
+	if err := yaml.Unmarshal([]byte(contract), &contractMap); err != nil {
+		return HpcrVerifyContract(contract, confidentialComputingOs, SectionBoth)
+	}
+
+	workload, _ := contractMap[SectionWorkload].(string)
+	env, _ := contractMap[SectionEnv].(string)
+	workloadEncrypted, envEncrypted := isEncryptedSection(workload), isEncryptedSection(env)
+
+	switch {
+	case workloadEncrypted && envEncrypted:
+		return nil
+	case workloadEncrypted:
+		return HpcrVerifyContract(env, confidentialComputingOs, SectionEnv)
+	case envEncrypted:
+		return HpcrVerifyContract(workload, confidentialComputingOs, SectionWorkload)
+	default:
+		return HpcrVerifyContract(contract, confidentialComputingOs, SectionBoth)
+	}
+}
+
 // encrypter is an internal helper that encrypts any string using the IBM Confidential Computing
 // encryption format. It generates a random AES-256 password, encrypts the password with the
 // IBM encryption certificate (RSA), encrypts the data with the password (AES-256-CBC), and
@@ -769,6 +1308,25 @@ func encrypter(stringText, confidentialComputingOs, certVersion, encryptionCerti
 	return enc.EncryptFinalStr(encodedEncryptedPassword, encryptedString, confidentialComputingOs), nil
 }
 
+// encryptWithOptions resolves the encryption certificate using the given options and encrypts a string
+// with [encrypter]. The string is not a contract, so a section selection is rejected.
+func encryptWithOptions(stringText, confidentialComputingOs, certVersion, encryptionCertificate string, opts []EncryptOption) (string, error) {
+	cfg, err := newEncryptConfig(opts)
+	if err != nil {
+		return "", err
+	}
+	if cfg.sectionSet {
+		return "", fmt.Errorf("section selection only applies to contracts")
+	}
+
+	encCert, err := cfg.fetchCertificate(confidentialComputingOs, encryptionCertificate, certVersion)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch encryption certificate - %v", err)
+	}
+
+	return encrypter(stringText, confidentialComputingOs, certVersion, encCert)
+}
+
 // readHpcrTemplateFile resolves a template file path under contract/template and returns its content.
 func readHpcrTemplateFile(fileName string) (string, error) {
 	_, currentFile, _, ok := runtime.Caller(0)
@@ -17,12 +17,19 @@ package contract
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 
+	"github.com/ibm-hyper-protect/contract-go/v2/certificate"
+	enc "github.com/ibm-hyper-protect/contract-go/v2/common/encrypt"
 	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+	cert "github.com/ibm-hyper-protect/contract-go/v2/encryption"
 )
 
 const (
@@ -88,6 +95,9 @@ const (
 	sampleEnvTemplatePath                 = "./template/env.yaml"
 	sampleEnvCccoPeerpodTemplatePath      = "./template/env_ccco_peerpod.yaml"
 	sampleEnvCccoBmtlTemplatePath         = "./template/env_ccco_bmtl.yaml"
+	sampleWorkloadCcrtComposeTemplatePath = "./template/workload_ccrt_compose.yaml"
+	sampleWorkloadCcrtPlayTemplatePath    = "./template/workload_ccrt_play.yaml"
+	sampleEnvKmsTemplatePath              = "./template/env_kms.yaml"
 )
 
 var (
@@ -137,7 +147,8 @@ func common(testType string) (string, string, string, string, string, error) {
 		}
 
 		return contract, privateKey, publicKey, "", "", nil
-	} else if testType == "TestHpcrContractSignedEncryptedContractExpiryCsrParams" || testType == "TestHpcrContractSignedEncryptedContractExpiryCsrPem" {
+	} else if testType == "TestHpcrContractSignedEncryptedContractExpiryCsrParams" || testType == "TestHpcrContractSignedEncryptedContractExpiryCsrPem" ||
+		testType == "TestHpcrContractSignedEncryptedContractExpiryDeployTime" {
 		cePrivateKey, err := gen.ReadDataFromFile(sampleCePrivateKeyPath)
 		if err != nil {
 			return "", "", "", "", "", err
@@ -289,6 +300,62 @@ func TestHpcrContractSignedEncryptedContractExpiryCsrParams(t *testing.T) {
 	assert.Equal(t, inputSha256, simpleContractInputChecksum)
 }
 
+// Testcase to check if HpcrContractSignedEncryptedContractExpiry() checks the certificates at the time given with WithDeployTime()
+func TestHpcrContractSignedEncryptedContractExpiryDeployTime(t *testing.T) {
+	contract, privateKey, _, caCert, caKey, err := common(t.Name())
+	if err != nil {
+		t.Errorf("failed to get contract, private key, CA certificate and CA key - %v", err)
+	}
+
+	csrParams, err := json.Marshal(sampleCeCSRPems)
+	if err != nil {
+		t.Errorf("failed to unmarshal CSR parameters - %v", err)
+	}
+
+	result, _, _, err := HpcrContractSignedEncryptedContractExpiry(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", caCert, caKey, string(csrParams), "", 2,
+		WithDeployTime(time.Now().Add(24*time.Hour)))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result)
+
+	// The signing certificate is valid for 2 days from now
+	_, _, _, err = HpcrContractSignedEncryptedContractExpiry(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", caCert, caKey, string(csrParams), "", 2,
+		WithDeployTime(time.Now().Add(72*time.Hour)))
+	assert.ErrorContains(t, err, "contract signing certificate is not valid at")
+}
+
+// Testcase to check if checkSigningCertValidity() rejects expired and future-dated signing certificates
+func TestCheckSigningCertValidity(t *testing.T) {
+	_, privateKey, _, caCert, caKey, err := common("TestHpcrContractSignedEncryptedContractExpiryCsrPem")
+	if err != nil {
+		t.Errorf("failed to get private key, CA certificate and CA key - %v", err)
+	}
+
+	csr, err := gen.ReadDataFromFile(sampleCeCsrPath)
+	if err != nil {
+		t.Errorf("failed to read CSR file - %v", err)
+	}
+
+	now := time.Now()
+	valid, err := enc.CreateSigningCertAt(privateKey, caCert, caKey, "", csr, 2, now.Add(-time.Hour))
+	if err != nil {
+		t.Errorf("failed to create signing certificate - %v", err)
+	}
+	assert.NoError(t, checkSigningCertValidity(valid, now))
+
+	expired, err := enc.CreateSigningCertAt(privateKey, caCert, caKey, "", csr, 2, now.AddDate(0, 0, -3))
+	if err != nil {
+		t.Errorf("failed to create signing certificate - %v", err)
+	}
+	assert.ErrorContains(t, checkSigningCertValidity(expired, now), "contract signing certificate is not valid at")
+
+	future, err := enc.CreateSigningCertAt(privateKey, caCert, caKey, "", csr, 2, now.Add(time.Hour))
+	if err != nil {
+		t.Errorf("failed to create signing certificate - %v", err)
+	}
+	assert.ErrorContains(t, checkSigningCertValidity(future, now), "contract signing certificate is not valid at")
+	assert.NoError(t, checkSigningCertValidity(future, now.Add(2*time.Hour)))
+}
+
 // Testcase to check if HpcrContractSignedEncryptedContractExpiry() is able to create signed and encrypted contract with contract expiry enabled with CSR PEM data
 func TestHpcrContractSignedEncryptedContractExpiryCsrPem(t *testing.T) {
 	contract, privateKey, _, caCert, caKey, err := common(t.Name())
@@ -317,7 +384,12 @@ func TestEncryptWrapper(t *testing.T) {
 		t.Errorf("failed to get contract, private key and public key - %v", err)
 	}
 
-	result, err := encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", publicKey)
+	encryptCertificate, err := gen.FetchEncryptionCertificate(sampleConfidentialComputingOsVersion, "", "")
+	if err != nil {
+		t.Errorf("failed to fetch encryption certificate - %v", err)
+	}
+
+	result, err := encryptWrapper(contract, sampleConfidentialComputingOsVersion, encryptCertificate, privateKey, "", publicKey, SectionBoth)
 	if err != nil {
 		t.Errorf("failed to sign and encrypt contract - %v", err)
 	}
@@ -336,7 +408,12 @@ func TestEncryptWrapperAttestPubKey(t *testing.T) {
 		t.Errorf("failed to get contract, private key and public key - %v", err)
 	}
 
-	result, err := encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", publicKey)
+	encryptCertificate, err := gen.FetchEncryptionCertificate(sampleConfidentialComputingOsVersion, "", "")
+	if err != nil {
+		t.Errorf("failed to fetch encryption certificate - %v", err)
+	}
+
+	result, err := encryptWrapper(contract, sampleConfidentialComputingOsVersion, encryptCertificate, privateKey, "", publicKey, SectionBoth)
 	if err != nil {
 		t.Errorf("failed to sign and encrypt contract - %v", err)
 	}
@@ -348,6 +425,261 @@ func TestEncryptWrapperAttestPubKey(t *testing.T) {
 	assert.NotEmpty(t, result)
 }
 
+// readContractSectionsForTest parses a contract result into its top level string values.
+func readContractSectionsForTest(t *testing.T, contract string) map[string]string {
+	sections := map[string]string{}
+	if err := yaml.Unmarshal([]byte(contract), &sections); err != nil {
+		t.Fatalf("failed to parse contract - %v", err)
+	}
+	return sections
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() passes an already encrypted workload through unchanged
+func TestHpcrContractSignedEncryptedEncryptedWorkload(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+	sections := readContractSectionsForTest(t, contract)
+
+	encryptedWorkload, err := encrypter(sections["workload"], sampleConfidentialComputingOsVersion, "", "")
+	if err != nil {
+		t.Errorf("failed to encrypt workload - %v", err)
+	}
+
+	partialContract, err := gen.MapToYaml(map[string]interface{}{"workload": encryptedWorkload, "env": sections["env"]})
+	if err != nil {
+		t.Errorf("failed to build contract - %v", err)
+	}
+
+	result, _, _, err := HpcrContractSignedEncrypted(partialContract, sampleConfidentialComputingOsVersion, "", "", privateKey, "")
+	if err != nil {
+		t.Errorf("failed to generate signed and encrypted contract - %v", err)
+	}
+
+	resultSections := readContractSectionsForTest(t, result)
+	assert.Equal(t, encryptedWorkload, resultSections["workload"])
+	assert.True(t, strings.HasPrefix(resultSections["env"], ccrtEncryptPrefix))
+	assert.NotEmpty(t, resultSections["envWorkloadSignature"])
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() rejects an already encrypted env section
+func TestHpcrContractSignedEncryptedEncryptedEnv(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+	sections := readContractSectionsForTest(t, contract)
+
+	encryptedEnv, err := encrypter(sections["env"], sampleConfidentialComputingOsVersion, "", "")
+	if err != nil {
+		t.Errorf("failed to encrypt env - %v", err)
+	}
+
+	partialContract, err := gen.MapToYaml(map[string]interface{}{"workload": sections["workload"], "env": encryptedEnv})
+	if err != nil {
+		t.Errorf("failed to build contract - %v", err)
+	}
+
+	_, _, _, err = HpcrContractSignedEncrypted(partialContract, sampleConfidentialComputingOsVersion, "", "", privateKey, "")
+	assert.ErrorContains(t, err, "env section is already encrypted - signingKey cannot be injected into an encrypted env")
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() rejects encrypted sections of another platform
+func TestHpcrContractSignedEncryptedWrongPlatformToken(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+	sections := readContractSectionsForTest(t, contract)
+
+	encryptedWorkload, err := encrypter(sections["workload"], gen.HyperProtectOsHpvs, "", "")
+	if err != nil {
+		t.Errorf("failed to encrypt workload - %v", err)
+	}
+
+	partialContract, err := gen.MapToYaml(map[string]interface{}{"workload": encryptedWorkload, "env": sections["env"]})
+	if err != nil {
+		t.Errorf("failed to build contract - %v", err)
+	}
+
+	_, _, _, err = HpcrContractSignedEncrypted(partialContract, sampleConfidentialComputingOsVersion, "", "", privateKey, "")
+	assert.ErrorContains(t, err, `workload section is encrypted but invalid - malformed encrypted token: prefix "hyper-protect-basic" is not valid for platform ccrt`)
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() encrypts only the workload section when requested
+func TestHpcrContractSignedEncryptedWorkloadOnly(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+
+	result, _, _, err := HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", WithEncryptSection(SectionWorkload))
+	if err != nil {
+		t.Errorf("failed to generate signed and encrypted contract - %v", err)
+	}
+
+	resultSections := readContractSectionsForTest(t, result)
+	assert.True(t, strings.HasPrefix(resultSections["workload"], ccrtEncryptPrefix))
+	assert.Contains(t, resultSections["env"], "type: env")
+	assert.Contains(t, resultSections["env"], "signingKey:")
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() encrypts only the env section when requested
+func TestHpcrContractSignedEncryptedEnvOnly(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+	sections := readContractSectionsForTest(t, contract)
+
+	result, _, _, err := HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", WithEncryptSection(SectionEnv))
+	if err != nil {
+		t.Errorf("failed to generate signed and encrypted contract - %v", err)
+	}
+
+	resultSections := readContractSectionsForTest(t, result)
+	assert.Equal(t, sections["workload"], resultSections["workload"])
+	assert.True(t, strings.HasPrefix(resultSections["env"], ccrtEncryptPrefix))
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() rejects an invalid section option
+func TestHpcrContractSignedEncryptedInvalidSection(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+
+	_, _, _, err = HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", WithEncryptSection("attestation"))
+	assert.EqualError(t, err, `invalid section "attestation": must be "" (both), "workload", or "env"`)
+}
+
+// Testcase to check if HpcrTextEncrypted(), HpcrJsonEncrypted() and HpcrTgzEncrypted() reject a section selection
+func TestHpcrEncryptedRejectsSection(t *testing.T) {
+	_, _, _, err := HpcrTextEncrypted(sampleStringData, sampleConfidentialComputingOsVersion, "", "", WithEncryptSection(SectionWorkload))
+	assert.EqualError(t, err, "failed to generate encrypted string - section selection only applies to contracts")
+
+	_, _, _, err = HpcrJsonEncrypted(sampleStringJson, sampleConfidentialComputingOsVersion, "", "", WithEncryptSection(SectionEnv))
+	assert.EqualError(t, err, "failed to generate encrypted JSON - section selection only applies to contracts")
+
+	_, _, _, err = HpcrTgzEncrypted(sampleComposeFolderPath, sampleConfidentialComputingOsVersion, "", "", WithEncryptSection(SectionBoth))
+	assert.EqualError(t, err, "failed to generate encrypted tgz - section selection only applies to contracts")
+}
+
+// Testcase to check if HpcrContractSignedEncrypted() resolves the encryption certificate in the store given with WithCertStore()
+func TestHpcrContractSignedEncryptedWithCertStore(t *testing.T) {
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+
+	_, _, _, err = HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", WithCertStore(cert.NewCertStore()))
+	assert.EqualError(t, err, "failed to fetch encryption certificate - no certificates found for platform "+sampleConfidentialComputingOsVersion)
+
+	store := cert.NewCertStore()
+	latest, ok := cert.DefaultCertStore().Certificate(sampleConfidentialComputingOsVersion, "")
+	assert.True(t, ok)
+	assert.NoError(t, store.Add(sampleConfidentialComputingOsVersion, "99.0.0", latest))
+
+	_, _, _, err = HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "98.0.0", "", privateKey, "", WithCertStore(store))
+	assert.EqualError(t, err, "failed to fetch encryption certificate - certificate version 98.0.0 not found for platform "+sampleConfidentialComputingOsVersion)
+
+	// Certificates of the store are not subject to the identity check of explicitly passed certificates
+	cccoCert, ok := cert.DefaultCertStore().Certificate(gen.ConfidentialComputingOsCcco, "")
+	assert.True(t, ok)
+	assert.NoError(t, store.Add(sampleConfidentialComputingOsVersion, "100.0.0", cccoCert))
+
+	_, _, _, err = HpcrTextEncrypted(sampleStringData, sampleConfidentialComputingOsVersion, "100.0.0", "", WithCertStore(store))
+	assert.NoError(t, err)
+
+	result, _, _, err := HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "100.0.0", "", privateKey, "", WithCertStore(store))
+	assert.NoError(t, err)
+	assert.Contains(t, result, "envWorkloadSignature:")
+}
+
+// Testcase to check if HpcrTextEncrypted() rejects an explicitly passed certificate of another platform unless WithSkipCertificateIdentityCheck() is given
+func TestHpcrTextEncryptedCertificateIdentity(t *testing.T) {
+	hpvsCert, ok := cert.DefaultCertStore().Certificate(gen.HyperProtectOsHpvs, "")
+	assert.True(t, ok)
+
+	_, _, _, err := HpcrTextEncrypted(sampleStringData, gen.ConfidentialComputingOsCcco, "", hpvsCert)
+	assert.ErrorContains(t, err, "failed to fetch encryption certificate - encryption certificate does not match platform ccco - subject common name")
+
+	result, _, _, err := HpcrTextEncrypted(sampleStringData, gen.ConfidentialComputingOsCcco, "", hpvsCert, WithSkipCertificateIdentityCheck())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result, hpcrEncryptPrefix))
+
+	result, _, _, err = HpcrTextEncrypted(sampleStringData, gen.HyperProtectOsHpvs, "", hpvsCert)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result, hpcrEncryptPrefix))
+}
+
+// Testcase to check if the encrypt functions refuse to encrypt when the certificate fails strict trust verification
+func TestHpcrEncryptedStrictTrust(t *testing.T) {
+	untrusted := WithStrictTrust(certificate.TrustChain{IbmIntermediateCert: "invalid", DigicertIntermediateCert: "invalid"})
+
+	_, _, _, err := HpcrTextEncrypted(sampleStringData, sampleConfidentialComputingOsVersion, "", "", untrusted)
+	assert.ErrorContains(t, err, "failed to fetch encryption certificate - encryption certificate failed strict trust verification - CA verify failed")
+
+	latest, ok := cert.DefaultCertStore().Certificate(sampleConfidentialComputingOsVersion, "")
+	assert.True(t, ok)
+	_, _, _, err = HpcrJsonEncrypted(sampleStringJson, sampleConfidentialComputingOsVersion, "", latest, untrusted)
+	assert.ErrorContains(t, err, "encryption certificate failed strict trust verification")
+
+	contract, privateKey, _, _, _, err := common("TestHpcrContractSignedEncrypted")
+	if err != nil {
+		t.Errorf("failed to get contract and private key - %v", err)
+	}
+
+	_, _, _, err = HpcrContractSignedEncrypted(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", untrusted)
+	assert.ErrorContains(t, err, "encryption certificate failed strict trust verification")
+
+	// The default trust chain shares one verifier, so intermediates and CRLs are downloaded once per process
+	first, err := newEncryptConfig([]EncryptOption{WithStrictTrust(certificate.TrustChain{})})
+	assert.NoError(t, err)
+	second, err := newEncryptConfig([]EncryptOption{WithStrictTrust(certificate.TrustChain{})})
+	assert.NoError(t, err)
+	assert.Same(t, first.trustVerifier, second.trustVerifier)
+	custom, err := newEncryptConfig([]EncryptOption{untrusted})
+	assert.NoError(t, err)
+	assert.NotSame(t, first.trustVerifier, custom.trustVerifier)
+}
+
+// Testcase to check if the default strict trust mode caches CRLs in a directory
+func TestDefaultTrustChain(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crls")
+	chain := defaultTrustChain(dir)
+	assert.NotNil(t, chain.CRLStore)
+	assert.DirExists(t, dir)
+
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0600))
+	assert.Equal(t, certificate.TrustChain{}, defaultTrustChain(filepath.Join(file, "crls")))
+	assert.Equal(t, certificate.TrustChain{}, defaultTrustChain(""))
+}
+
+// Testcase to check if HpcrTextEncrypted() resolves a certificate version constraint and reports the resolved version
+func TestHpcrTextEncryptedCertVersionConstraint(t *testing.T) {
+	latest, ok := cert.DefaultCertStore().Certificate(sampleConfidentialComputingOsVersion, "")
+	assert.True(t, ok)
+	expired, err := gen.ReadDataFromFile("../samples/encryption-cert/expired.crt")
+	assert.NoError(t, err)
+
+	store := cert.NewCertStore()
+	assert.NoError(t, store.Add(sampleConfidentialComputingOsVersion, "26.4.0", latest))
+	assert.NoError(t, store.Add(sampleConfidentialComputingOsVersion, "26.5.0", latest))
+	assert.NoError(t, store.Add(sampleConfidentialComputingOsVersion, "26.5.1", expired))
+
+	var version string
+	result, _, _, err := HpcrTextEncrypted(sampleStringData, sampleConfidentialComputingOsVersion, "~26.5", "", WithCertStore(store), WithSkipExpiredCertificates(), WithResolvedCertVersion(&version))
+	assert.NoError(t, err)
+	assert.Contains(t, result, ccrtEncryptPrefix)
+	assert.Equal(t, "26.5.0", version)
+
+	_, _, _, err = HpcrJsonEncrypted(sampleStringJson, sampleConfidentialComputingOsVersion, ">=26.5.1", "", WithCertStore(store), WithSkipExpiredCertificates())
+	assert.EqualError(t, err, "failed to generate encrypted JSON - failed to fetch encryption certificate - all certificates matching version >=26.5.1 for platform "+sampleConfidentialComputingOsVersion+" have expired")
+}
+
 // Testcase to check if encrypter() is able to encrypt and generate SHA256 from string
 func TestEncrypter(t *testing.T) {
 	result, err := encrypter(sampleStringJson, sampleConfidentialComputingOsVersion, "", "")
@@ -690,7 +1022,7 @@ func TestEncryptWrapperEmptyContract(t *testing.T) {
 		t.Errorf("failed to read public key - %v", err)
 	}
 
-	_, err = encryptWrapper("", sampleConfidentialComputingOsVersion, "", "", privateKey, "", publicKey)
+	_, err = encryptWrapper("", sampleConfidentialComputingOsVersion, "", privateKey, "", publicKey, SectionBoth)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), emptyParameterErrStatement)
 }
@@ -707,7 +1039,7 @@ func TestEncryptWrapperEmptyPrivateKey(t *testing.T) {
 		t.Errorf("failed to read public key - %v", err)
 	}
 
-	_, err = encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", "", "", "", publicKey)
+	_, err = encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", "", "", publicKey, SectionBoth)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), emptyParameterErrStatement)
 }
@@ -724,7 +1056,7 @@ func TestEncryptWrapperEmptyPublicKey(t *testing.T) {
 		t.Errorf("failed to read private key - %v", err)
 	}
 
-	_, err = encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", "", privateKey, "", "")
+	_, err = encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", privateKey, "", "", SectionBoth)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), emptyParameterErrStatement)
 }
@@ -741,7 +1073,12 @@ func TestEncryptWrapperInvalidYaml(t *testing.T) {
 		t.Errorf("failed to read public key - %v", err)
 	}
 
-	_, err = encryptWrapper("invalid: yaml: content:", sampleConfidentialComputingOsVersion, "", "", privateKey, "", publicKey)
+	encryptCertificate, err := gen.FetchEncryptionCertificate(sampleConfidentialComputingOsVersion, "", "")
+	if err != nil {
+		t.Errorf("failed to fetch encryption certificate - %v", err)
+	}
+
+	_, err = encryptWrapper("invalid: yaml: content:", sampleConfidentialComputingOsVersion, encryptCertificate, privateKey, "", publicKey, SectionBoth)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to unmarshal YAML")
 }
@@ -763,7 +1100,7 @@ func TestEncryptWrapperInvalidCertificate(t *testing.T) {
 		t.Errorf("failed to read public key - %v", err)
 	}
 
-	_, err = encryptWrapper(contract, sampleConfidentialComputingOsVersion, "", "invalid-certificate", privateKey, "", publicKey)
+	_, err = encryptWrapper(contract, sampleConfidentialComputingOsVersion, "invalid-certificate", privateKey, "", publicKey, SectionBoth)
 	assert.Error(t, err)
 }
 
@@ -970,6 +1307,9 @@ func TestHpcrContractTemplateWorkload(t *testing.T) {
 		{"ccrv", readTemplate(sampleWorkloadCcrvTemplatePath)},
 		{"ccco-peerpod", readTemplate(sampleWorkloadCccoPeerpodTemplatePath)},
 		{"ccco-bmtl", readTemplate(sampleWorkloadCccoBmtlTemplatePath)},
+		{"ccrt-compose", readTemplate(sampleWorkloadCcrtComposeTemplatePath)},
+		{"ccrt-play", readTemplate(sampleWorkloadCcrtPlayTemplatePath)},
+		{"CCRV-KMS", readTemplate(sampleWorkloadCcrvTemplatePath)},
 	}
 	for _, tc := range cases {
 		result, err := HpcrContractTemplate("workload", tc.os)
@@ -1000,6 +1340,9 @@ func TestHpcrContractTemplateEnv(t *testing.T) {
 		{"ccrv", readTemplate(sampleEnvTemplatePath)},
 		{"ccco-peerpod", readTemplate(sampleEnvCccoPeerpodTemplatePath)},
 		{"ccco-bmtl", readTemplate(sampleEnvCccoBmtlTemplatePath)},
+		{"ccrt-kms", readTemplate(sampleEnvKmsTemplatePath)},
+		{"hpvs-kms", readTemplate(sampleEnvKmsTemplatePath)},
+		{"ccrv-kms", readTemplate(sampleEnvKmsTemplatePath)},
 	}
 	for _, tc := range cases {
 		result, err := HpcrContractTemplate("env", tc.os)
@@ -1050,34 +1393,95 @@ func TestHpcrContractTemplateInvalidType(t *testing.T) {
 	assert.EqualError(t, err, "unsupported template type: invalid")
 }
 
-// Testcase to check if resolveWorkloadTemplateFile() maps OS values to correct template files.
-func TestResolveWorkloadTemplateFile(t *testing.T) {
-	cases := map[string]string{
-		"ccrv":         workloadCcrvTemplateFilePath,
-		"":             workloadCcrtTemplateFilePath,
-		"ccrt":         workloadCcrtTemplateFilePath,
-		"hpvs":         workloadCcrtTemplateFilePath,
-		"ccco-peerpod": workloadCccoPeerpodTemplateFilePath,
-		"ccco-bmtl":    workloadCccoBmtlTemplateFilePath,
+// Testcase to check if resolveTemplate() maps template names to the correct template files.
+func TestResolveTemplate(t *testing.T) {
+	cases := map[string][2]string{
+		"":             {workloadCcrtTemplateFilePath, envTemplateFilePath},
+		"ccrt":         {workloadCcrtTemplateFilePath, envTemplateFilePath},
+		" CCRT ":       {workloadCcrtTemplateFilePath, envTemplateFilePath},
+		"hpvs":         {workloadCcrtTemplateFilePath, envTemplateFilePath},
+		"ccrv":         {workloadCcrvTemplateFilePath, envTemplateFilePath},
+		"ccco-peerpod": {workloadCccoPeerpodTemplateFilePath, envCccoPeerpodTemplateFilePath},
+		"ccco-bmtl":    {workloadCccoBmtlTemplateFilePath, envCccoBmtlTemplateFilePath},
+	}
+	for name, expected := range cases {
+		templateInfo, err := resolveTemplate(name)
+		assert.NoError(t, err, "name=%s", name)
+		assert.Equal(t, expected[0], templateInfo.WorkloadTemplate, "name=%s", name)
+		assert.Equal(t, expected[1], templateInfo.EnvTemplate, "name=%s", name)
 	}
-	for osVal, expected := range cases {
-		assert.Equal(t, expected, resolveWorkloadTemplateFile(osVal), "os=%s", osVal)
+}
+
+// Testcase to check if HpcrContractTemplate() rejects unknown template names instead of defaulting to ccrt.
+func TestHpcrContractTemplateUnknownName(t *testing.T) {
+	for _, name := range []string{"ccrtt", "hpcr", "ccco"} {
+		_, err := HpcrContractTemplate("workload", name)
+		assert.EqualError(t, err, "unsupported template name: "+name+". Use HpcrListContractTemplates to list the available templates")
 	}
+
+	_, err := resolveTemplate("unknown")
+	assert.EqualError(t, err, "unsupported template name: unknown. Use HpcrListContractTemplates to list the available templates")
+}
+
+// Testcase to check if HpcrContractTemplate() adds the attestation public key placeholder for attestation templates.
+func TestHpcrContractTemplateAttestation(t *testing.T) {
+	for _, name := range []string{"ccrt-attestation", "ccrv-attestation"} {
+		result, err := HpcrContractTemplate("", name)
+		assert.NoError(t, err, "os=%s", name)
+		assert.True(t, strings.HasSuffix(result, attestationPublicKeyTemplate), "os=%s", name)
+
+		var parsed map[string]interface{}
+		assert.NoError(t, yaml.Unmarshal([]byte(result), &parsed), "os=%s", name)
+		assert.Contains(t, parsed, "attestationPublicKey")
+	}
+
+	result, err := HpcrContractTemplate("", "ccrt")
+	assert.NoError(t, err)
+	assert.NotContains(t, result, "attestationPublicKey")
 }
 
-// Testcase to check if resolveEnvTemplateFile() maps OS values to correct template files.
-func TestResolveEnvTemplateFile(t *testing.T) {
-	cases := map[string]string{
-		"":             envTemplateFilePath,
-		"hpvs":         envTemplateFilePath,
-		"ccrt":         envTemplateFilePath,
-		"ccrv":         envTemplateFilePath,
-		"ccco-peerpod": envCccoPeerpodTemplateFilePath,
-		"ccco-bmtl":    envCccoBmtlTemplateFilePath,
+// Testcase to check if every catalog entry resolves to readable workload and env templates.
+func TestTemplateCatalogFilesExist(t *testing.T) {
+	for _, templateInfo := range templateCatalog {
+		_, err := HpcrContractTemplate("", templateInfo.Name)
+		assert.NoError(t, err, "template=%s", templateInfo.Name)
+		assert.NotEmpty(t, templateInfo.Description, "template=%s", templateInfo.Name)
+		assert.NotEmpty(t, templateInfo.Features, "template=%s", templateInfo.Name)
 	}
-	for osVal, expected := range cases {
-		assert.Equal(t, expected, resolveEnvTemplateFile(osVal), "os=%s", osVal)
+}
+
+// Testcase to check if HpcrListContractTemplates() lists all templates in JSON format.
+func TestHpcrListContractTemplatesJson(t *testing.T) {
+	result, err := HpcrListContractTemplates("", "")
+	assert.NoError(t, err)
+
+	var templates []TemplateInfo
+	assert.NoError(t, json.Unmarshal([]byte(result), &templates))
+	assert.Len(t, templates, len(templateCatalog))
+	assert.Equal(t, "ccrt", templates[0].Name)
+}
+
+// Testcase to check if HpcrListContractTemplates() filters templates by platform in YAML format.
+func TestHpcrListContractTemplatesYamlPlatform(t *testing.T) {
+	result, err := HpcrListContractTemplates(" CCCO ", "yaml")
+	assert.NoError(t, err)
+
+	var templates []TemplateInfo
+	assert.NoError(t, yaml.Unmarshal([]byte(result), &templates))
+	assert.Len(t, templates, 2)
+	for _, templateInfo := range templates {
+		assert.Equal(t, "ccco", templateInfo.Platform)
 	}
+	assert.Contains(t, templates[1].Features, TemplateFeatureHostAttestation)
+}
+
+// Testcase to check if HpcrListContractTemplates() rejects invalid platform and format values.
+func TestHpcrListContractTemplatesInvalid(t *testing.T) {
+	_, err := HpcrListContractTemplates("invalid", "json")
+	assert.EqualError(t, err, "invalid platform: invalid. Valid platforms are: ccrt, ccrv, ccco, hpvs")
+
+	_, err = HpcrListContractTemplates("", "xml")
+	assert.EqualError(t, err, "invalid output format: xml. Valid formats are: json, yaml")
 }
 
 // indentTemplateBlockForTest mirrors production indentation behavior for expected YAML output.
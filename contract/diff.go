@@ -0,0 +1,499 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+// Change kinds reported by [HpcrDiffContracts].
+const (
+	DiffAdded    = "added"
+	DiffRemoved  = "removed"
+	DiffModified = "modified"
+)
+
+type (
+	// ContractDiff is the semantic difference between two plaintext contracts.
+	ContractDiff struct {
+		Changes        []ContractChange    `json:"changes" yaml:"changes"`
+		ImagesAdded    []string            `json:"imagesAdded" yaml:"imagesAdded"`
+		ImagesRemoved  []string            `json:"imagesRemoved" yaml:"imagesRemoved"`
+		VolumeChanges  []VolumeChange      `json:"volumeChanges" yaml:"volumeChanges"`
+		ArchiveChanges []ArchiveFileChange `json:"archiveChanges" yaml:"archiveChanges"`
+	}
+
+	// ContractChange describes a single added, removed or modified value.
+	// Path uses dot notation for map keys and [n] for list indices, e.g. "env.logging.syslog.port".
+	ContractChange struct {
+		Path   string      `json:"path" yaml:"path"`
+		Change string      `json:"change" yaml:"change"`
+		Old    interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+		New    interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+	}
+
+	// VolumeChange describes an added, removed or modified volume of the workload or env section.
+	VolumeChange struct {
+		Section string `json:"section" yaml:"section"`
+		Name    string `json:"name" yaml:"name"`
+		Change  string `json:"change" yaml:"change"`
+	}
+
+	// ArchiveFileChange describes a file that differs between the decoded compose or play archives.
+	// OldSha256 and NewSha256 hold the HMAC-SHA256 of the file contents under the redaction key and
+	// are empty without a key. Changes holds the semantic diff of modified YAML files.
+	ArchiveFileChange struct {
+		Archive   string           `json:"archive" yaml:"archive"`
+		File      string           `json:"file" yaml:"file"`
+		Change    string           `json:"change" yaml:"change"`
+		OldSha256 string           `json:"oldSha256,omitempty" yaml:"oldSha256,omitempty"`
+		NewSha256 string           `json:"newSha256,omitempty" yaml:"newSha256,omitempty"`
+		Changes   []ContractChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	}
+
+	// parsedContract holds a contract with nested sections decoded and archives extracted.
+	parsedContract struct {
+		tree     map[string]interface{}
+		archives map[string]map[string]string
+	}
+)
+
+// archivePaths lists the workload fields holding base64 encoded tar.gz archives.
+var archivePaths = [][]string{
+	{"workload", "compose", "archive"},
+	{"workload", "play", "archive"},
+}
+
+// sensitiveArchivePaths lists the fields of the YAML files in workload archives holding secrets: the
+// compose service environment and the container environment and secret data of pod descriptors. Paths
+// start at the document index of the file; "*" matches any single map key or list index.
+var sensitiveArchivePaths = [][]string{
+	{"*", "services", "*", "environment", "*"},
+	{"*", "spec", "containers", "*", "env", "*", "value"},
+	{"*", "spec", "initContainers", "*", "env", "*", "value"},
+	{"*", "data", "*"},
+	{"*", "stringData", "*"},
+}
+
+// IsEmpty reports whether the diff contains no differences.
+func (d *ContractDiff) IsEmpty() bool {
+	return len(d.Changes) == 0 && len(d.ImagesAdded) == 0 && len(d.ImagesRemoved) == 0 &&
+		len(d.VolumeChanges) == 0 && len(d.ArchiveChanges) == 0
+}
+
+// HpcrDiffContracts compares two plaintext contracts and returns a semantic diff.
+//
+// The nested workload and env YAML strings are decoded, and the base64 tar.gz archives of
+// compose and play workloads are extracted and compared file by file; archives that cannot be decoded
+// are compared by their digest placeholder. Container images referenced
+// by the workload (compose services, pod specs and play resources) are collected to report added
+// and removed images, and volume changes are reported by name. Secret values such as registry
+// passwords, volume seeds, API keys and environment variables, and the compose and container
+// environment values of archive files, are replaced by a short SHA256 digest placeholder, so reviewers
+// can tell that a secret changed without seeing it. As for [HpcrRedactContract], pass
+// [WithRedactionKey] to derive placeholders with a keyed HMAC. Archive file digests are only reported
+// with a redaction key, since archive files can hold secrets. Encrypted sections are compared as
+// opaque values.
+//
+// Parameters:
+//   - contractA: Original plaintext contract YAML
+//   - contractB: Updated plaintext contract YAML
+//   - opts: Optional settings, e.g. WithRedactionKey(key)
+//
+// Returns:
+//   - Structured diff of the two contracts
+//   - Error if either contract is empty or cannot be parsed
+func HpcrDiffContracts(contractA, contractB string, opts ...RedactOption) (*ContractDiff, error) {
+	if gen.CheckIfEmpty(contractA, contractB) {
+		return nil, fmt.Errorf(emptyParameterErrStatement)
+	}
+
+	r := newRedactor(opts)
+
+	parsedA, err := r.parseContractForDiff(contractA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse first contract - %v", err)
+	}
+
+	parsedB, err := r.parseContractForDiff(contractB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse second contract - %v", err)
+	}
+
+	diff := &ContractDiff{
+		Changes:        []ContractChange{},
+		VolumeChanges:  diffVolumes(parsedA.tree, parsedB.tree),
+		ArchiveChanges: r.diffArchives(parsedA.archives, parsedB.archives),
+	}
+	r.diffValues(nil, parsedA.tree, parsedB.tree, sensitiveContractPaths, &diff.Changes)
+	diff.ImagesAdded, diff.ImagesRemoved = diffImages(collectImages(parsedA), collectImages(parsedB))
+
+	return diff, nil
+}
+
+// parseContractForDiff decodes a contract and its nested sections, extracting workload archives.
+// Archives that cannot be decoded are replaced by their digest placeholder, as they may hold secrets.
+func (r *redactor) parseContractForDiff(contract string) (*parsedContract, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(contract), &raw); err != nil {
+		return nil, err
+	}
+
+	tree, ok := normalizeYamlValue(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("contract is not a YAML map")
+	}
+
+	for _, section := range []string{SectionWorkload, SectionEnv} {
+		content, ok := tree[section].(string)
+		if !ok || isEncryptedSection(content) {
+			continue
+		}
+
+		var sectionValue interface{}
+		if err := yaml.Unmarshal([]byte(content), &sectionValue); err != nil {
+			return nil, fmt.Errorf("failed to parse %s section - %v", section, err)
+		}
+		tree[section] = normalizeYamlValue(sectionValue)
+	}
+
+	archives := make(map[string]map[string]string)
+	for _, path := range archivePaths {
+		parent, ok := lookupPath(tree, path[:len(path)-1]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		archive, ok := parent[path[len(path)-1]].(string)
+		if !ok {
+			continue
+		}
+
+		files, err := gen.ExtractTgzBase64(archive)
+		if err != nil {
+			parent[path[len(path)-1]] = r.placeholder(archive)
+			continue
+		}
+		archives[strings.Join(path, ".")] = files
+		delete(parent, path[len(path)-1])
+	}
+
+	return &parsedContract{tree: tree, archives: archives}, nil
+}
+
+// isEncryptedSection reports whether a section value carries an encrypted token prefix instead of plaintext YAML.
+// Unlike [gen.IsEncryptedToken] it does not validate the token, so damaged tokens are never parsed as YAML.
+func isEncryptedSection(content string) bool {
+	prefix, _, found := strings.Cut(strings.TrimSpace(content), ".")
+	return found && (prefix == gen.EncryptedTokenPrefixHyperProtectBasic || prefix == gen.EncryptedTokenPrefixContractBasic ||
+		prefix == gen.EncryptedTokenPrefixSealed)
+}
+
+// normalizeYamlValue converts YAML decoded maps to map[string]interface{} recursively.
+func normalizeYamlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYamlValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprintf("%v", key)] = normalizeYamlValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYamlValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// lookupPath returns the value at the given map key path, or nil if it does not exist.
+func lookupPath(tree map[string]interface{}, path []string) interface{} {
+	var current interface{} = tree
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// diffValues appends the differences between a and b below path to changes, redacting values at the
+// sensitive paths.
+func (r *redactor) diffValues(path []string, a, b interface{}, sensitive [][]string, changes *[]ContractChange) {
+	mapA, isMapA := a.(map[string]interface{})
+	mapB, isMapB := b.(map[string]interface{})
+	if isMapA && isMapB {
+		for _, key := range unionKeys(mapA, mapB) {
+			valueA, okA := mapA[key]
+			valueB, okB := mapB[key]
+			childPath := appendPath(path, key)
+			switch {
+			case !okA:
+				*changes = append(*changes, ContractChange{Path: formatPath(childPath), Change: DiffAdded, New: r.redactValue(childPath, valueB, sensitive)})
+			case !okB:
+				*changes = append(*changes, ContractChange{Path: formatPath(childPath), Change: DiffRemoved, Old: r.redactValue(childPath, valueA, sensitive)})
+			default:
+				r.diffValues(childPath, valueA, valueB, sensitive, changes)
+			}
+		}
+		return
+	}
+
+	listA, isListA := a.([]interface{})
+	listB, isListB := b.([]interface{})
+	if isListA && isListB {
+		for i := 0; i < len(listA) || i < len(listB); i++ {
+			childPath := appendPath(path, fmt.Sprintf("[%d]", i))
+			switch {
+			case i >= len(listA):
+				*changes = append(*changes, ContractChange{Path: formatPath(childPath), Change: DiffAdded, New: r.redactValue(childPath, listB[i], sensitive)})
+			case i >= len(listB):
+				*changes = append(*changes, ContractChange{Path: formatPath(childPath), Change: DiffRemoved, Old: r.redactValue(childPath, listA[i], sensitive)})
+			default:
+				r.diffValues(childPath, listA[i], listB[i], sensitive, changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, ContractChange{
+			Path:   formatPath(path),
+			Change: DiffModified,
+			Old:    r.redactValue(path, a, sensitive),
+			New:    r.redactValue(path, b, sensitive),
+		})
+	}
+}
+
+// diffVolumes reports volumes added, removed or modified in the workload and env sections.
+func diffVolumes(treeA, treeB map[string]interface{}) []VolumeChange {
+	changes := []VolumeChange{}
+	for _, section := range []string{SectionWorkload, SectionEnv} {
+		volumesA, _ := lookupPath(treeA, []string{section, "volumes"}).(map[string]interface{})
+		volumesB, _ := lookupPath(treeB, []string{section, "volumes"}).(map[string]interface{})
+
+		for _, name := range unionKeys(volumesA, volumesB) {
+			volumeA, okA := volumesA[name]
+			volumeB, okB := volumesB[name]
+			switch {
+			case !okA:
+				changes = append(changes, VolumeChange{Section: section, Name: name, Change: DiffAdded})
+			case !okB:
+				changes = append(changes, VolumeChange{Section: section, Name: name, Change: DiffRemoved})
+			case !reflect.DeepEqual(volumeA, volumeB):
+				changes = append(changes, VolumeChange{Section: section, Name: name, Change: DiffModified})
+			}
+		}
+	}
+	return changes
+}
+
+// diffArchives compares the extracted workload archives file by file.
+func (r *redactor) diffArchives(archivesA, archivesB map[string]map[string]string) []ArchiveFileChange {
+	changes := []ArchiveFileChange{}
+	for _, path := range archivePaths {
+		archive := strings.Join(path, ".")
+		filesA, filesB := archivesA[archive], archivesB[archive]
+
+		for _, file := range unionKeys(filesA, filesB) {
+			contentA, okA := filesA[file]
+			contentB, okB := filesB[file]
+			switch {
+			case !okA:
+				changes = append(changes, ArchiveFileChange{Archive: archive, File: file, Change: DiffAdded, NewSha256: r.digest(contentB)})
+			case !okB:
+				changes = append(changes, ArchiveFileChange{Archive: archive, File: file, Change: DiffRemoved, OldSha256: r.digest(contentA)})
+			case contentA != contentB:
+				changes = append(changes, ArchiveFileChange{
+					Archive:   archive,
+					File:      file,
+					Change:    DiffModified,
+					OldSha256: r.digest(contentA),
+					NewSha256: r.digest(contentB),
+					Changes:   r.diffYamlFiles(file, contentA, contentB),
+				})
+			}
+		}
+	}
+	return changes
+}
+
+// diffYamlFiles returns the semantic diff of two versions of a YAML archive file, or nil for other files.
+func (r *redactor) diffYamlFiles(file, contentA, contentB string) []ContractChange {
+	if !strings.HasSuffix(file, ".yaml") && !strings.HasSuffix(file, ".yml") {
+		return nil
+	}
+
+	docsA, errA := decodeYamlDocuments(contentA)
+	docsB, errB := decodeYamlDocuments(contentB)
+	if errA != nil || errB != nil {
+		return nil
+	}
+
+	changes := []ContractChange{}
+	r.diffValues(nil, docsA, docsB, sensitiveArchivePaths, &changes)
+	return changes
+}
+
+// decodeYamlDocuments decodes every document of a multi-document YAML file.
+func decodeYamlDocuments(content string) ([]interface{}, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	docs := []interface{}{}
+	for {
+		var doc interface{}
+		err := decoder.Decode(&doc)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, err
+		}
+		docs = append(docs, normalizeYamlValue(doc))
+	}
+}
+
+// collectImages returns the container images referenced by the workload section and archive files.
+func collectImages(contract *parsedContract) map[string]bool {
+	images := make(map[string]bool)
+	collectImageValues(contract.tree[SectionWorkload], images)
+
+	for _, files := range contract.archives {
+		for file, content := range files {
+			if !strings.HasSuffix(file, ".yaml") && !strings.HasSuffix(file, ".yml") {
+				continue
+			}
+			docs, err := decodeYamlDocuments(content)
+			if err != nil {
+				continue
+			}
+			collectImageValues(docs, images)
+		}
+	}
+	return images
+}
+
+// collectImageValues walks a decoded YAML value and records every string stored under an "image" key.
+func collectImageValues(value interface{}, images map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if image, ok := item.(string); ok && key == "image" {
+				images[image] = true
+				continue
+			}
+			collectImageValues(item, images)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectImageValues(item, images)
+		}
+	}
+}
+
+// diffImages returns the sorted images only present in b (added) and only present in a (removed).
+func diffImages(imagesA, imagesB map[string]bool) ([]string, []string) {
+	added, removed := []string{}, []string{}
+	for image := range imagesB {
+		if !imagesA[image] {
+			added = append(added, image)
+		}
+	}
+	for image := range imagesA {
+		if !imagesB[image] {
+			removed = append(removed, image)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// redactValue replaces the values at the sensitive paths, including those nested in value, by a digest placeholder.
+func (r *redactor) redactValue(path []string, value interface{}, sensitive [][]string) interface{} {
+	if matchesPath(sensitive, path) {
+		return r.placeholder(value)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = r.redactValue(appendPath(path, key), item, sensitive)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = r.redactValue(appendPath(path, fmt.Sprintf("[%d]", i)), item, sensitive)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// unionKeys returns the sorted union of the keys of two maps.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendPath returns a copy of path with segment appended.
+func appendPath(path []string, segment string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, segment)
+}
+
+// formatPath renders a path using dot notation for keys and [n] for list indices.
+func formatPath(path []string) string {
+	var builder strings.Builder
+	for i, segment := range path {
+		if i > 0 && !strings.HasPrefix(segment, "[") {
+			builder.WriteString(".")
+		}
+		builder.WriteString(segment)
+	}
+	return builder.String()
+}
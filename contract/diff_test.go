@@ -0,0 +1,287 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	sampleDiffComposeV1 = `services:
+  app:
+    image: icr.io/sample/app:1.0
+  db:
+    image: icr.io/sample/db:2.0
+`
+	sampleDiffComposeV2 = `services:
+  app:
+    image: icr.io/sample/app:1.1
+  db:
+    image: icr.io/sample/db:2.0
+`
+	sampleDiffContractTemplate = `workload: |
+  type: workload
+  auths:
+    icr.io:
+      username: iamapikey
+      password: %PASSWORD%
+  compose:
+    archive: %ARCHIVE%
+  volumes:
+    data:
+      seed: workload-seed
+env: |
+  type: env
+  logging:
+    logRouter:
+      hostname: logs.example.com
+      iamApiKey: %APIKEY%
+  volumes:
+%VOLUMES%
+`
+)
+
+// buildDiffContract creates a plaintext contract with a compose archive built from the given files.
+func buildDiffContract(t *testing.T, files map[string]string, password, apiKey, volumes string) string {
+	dir := t.TempDir()
+	composeDir := filepath.Join(dir, "compose")
+	if err := os.Mkdir(composeDir, 0755); err != nil {
+		t.Fatalf("failed to create compose folder - %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(composeDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s - %v", name, err)
+		}
+	}
+
+	filesFoldersList, err := gen.ListFoldersAndFiles(composeDir)
+	if err != nil {
+		t.Fatalf("failed to list files and folders - %v", err)
+	}
+	archive, err := gen.GenerateTgzBase64(filesFoldersList)
+	if err != nil {
+		t.Fatalf("failed to generate TGZ base64 - %v", err)
+	}
+
+	return strings.NewReplacer(
+		"%PASSWORD%", password,
+		"%ARCHIVE%", archive,
+		"%APIKEY%", apiKey,
+		"%VOLUMES%", volumes,
+	).Replace(sampleDiffContractTemplate)
+}
+
+// Testcase to check if HpcrDiffContracts() reports no differences for identical contracts
+func TestHpcrDiffContractsIdentical(t *testing.T) {
+	contract := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV1}, "secret", "apikey", "    vol1:\n      seed: env-seed")
+
+	diff, err := HpcrDiffContracts(contract, contract)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+
+	assert.True(t, diff.IsEmpty())
+}
+
+// Testcase to check if HpcrDiffContracts() reports value, image, volume and archive changes
+func TestHpcrDiffContracts(t *testing.T) {
+	contractA := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV1}, "old-password", "old-apikey",
+		"    vol1:\n      seed: env-seed\n    vol2:\n      seed: other-seed")
+	contractB := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV2, ".env": "PORT=8080"}, "new-password", "old-apikey",
+		"    vol1:\n      seed: rotated-seed\n    vol3:\n      seed: new-seed")
+
+	diff, err := HpcrDiffContracts(contractA, contractB)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+
+	assert.Equal(t, []string{"icr.io/sample/app:1.1"}, diff.ImagesAdded)
+	assert.Equal(t, []string{"icr.io/sample/app:1.0"}, diff.ImagesRemoved)
+
+	assert.Equal(t, []VolumeChange{
+		{Section: "env", Name: "vol1", Change: DiffModified},
+		{Section: "env", Name: "vol2", Change: DiffRemoved},
+		{Section: "env", Name: "vol3", Change: DiffAdded},
+	}, diff.VolumeChanges)
+
+	assert.Len(t, diff.ArchiveChanges, 2)
+	assert.Equal(t, ".env", diff.ArchiveChanges[0].File)
+	assert.Equal(t, DiffAdded, diff.ArchiveChanges[0].Change)
+	assert.Equal(t, "docker-compose.yaml", diff.ArchiveChanges[1].File)
+	assert.Equal(t, DiffModified, diff.ArchiveChanges[1].Change)
+	assert.Equal(t, []ContractChange{
+		{Path: "[0].services.app.image", Change: DiffModified, Old: "icr.io/sample/app:1.0", New: "icr.io/sample/app:1.1"},
+	}, diff.ArchiveChanges[1].Changes)
+
+	paths := map[string]ContractChange{}
+	for _, change := range diff.Changes {
+		paths[change.Path] = change
+	}
+	assert.Contains(t, paths, "workload.auths.icr.io.password")
+	assert.Contains(t, paths, "env.volumes.vol1.seed")
+	assert.Contains(t, paths, "env.volumes.vol2")
+	assert.Contains(t, paths, "env.volumes.vol3")
+	assert.NotContains(t, paths, "env.logging.logRouter.iamApiKey")
+	assert.NotContains(t, paths, "workload.compose.archive")
+}
+
+// Testcase to check if HpcrDiffContracts() only reports keyed digests of archive files
+func TestHpcrDiffContractsArchiveDigests(t *testing.T) {
+	contractA := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV1}, "secret", "apikey", "    vol1:\n      seed: env-seed")
+	contractB := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV1, ".env": "DB_PASSWORD=guessable"}, "secret", "apikey", "    vol1:\n      seed: env-seed")
+
+	diff, err := HpcrDiffContracts(contractA, contractB)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+	assert.Len(t, diff.ArchiveChanges, 1)
+	assert.Empty(t, diff.ArchiveChanges[0].NewSha256)
+
+	keyed, err := HpcrDiffContracts(contractA, contractB, WithRedactionKey([]byte("diff-key")))
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+	assert.Len(t, keyed.ArchiveChanges, 1)
+	assert.Len(t, keyed.ArchiveChanges[0].NewSha256, 64)
+	assert.NotEqual(t, gen.GenerateSha256("DB_PASSWORD=guessable"), keyed.ArchiveChanges[0].NewSha256)
+}
+
+// Testcase to check if HpcrDiffContracts() redacts secret values in the diff output
+func TestHpcrDiffContractsRedactsSecrets(t *testing.T) {
+	contractA := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV1}, "old-password", "old-apikey", "    vol1:\n      seed: env-seed")
+	contractB := buildDiffContract(t, map[string]string{"docker-compose.yaml": sampleDiffComposeV1}, "new-password", "new-apikey",
+		"    vol1:\n      seed: env-seed\n    vol2:\n      seed: new-seed\n      kms:\n        - apiKey: kms-apikey\n          crn: crn:v1:sample\n          type: public")
+
+	diff, err := HpcrDiffContracts(contractA, contractB)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+
+	for _, secret := range []string{"old-password", "new-password", "old-apikey", "new-apikey", "new-seed", "kms-apikey"} {
+		for _, change := range diff.Changes {
+			assert.NotContains(t, stringifyChange(change), secret, "path=%s", change.Path)
+		}
+	}
+
+	for _, change := range diff.Changes {
+		if change.Path == "workload.auths.icr.io.password" {
+			assert.True(t, strings.HasPrefix(change.Old.(string), redactedValuePrefix))
+			assert.NotEqual(t, change.Old, change.New)
+		}
+		if change.Path == "env.volumes.vol2" {
+			assert.Contains(t, stringifyChange(change), "crn:v1:sample")
+		}
+	}
+}
+
+// Testcase to check if HpcrDiffContracts() redacts secret values of compose files in workload archives
+func TestHpcrDiffContractsRedactsArchiveSecrets(t *testing.T) {
+	composeA := "services:\n  app:\n    image: icr.io/sample/app:1.0\n    environment:\n      DB_PASSWORD: old-db-password\n      LOG_LEVEL: info\n"
+	composeB := "services:\n  app:\n    image: icr.io/sample/app:1.0\n    environment:\n      DB_PASSWORD: new-db-password\n      API_TOKEN: new-api-token\n      LOG_LEVEL: info\n" +
+		"  worker:\n    image: icr.io/sample/worker:1.0\n    environment:\n      - WORKER_TOKEN=worker-token\n"
+	contractA := buildDiffContract(t, map[string]string{"docker-compose.yaml": composeA}, "secret", "apikey", "    vol1:\n      seed: env-seed")
+	contractB := buildDiffContract(t, map[string]string{"docker-compose.yaml": composeB}, "secret", "apikey", "    vol1:\n      seed: env-seed")
+
+	diff, err := HpcrDiffContracts(contractA, contractB)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+
+	assert.Len(t, diff.ArchiveChanges, 1)
+	paths := map[string]ContractChange{}
+	for _, change := range diff.ArchiveChanges[0].Changes {
+		paths[change.Path] = change
+		for _, secret := range []string{"old-db-password", "new-db-password", "new-api-token", "worker-token"} {
+			assert.NotContains(t, stringifyChange(change), secret, "path=%s", change.Path)
+		}
+	}
+
+	assert.Contains(t, paths, "[0].services.app.environment.DB_PASSWORD")
+	assert.Contains(t, paths, "[0].services.app.environment.API_TOKEN")
+	assert.Contains(t, paths, "[0].services.worker")
+	assert.Contains(t, stringifyChange(paths["[0].services.worker"]), "icr.io/sample/worker:1.0")
+	password := paths["[0].services.app.environment.DB_PASSWORD"]
+	assert.True(t, strings.HasPrefix(password.Old.(string), redactedValuePrefix))
+	assert.NotEqual(t, password.Old, password.New)
+
+	keyed, err := HpcrDiffContracts(contractA, contractB, WithRedactionKey([]byte("diff-key")))
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+	for _, change := range keyed.ArchiveChanges[0].Changes {
+		if change.Path == "[0].services.app.environment.DB_PASSWORD" {
+			assert.NotEqual(t, password.Old, change.Old)
+			assert.True(t, strings.HasPrefix(change.Old.(string), redactedValuePrefix))
+		}
+	}
+}
+
+// Testcase to check if HpcrDiffContracts() redacts workload archives that cannot be decoded
+func TestHpcrDiffContractsRedactsUndecodableArchive(t *testing.T) {
+	contractA := "workload: |\n  type: workload\n  compose:\n    archive: not-an-archive-old-secret\n"
+	contractB := "workload: |\n  type: workload\n  compose:\n    archive: not-an-archive-new-secret\n"
+
+	diff, err := HpcrDiffContracts(contractA, contractB)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+
+	assert.Equal(t, []ContractChange{
+		{Path: "workload.compose.archive", Change: DiffModified,
+			Old: redactedPlaceholder("not-an-archive-old-secret"), New: redactedPlaceholder("not-an-archive-new-secret")},
+	}, diff.Changes)
+	assert.Empty(t, diff.ArchiveChanges)
+}
+
+// Testcase to check if HpcrDiffContracts() compares encrypted sections as opaque values
+func TestHpcrDiffContractsEncryptedSection(t *testing.T) {
+	contractA := "workload: hyper-protect-basic.aaa.bbb\nenv: |\n  type: env\n"
+	contractB := "workload: hyper-protect-basic.ccc.ddd\nenv: |\n  type: env\n"
+
+	diff, err := HpcrDiffContracts(contractA, contractB)
+	if err != nil {
+		t.Errorf("failed to diff contracts - %v", err)
+	}
+
+	assert.Equal(t, []ContractChange{
+		{Path: "workload", Change: DiffModified, Old: "hyper-protect-basic.aaa.bbb", New: "hyper-protect-basic.ccc.ddd"},
+	}, diff.Changes)
+}
+
+// Testcase to check if HpcrDiffContracts() handles empty and invalid contracts
+func TestHpcrDiffContractsInvalid(t *testing.T) {
+	_, err := HpcrDiffContracts("", "env: test")
+	assert.EqualError(t, err, emptyParameterErrStatement)
+
+	_, err = HpcrDiffContracts("- a\n- b", "env: test")
+	assert.EqualError(t, err, "failed to parse first contract - contract is not a YAML map")
+
+	_, err = HpcrDiffContracts("env: test", "workload: |\n  key: [unclosed")
+	assert.ErrorContains(t, err, "failed to parse second contract - failed to parse workload section")
+}
+
+// stringifyChange renders a change including nested values for content assertions.
+func stringifyChange(change ContractChange) string {
+	out, _ := gen.MapToYaml(map[string]interface{}{"old": change.Old, "new": change.New})
+	return out
+}
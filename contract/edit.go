@@ -0,0 +1,118 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+// HpcrContractSetPath sets a nested field of a plaintext contract while preserving comments, key order
+// and scalar styles.
+//
+// When the path starts with "workload" or "env" and the section is written as a nested YAML string
+// (workload: |), the remaining path is applied inside that string and the section is written back as
+// a literal block. A missing section is created the same way. List elements are addressed by index
+// ("0" or "[0]").
+//
+// Parameters:
+//   - contract: Plaintext contract YAML
+//   - path: Keys from the contract root to the field, e.g. []string{"env", "logging", "syslog", "port"}
+//   - value: Value to set; strings, numbers, booleans, maps and slices are encoded as YAML
+//
+// Returns:
+//   - Modified contract YAML
+//   - Error if the contract is empty or invalid, the path is empty or the targeted section is encrypted
+func HpcrContractSetPath(contract string, path []string, value interface{}) (string, error) {
+	return editContractPath(contract, path, func(document string, path []string) (string, error) {
+		return gen.SetPath(document, path, value)
+	})
+}
+
+// HpcrContractDeletePath removes a nested field from a plaintext contract while preserving comments,
+// key order and scalar styles. Paths into the nested workload and env YAML strings are handled as in
+// HpcrContractSetPath. Deleting a field that does not exist returns the contract unchanged.
+//
+// Parameters:
+//   - contract: Plaintext contract YAML
+//   - path: Keys from the contract root to the field to remove
+//
+// Returns:
+//   - Modified contract YAML
+//   - Error if the contract is empty or invalid, the path is empty or the targeted section is encrypted
+func HpcrContractDeletePath(contract string, path []string) (string, error) {
+	return editContractPath(contract, path, func(document string, path []string) (string, error) {
+		return gen.DeletePath(document, path)
+	})
+}
+
+// editContractPath applies edit to the contract, descending into the nested workload or env YAML string when path targets one.
+func editContractPath(contract string, path []string, edit func(document string, path []string) (string, error)) (string, error) {
+	if gen.CheckIfEmpty(contract) {
+		return "", fmt.Errorf(emptyParameterErrStatement)
+	}
+	if len(path) == 0 {
+		return "", fmt.Errorf("path is empty")
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(contract), &document); err != nil {
+		return "", fmt.Errorf("failed to parse contract - %v", err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("failed to parse contract - contract is not a YAML map")
+	}
+
+	section := path[0]
+	if len(path) == 1 || (section != SectionWorkload && section != SectionEnv) {
+		return edit(contract, path)
+	}
+
+	root := document.Content[0]
+	var sectionNode *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == section {
+			sectionNode = root.Content[i+1]
+			break
+		}
+	}
+
+	if sectionNode == nil {
+		sectionNode = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: section}, sectionNode)
+	}
+
+	if sectionNode.Kind != yaml.ScalarNode {
+		return edit(contract, path)
+	}
+
+	if isEncryptedSection(sectionNode.Value) {
+		return "", fmt.Errorf("%s section is encrypted and cannot be edited", section)
+	}
+
+	editedSection, err := edit(sectionNode.Value, path[1:])
+	if err != nil {
+		return "", fmt.Errorf("failed to edit %s section - %v", section, err)
+	}
+	sectionNode.Value = editedSection
+	sectionNode.Tag = "!!str"
+	sectionNode.Style = yaml.LiteralStyle
+
+	return gen.EncodeYamlDocument(&document, contract)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleEditContract = `# reviewed contract
+workload: |
+  type: workload
+  # registry credentials
+  auths:
+    icr.io:
+      username: iamapikey
+      password: secret
+env: |
+  type: env
+  logging:
+    syslog:
+      hostname: logs.example.com
+      port: 514
+`
+
+// Testcase to check if HpcrContractSetPath() edits nested section fields and preserves comments
+func TestHpcrContractSetPath(t *testing.T) {
+	result, err := HpcrContractSetPath(sampleEditContract, []string{"env", "logging", "syslog", "port"}, 6514)
+	if err != nil {
+		t.Errorf("failed to set path - %v", err)
+	}
+
+	assert.Equal(t, strings.Replace(sampleEditContract, "port: 514", "port: 6514", 1), result)
+
+	result, err = HpcrContractSetPath(sampleEditContract, []string{"workload", "auths", "icr.io", "password"}, "rotated")
+	if err != nil {
+		t.Errorf("failed to set path - %v", err)
+	}
+
+	assert.Contains(t, result, "# reviewed contract\n")
+	assert.Contains(t, result, "  # registry credentials\n")
+	assert.Contains(t, result, "      password: rotated\n")
+
+	result, err = HpcrContractSetPath("workload: |\n  type: workload\n", []string{"env", "type"}, "env")
+	if err != nil {
+		t.Errorf("failed to set path - %v", err)
+	}
+
+	assert.Equal(t, "workload: |\n  type: workload\nenv: |\n  type: env\n", result)
+
+	result, err = HpcrContractSetPath(sampleEditContract, []string{"attestationPublicKey"}, "public-key")
+	if err != nil {
+		t.Errorf("failed to set path - %v", err)
+	}
+
+	assert.Equal(t, sampleEditContract+"attestationPublicKey: public-key\n", result)
+}
+
+// Testcase to check if HpcrContractSetPath() keeps the indentation of a contract indented with four spaces
+func TestHpcrContractSetPathFourSpaceIndent(t *testing.T) {
+	contract := `env: |
+    type: env
+    logging:
+        logRouter:
+            hostname: logs.example.com
+            port: 443
+attestationPublicKey:
+    key: public-key
+`
+
+	result, err := HpcrContractSetPath(contract, []string{"env", "logging", "logRouter", "port"}, 8080)
+	if err != nil {
+		t.Errorf("failed to set path - %v", err)
+	}
+
+	assert.Equal(t, strings.Replace(contract, "port: 443", "port: 8080", 1), result)
+}
+
+// Testcase to check if HpcrContractDeletePath() removes nested section fields
+func TestHpcrContractDeletePath(t *testing.T) {
+	result, err := HpcrContractDeletePath(sampleEditContract, []string{"env", "logging", "syslog", "port"})
+	if err != nil {
+		t.Errorf("failed to delete path - %v", err)
+	}
+
+	assert.Equal(t, strings.Replace(sampleEditContract, "      port: 514\n", "", 1), result)
+
+	result, err = HpcrContractDeletePath(sampleEditContract, []string{"workload"})
+	if err != nil {
+		t.Errorf("failed to delete path - %v", err)
+	}
+
+	assert.NotContains(t, result, "workload")
+	assert.Contains(t, result, "env: |\n")
+}
+
+// Testcase to check if HpcrContractSetPath() and HpcrContractDeletePath() handle invalid input
+func TestHpcrContractSetPathInvalid(t *testing.T) {
+	_, err := HpcrContractSetPath("", []string{"env"}, "value")
+	assert.EqualError(t, err, emptyParameterErrStatement)
+
+	_, err = HpcrContractSetPath(sampleEditContract, nil, "value")
+	assert.EqualError(t, err, "path is empty")
+
+	_, err = HpcrContractSetPath("- a\n- b", []string{"env"}, "value")
+	assert.EqualError(t, err, "failed to parse contract - contract is not a YAML map")
+
+	_, err = HpcrContractSetPath("workload: hyper-protect-basic.aaa.bbb\n", []string{"workload", "type"}, "workload")
+	assert.EqualError(t, err, "workload section is encrypted and cannot be edited")
+
+	_, err = HpcrContractDeletePath("env: |\n  type: env\n", []string{"env", "type", "nested"})
+	assert.EqualError(t, err, `failed to edit env section - cannot delete "nested" - parent value is not a map or list`)
+}
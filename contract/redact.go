@@ -0,0 +1,336 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+const (
+	// Prefix of the placeholder that replaces sensitive values. Placeholders are alphanumeric
+	// and 24 characters long so they satisfy the schema patterns of every sensitive field.
+	redactedValuePrefix = "REDACTED"
+	// Number of hex characters of the SHA256 digest kept in redacted placeholders.
+	redactedDigestLength = 16
+)
+
+// sensitiveContractPaths lists the contract fields holding secrets, derived from the contract schemas.
+// "*" matches any single map key or list index.
+var sensitiveContractPaths = [][]string{
+	{"workload", "auths", "*", "password"},
+	{"workload", "volumes", "*", "seed"},
+	{"workload", "volumes", "*", "previousSeed"},
+	{"workload", "confidential-containers", "secret", "decryptionKey"},
+	{"env", "auths", "*", "password"},
+	{"env", "logging", "logRouter", "iamApiKey"},
+	{"env", "logging", "syslog", "key"},
+	{"env", "volumes", "*", "seed"},
+	{"env", "volumes", "*", "previousSeed"},
+	{"env", "volumes", "*", "apiKey"},
+	{"env", "volumes", "*", "kms", "*", "apiKey"},
+	{"env", "volumes", "*", "grep11", "clientKey"},
+	{"env", "confidential-containers", "secret", "decryptionKey"},
+	{"workload", "env", "*"},
+	{"env", "env", "*"},
+}
+
+// RedactOption configures how secret values are masked.
+type RedactOption func(*redactor)
+
+// WithRedactionKey derives placeholders with HMAC-SHA256 under the given key instead of a plain
+// SHA256 digest. Without a key, anyone holding a redacted contract can confirm guesses of a secret
+// by hashing them, which recovers low-entropy secrets such as short passwords; with a key this
+// requires the key. Use the same key to keep placeholders comparable across contracts.
+func WithRedactionKey(key []byte) RedactOption {
+	return func(r *redactor) {
+		r.key = key
+	}
+}
+
+// redactor masks sensitive values with digest placeholders.
+type redactor struct {
+	key []byte
+	// set when a value was replaced since it was last reset
+	changed bool
+}
+
+// newRedactor applies the redact options.
+func newRedactor(opts []RedactOption) *redactor {
+	r := &redactor{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// HpcrRedactContract returns a copy of a plaintext contract with all secret values masked,
+// suitable for logs and audit trails.
+//
+// The contract is walked using the sensitive fields known from the contract schemas, including
+// fields inside the nested workload and env YAML strings: registry passwords, volume seeds,
+// KMS and IAM API keys, the syslog client key, the GREP11 client key, the confidential-containers
+// decryption key and the values of the workload and env environment variables. The compose and play
+// archives of the workload are unpacked and the service environment, container env values and
+// secret data of their YAML files are masked as well. Each secret is replaced by "REDACTED" followed
+// by the first 16 hex characters of its SHA256 digest, or of its HMAC-SHA256 with [WithRedactionKey],
+// so identical secrets produce identical placeholders and changes remain visible when redacted
+// contracts are diffed. Placeholders satisfy the schema patterns of the fields they replace, so the
+// redacted contract is still schema-valid. Comments and key order are preserved and encrypted
+// sections are left unchanged.
+//
+// Without a redaction key the placeholder is an unsalted digest: low-entropy secrets can be
+// recovered from it by hashing guesses. Pass a key when redacted contracts leave trusted storage.
+//
+// Parameters:
+//   - contract: Plaintext contract YAML
+//   - opts: Optional settings, e.g. WithRedactionKey(key)
+//
+// Returns:
+//   - Redacted contract YAML
+//   - Error if the contract is empty or cannot be parsed
+func HpcrRedactContract(contract string, opts ...RedactOption) (string, error) {
+	if gen.CheckIfEmpty(contract) {
+		return "", fmt.Errorf(emptyParameterErrStatement)
+	}
+
+	r := newRedactor(opts)
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(contract), &document); err != nil {
+		return "", fmt.Errorf("failed to parse contract - %v", err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return "", fmt.Errorf("failed to parse contract - contract is not a YAML map")
+	}
+
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		section, value := root.Content[i].Value, root.Content[i+1]
+		if section != SectionWorkload && section != SectionEnv {
+			continue
+		}
+
+		if value.Kind != yaml.ScalarNode {
+			r.redactNode(value, []string{section}, sensitiveContractPaths)
+			continue
+		}
+
+		if isEncryptedSection(value.Value) {
+			continue
+		}
+
+		redactedSection, err := r.redactYamlString(value.Value, []string{section})
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s section - %v", section, err)
+		}
+		value.Value = redactedSection
+		value.Style = yaml.LiteralStyle
+	}
+
+	return encodeYamlNode(&document)
+}
+
+// redactYamlString redacts the sensitive fields of a nested YAML document located at path.
+func (r *redactor) redactYamlString(content string, path []string) (string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return "", err
+	}
+
+	if len(document.Content) == 0 {
+		return content, nil
+	}
+
+	r.redactNode(&document, path, sensitiveContractPaths)
+	return encodeYamlNode(&document)
+}
+
+// redactNode replaces scalar values at the sensitive paths below node, where path is the location of
+// node. Workload archives found below node are redacted with the archive file paths.
+func (r *redactor) redactNode(node *yaml.Node, path []string, sensitive [][]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			r.redactNode(child, path, sensitive)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childPath := appendPath(path, node.Content[i].Value)
+			value := node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && matchesPath(sensitive, childPath) {
+				r.redactScalar(value)
+				continue
+			}
+			if value.Kind == yaml.ScalarNode && matchesPath(archivePaths, childPath) {
+				value.Value = r.redactArchive(value.Value)
+				continue
+			}
+			r.redactNode(value, childPath, sensitive)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := appendPath(path, fmt.Sprintf("[%d]", i))
+			if child.Kind == yaml.ScalarNode && matchesPath(sensitive, childPath) {
+				r.redactScalar(child)
+				continue
+			}
+			r.redactNode(child, childPath, sensitive)
+		}
+	}
+}
+
+// redactScalar replaces the value of a scalar node by its placeholder.
+func (r *redactor) redactScalar(node *yaml.Node) {
+	node.Value = r.placeholder(node.Value)
+	node.Tag = "!!str"
+	node.Style = 0
+}
+
+// redactArchive masks the sensitive values of the YAML files of a base64 tar.gz workload archive.
+// The archive is returned unchanged if it holds no sensitive value or cannot be read.
+func (r *redactor) redactArchive(archive string) string {
+	changed := false
+	redacted, err := gen.RewriteTgzBase64(archive, func(name, content string) (string, error) {
+		if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+			return content, nil
+		}
+
+		redactedContent, err := r.redactYamlFile(content)
+		if err != nil {
+			// Files that are not valid YAML are kept as they are.
+			return content, nil
+		}
+		if redactedContent != content {
+			changed = true
+		}
+		return redactedContent, nil
+	})
+	if err != nil || !changed {
+		return archive
+	}
+	return redacted
+}
+
+// redactYamlFile redacts the sensitive fields of every document of an archive YAML file. Files
+// without sensitive values are returned unchanged.
+func (r *redactor) redactYamlFile(content string) (string, error) {
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	changed := false
+	for i := 0; ; i++ {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		r.changed = false
+		r.redactNode(&document, []string{fmt.Sprintf("[%d]", i)}, sensitiveArchivePaths)
+		changed = changed || r.changed
+
+		if err := encoder.Encode(&document); err != nil {
+			return "", fmt.Errorf("failed to encode YAML - %v", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode YAML - %v", err)
+	}
+
+	if !changed {
+		return content, nil
+	}
+	return buf.String(), nil
+}
+
+// encodeYamlNode renders a YAML node with the two space indentation used by contracts.
+func encodeYamlNode(node *yaml.Node) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", fmt.Errorf("failed to encode YAML - %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode YAML - %v", err)
+	}
+	return buf.String(), nil
+}
+
+// placeholder returns the placeholder that stands in for a sensitive value.
+func (r *redactor) placeholder(value interface{}) string {
+	r.changed = true
+	if r.key == nil {
+		return redactedPlaceholder(value)
+	}
+	return redactedValuePrefix + r.digest(fmt.Sprintf("%v", value))[:redactedDigestLength]
+}
+
+// digest returns the hex encoded HMAC-SHA256 of a value under the redaction key, or "" without a key:
+// an unkeyed digest of secret content lets anyone confirm guesses of it.
+func (r *redactor) digest(value string) string {
+	if r.key == nil {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// redactedPlaceholder returns the unkeyed placeholder that stands in for a sensitive value.
+func redactedPlaceholder(value interface{}) string {
+	digest := gen.GenerateSha256(fmt.Sprintf("%v", value))
+	return redactedValuePrefix + digest[:redactedDigestLength]
+}
+
+// matchesPath reports whether path matches one of the patterns.
+func matchesPath(patterns [][]string, path []string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) != len(path) {
+			continue
+		}
+
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
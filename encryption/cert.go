@@ -32,6 +32,9 @@ const (
 	OsTypeHpvs = "hpvs"
 )
 
+// osTypes lists the supported OS types
+var osTypes = []string{OsTypeCcrt, OsTypeCcrv, OsTypeCcco, OsTypeHpvs}
+
 // Embed all certificate directories
 //
 //go:embed ccrt/*.crt
@@ -42,9 +45,16 @@ var certFS embed.FS
 
 // CertificateMap holds the mapping of OS type and version to certificate content.
 // This map is automatically populated from embedded certificate files.
+//
+// Deprecated: CertificateMap is not safe for concurrent modification. Use [DefaultCertStore] or a
+// [CertStore] instead. Until it is removed, versions added to it are still used when no store is
+// given and the default store does not hold the version.
 var CertificateMap map[string]map[string]string
 
 // Latest encryption certificate variables - populated automatically with latest versions
+//
+// Deprecated: use DefaultCertStore().Certificate(osType, "") instead. Until they are removed, a
+// replaced value is still used as the latest certificate when no store is given.
 var (
 	LatestEncryptionCertificateCcrt string
 	LatestEncryptionCertificateCcrv string
@@ -144,15 +154,7 @@ func getLatestCertificate(osType string) string {
 		return ""
 	}
 
-	// Find the latest version using simple version comparison
-	var latestVersion string
-	for version := range osMap {
-		if latestVersion == "" || CompareVersions(version, latestVersion) > 0 {
-			latestVersion = version
-		}
-	}
-
-	return osMap[latestVersion]
+	return osMap[latestVersion(osMap)]
 }
 
 // CompareVersions compares two semantic version strings.
@@ -0,0 +1,145 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// Organization of the subject of IBM encryption certificates
+	encryptionCertSubjectOrganization = "IBM India Pvt Ltd"
+	// Organization of the issuer of IBM encryption certificates
+	encryptionCertIssuerOrganization = "International Business Machines Corporation"
+)
+
+// CertificateIdentity is the subject and issuer an encryption certificate of a platform is expected to have.
+type CertificateIdentity struct {
+	// Organization of the certificate subject
+	SubjectOrganization string
+	// Accepted common names of the certificate subject
+	SubjectCommonNames []string
+	// Organization of the certificate issuer
+	IssuerOrganization string
+}
+
+// identities lists the expected identity of the encryption certificates of each OS type. Common names
+// are shared between platforms whose images are built by the same signing service.
+var identities = map[string]CertificateIdentity{
+	OsTypeCcrt: {
+		SubjectOrganization: encryptionCertSubjectOrganization,
+		SubjectCommonNames:  []string{"Container Runtime Contract Encryption", "Hyper Protect Container Runtime Contract Encryption"},
+		IssuerOrganization:  encryptionCertIssuerOrganization,
+	},
+	OsTypeCcrv: {
+		SubjectOrganization: encryptionCertSubjectOrganization,
+		SubjectCommonNames:  []string{"Container Runtime Contract Encryption", "Contract Encryption for Confidential Container Runtime"},
+		IssuerOrganization:  encryptionCertIssuerOrganization,
+	},
+	OsTypeCcco: {
+		SubjectOrganization: encryptionCertSubjectOrganization,
+		SubjectCommonNames:  []string{"Contract Encryption for Confidential Containers", "Hyper Protect Confidential Container Contract Encryption"},
+		IssuerOrganization:  encryptionCertIssuerOrganization,
+	},
+	OsTypeHpvs: {
+		SubjectOrganization: encryptionCertSubjectOrganization,
+		SubjectCommonNames:  []string{"Container Runtime Contract Encryption", "Hyper Protect Container Runtime Contract Encryption"},
+		IssuerOrganization:  encryptionCertIssuerOrganization,
+	},
+}
+
+// ExpectedIdentity returns the identity expected of the encryption certificates of an OS type.
+//
+// Parameters:
+//   - osType: OS type ("ccrt", "ccrv", "ccco" or "hpvs")
+//
+// Returns:
+//   - Expected certificate identity
+//   - false if the OS type is not supported
+func ExpectedIdentity(osType string) (CertificateIdentity, bool) {
+	identity, ok := identities[osType]
+	if !ok {
+		return CertificateIdentity{}, false
+	}
+	identity.SubjectCommonNames = slices.Clone(identity.SubjectCommonNames)
+	return identity, true
+}
+
+// CheckCertificateIdentity checks that a certificate is an encryption certificate of an OS type.
+//
+// The subject organization and common name and the issuer organization must match the expected
+// identity of the OS type, the subject and issuer must belong to the same signing service
+// (organizational unit), and the certificate must be an RSA end-entity certificate usable for key
+// encipherment.
+//
+// Parameters:
+//   - osType: OS type ("ccrt", "ccrv", "ccco" or "hpvs")
+//   - certificatePEM: PEM-formatted encryption certificate
+//
+// Returns:
+//   - Error describing the first mismatch, nil if the certificate matches
+func CheckCertificateIdentity(osType, certificatePEM string) error {
+	identity, ok := identities[osType]
+	if !ok {
+		return fmt.Errorf("invalid OS type: %s. Valid types are: %s", osType, strings.Join(osTypes, ", "))
+	}
+
+	block, _ := pem.Decode([]byte(certificatePEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("failed to parse certificate - no PEM encoded certificate found")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate - %v", err)
+	}
+
+	if !slices.Contains(certificate.Subject.Organization, identity.SubjectOrganization) {
+		return fmt.Errorf("subject organization %q of the certificate is not %q", strings.Join(certificate.Subject.Organization, ", "), identity.SubjectOrganization)
+	}
+
+	if !slices.Contains(identity.SubjectCommonNames, certificate.Subject.CommonName) {
+		return fmt.Errorf("subject common name %q of the certificate is not a %s encryption certificate name (%s)", certificate.Subject.CommonName, osType, strings.Join(identity.SubjectCommonNames, ", "))
+	}
+
+	if !slices.Contains(certificate.Issuer.Organization, identity.IssuerOrganization) {
+		return fmt.Errorf("issuer organization %q of the certificate is not %q", strings.Join(certificate.Issuer.Organization, ", "), identity.IssuerOrganization)
+	}
+
+	if !slices.Equal(certificate.Subject.OrganizationalUnit, certificate.Issuer.OrganizationalUnit) {
+		return fmt.Errorf("subject organizational unit %q of the certificate does not match the issuer organizational unit %q",
+			strings.Join(certificate.Subject.OrganizationalUnit, ", "), strings.Join(certificate.Issuer.OrganizationalUnit, ", "))
+	}
+
+	if certificate.IsCA {
+		return fmt.Errorf("certificate is a CA certificate")
+	}
+
+	if _, ok := certificate.PublicKey.(*rsa.PublicKey); !ok {
+		return fmt.Errorf("certificate public key is not an RSA key")
+	}
+
+	if certificate.KeyUsage != 0 && certificate.KeyUsage&x509.KeyUsageKeyEncipherment == 0 {
+		return fmt.Errorf("certificate key usage does not allow key encipherment")
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testcase to check if every embedded certificate matches the expected identity of its OS type
+func TestCheckCertificateIdentityEmbedded(t *testing.T) {
+	for _, osType := range OsTypes() {
+		for version, certificate := range CertificateMap[osType] {
+			assert.NoError(t, CheckCertificateIdentity(osType, certificate), "%s %s", osType, version)
+		}
+	}
+
+	identity, ok := ExpectedIdentity(OsTypeCcco)
+	require.True(t, ok)
+	assert.Equal(t, encryptionCertSubjectOrganization, identity.SubjectOrganization)
+	assert.NotEmpty(t, identity.SubjectCommonNames)
+
+	_, ok = ExpectedIdentity("zos")
+	assert.False(t, ok)
+}
+
+// Testcase to check if certificates of another platform or issuer are rejected
+func TestCheckCertificateIdentityMismatch(t *testing.T) {
+	err := CheckCertificateIdentity(OsTypeCcco, CertificateMap[OsTypeHpvs]["1.0.28"])
+	assert.ErrorContains(t, err, `subject common name "Hyper Protect Container Runtime Contract Encryption" of the certificate is not a ccco encryption certificate name`)
+
+	err = CheckCertificateIdentity(OsTypeCcrt, CertificateMap[OsTypeCcco]["26.4.0"])
+	assert.ErrorContains(t, err, "is not a ccrt encryption certificate name")
+
+	selfSigned, err := os.ReadFile("../samples/encryption-cert/expired.crt")
+	require.NoError(t, err)
+	err = CheckCertificateIdentity(OsTypeHpvs, string(selfSigned))
+	assert.EqualError(t, err, `subject organization "ExampleOrg" of the certificate is not "IBM India Pvt Ltd"`)
+
+	err = CheckCertificateIdentity(OsTypeHpvs, "invalid")
+	assert.EqualError(t, err, "failed to parse certificate - no PEM encoded certificate found")
+
+	err = CheckCertificateIdentity("zos", CertificateMap[OsTypeHpvs]["1.0.28"])
+	assert.EqualError(t, err, "invalid OS type: zos. Valid types are: ccrt, ccrv, ccco, hpvs")
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CertStore provides encryption certificates by OS type and version.
+type CertStore interface {
+	// Certificate returns the certificate of an OS type and version, or the latest version if version is empty.
+	Certificate(osType, version string) (string, bool)
+	// Versions returns the versions available for an OS type, latest first.
+	Versions(osType string) []string
+}
+
+// WritableCertStore is a CertStore that certificates can be added to.
+type WritableCertStore interface {
+	CertStore
+	// Add adds or replaces the certificate of an OS type and version.
+	Add(osType, version, certificate string) error
+	// AddAll adds or replaces several certificates at once. If an error is returned, none of them is added.
+	AddAll(entries []CertEntry) error
+}
+
+// CertEntry is a certificate of an OS type and version to add to a [WritableCertStore].
+type CertEntry struct {
+	// OS type ("ccrt", "ccrv", "ccco" or "hpvs")
+	OsType string
+	// Certificate version (e.g., "26.2.0")
+	Version string
+	// PEM-formatted encryption certificate
+	Certificate string
+}
+
+// BundleRecorder records the creation time of the newest certificate bundle loaded into a store, so
+// that older bundles are refused. An implementation that persists the time in RecordBundle refuses
+// replayed bundles across restarts.
+type BundleRecorder interface {
+	// CheckBundle returns an error if a bundle created at createdAt is older than the newest recorded bundle.
+	CheckBundle(createdAt time.Time) error
+	// RecordBundle records that a bundle created at createdAt is loaded, or returns an error as CheckBundle.
+	RecordBundle(createdAt time.Time) error
+}
+
+// MemoryCertStore is a CertStore that keeps certificates in memory. It is safe for concurrent use,
+// so certificates can be added while contracts are being encrypted.
+type MemoryCertStore struct {
+	mu    sync.RWMutex
+	certs map[string]map[string]string
+	// creation time of the newest certificate bundle loaded into the store
+	bundleCreatedAt time.Time
+}
+
+var defaultCertStore = NewEmbeddedCertStore()
+
+// DefaultCertStore returns the store used to resolve encryption certificates when no store is given.
+// It starts with the embedded certificates; certificates added to it are used by all callers.
+func DefaultCertStore() *MemoryCertStore {
+	return defaultCertStore
+}
+
+// NewCertStore creates an empty certificate store.
+func NewCertStore() *MemoryCertStore {
+	return &MemoryCertStore{certs: make(map[string]map[string]string)}
+}
+
+// NewEmbeddedCertStore creates a certificate store holding the certificates embedded in this package.
+func NewEmbeddedCertStore() *MemoryCertStore {
+	store := NewCertStore()
+	for _, osType := range osTypes {
+		dir, err := fs.Sub(certFS, osType)
+		if err != nil {
+			continue
+		}
+		// Embedded certificates are known to be valid
+		store.AddFS(osType, dir)
+	}
+	return store
+}
+
+// Certificate returns the certificate of an OS type and version, or the latest version if version is empty.
+//
+// Parameters:
+//   - osType: OS type ("ccrt", "ccrv", "ccco" or "hpvs")
+//   - version: Certificate version, or empty for the latest version
+//
+// Returns:
+//   - PEM-formatted encryption certificate
+//   - true if the certificate was found
+func (s *MemoryCertStore) Certificate(osType, version string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	osMap := s.certs[osType]
+	if version == "" {
+		version = latestVersion(osMap)
+	}
+	certificate, ok := osMap[version]
+	return certificate, ok
+}
+
+// Versions returns the versions available for an OS type, latest first.
+//
+// Parameters:
+//   - osType: OS type ("ccrt", "ccrv", "ccco" or "hpvs")
+//
+// Returns:
+//   - Versions sorted in descending order
+func (s *MemoryCertStore) Versions(osType string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := make([]string, 0, len(s.certs[osType]))
+	for version := range s.certs[osType] {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return CompareVersions(versions[i], versions[j]) > 0
+	})
+	return versions
+}
+
+// Add adds or replaces the certificate of an OS type and version.
+//
+// Parameters:
+//   - osType: OS type ("ccrt", "ccrv", "ccco" or "hpvs")
+//   - version: Certificate version (e.g., "26.2.0")
+//   - certificate: PEM-formatted encryption certificate
+//
+// Returns:
+//   - Error if the OS type is unknown, the version is empty or the certificate cannot be parsed
+func (s *MemoryCertStore) Add(osType, version, certificate string) error {
+	return s.AddAll([]CertEntry{{OsType: osType, Version: version, Certificate: certificate}})
+}
+
+// AddAll adds or replaces several certificates at once. All certificates are checked before any is
+// added, so either all of them are added or none.
+//
+// Parameters:
+//   - entries: Certificates with their OS types and versions
+//
+// Returns:
+//   - Error if an OS type is unknown, a version is empty or a certificate cannot be parsed
+func (s *MemoryCertStore) AddAll(entries []CertEntry) error {
+	for _, entry := range entries {
+		if err := checkCertEntry(entry.OsType, entry.Version, entry.Certificate); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range entries {
+		if s.certs[entry.OsType] == nil {
+			s.certs[entry.OsType] = make(map[string]string)
+		}
+		s.certs[entry.OsType][entry.Version] = entry.Certificate
+	}
+	return nil
+}
+
+// checkCertEntry checks the OS type, version and certificate of a store entry.
+func checkCertEntry(osType, version, certificate string) error {
+	if !isOsType(osType) {
+		return fmt.Errorf("invalid OS type: %s. Valid types are: %s", osType, strings.Join(osTypes, ", "))
+	}
+	if version == "" {
+		return fmt.Errorf("certificate version is missing")
+	}
+
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("failed to parse certificate %s - no PEM encoded certificate found", version)
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return fmt.Errorf("failed to parse certificate %s - %v", version, err)
+	}
+	return nil
+}
+
+// CheckBundle checks that a certificate bundle created at createdAt may be loaded into the store, i.e.
+// that it is not older than the newest bundle recorded with [MemoryCertStore.RecordBundle].
+//
+// Parameters:
+//   - createdAt: Creation time of the bundle
+//
+// Returns:
+//   - Error if a newer bundle was loaded before
+func (s *MemoryCertStore) CheckBundle(createdAt time.Time) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.checkBundle(createdAt)
+}
+
+// RecordBundle records that a certificate bundle created at createdAt is loaded into the store. A
+// bundle older than the newest recorded bundle is refused, so that replaying an old bundle cannot
+// roll back the certificates of the store.
+//
+// The store keeps the time in memory only. To refuse replayed bundles after a restart, persist
+// [MemoryCertStore.LastBundle] after loading a bundle and record the persisted time on startup.
+//
+// Parameters:
+//   - createdAt: Creation time of the bundle
+//
+// Returns:
+//   - Error if a newer bundle was recorded before
+func (s *MemoryCertStore) RecordBundle(createdAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkBundle(createdAt); err != nil {
+		return err
+	}
+	s.bundleCreatedAt = createdAt
+	return nil
+}
+
+// LastBundle returns the creation time of the newest certificate bundle recorded with
+// [MemoryCertStore.RecordBundle], or the zero time if none was recorded.
+func (s *MemoryCertStore) LastBundle() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.bundleCreatedAt
+}
+
+// checkBundle compares the creation time of a bundle with the newest recorded bundle. The caller must
+// hold the lock.
+func (s *MemoryCertStore) checkBundle(createdAt time.Time) error {
+	if createdAt.Before(s.bundleCreatedAt) {
+		return fmt.Errorf("certificate bundle created at %s is older than the bundle created at %s loaded before",
+			createdAt.UTC().Format(time.RFC3339), s.bundleCreatedAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AddFS adds the "*-encrypt.crt" certificates found at the root of a file system. The version of
+// each certificate is taken from its file name, as for the embedded certificates
+// (e.g. "ibm-confidential-computing-container-runtime-26.2.0-encrypt.crt").
+//
+// Parameters:
+//   - osType: OS type the certificates belong to
+//   - fsys: File system holding the certificates
+//
+// Returns:
+//   - Versions that were added
+//   - Error if the directory cannot be read or a certificate is invalid; certificates added before the error are kept
+func (s *MemoryCertStore) AddFS(osType string, fsys fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate directory - %v", err)
+	}
+
+	var added []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".crt") {
+			continue
+		}
+
+		version := extractVersionFromFilename(entry.Name())
+		if version == "" {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return added, fmt.Errorf("failed to read certificate %s - %v", entry.Name(), err)
+		}
+
+		if err := s.Add(osType, version, string(content)); err != nil {
+			return added, err
+		}
+		added = append(added, version)
+	}
+
+	return added, nil
+}
+
+// AddDir adds the "*-encrypt.crt" certificates found in a directory. See [MemoryCertStore.AddFS].
+//
+// Parameters:
+//   - osType: OS type the certificates belong to
+//   - dir: Directory holding the certificates
+//
+// Returns:
+//   - Versions that were added
+//   - Error if the directory cannot be read or a certificate is invalid
+func (s *MemoryCertStore) AddDir(osType, dir string) ([]string, error) {
+	return s.AddFS(osType, os.DirFS(dir))
+}
+
+// OsTypes returns the supported OS types.
+func OsTypes() []string {
+	return slices.Clone(osTypes)
+}
+
+// latestVersion returns the highest version of a version map.
+func latestVersion(osMap map[string]string) string {
+	var latest string
+	for version := range osMap {
+		if latest == "" || CompareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// isOsType reports whether osType is a supported OS type.
+func isOsType(osType string) bool {
+	return slices.Contains(osTypes, osType)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package encryption
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Testcase to check if the embedded certificate store matches the embedded certificates
+func TestNewEmbeddedCertStore(t *testing.T) {
+	store := NewEmbeddedCertStore()
+
+	for _, osType := range OsTypes() {
+		versions := store.Versions(osType)
+		assert.Len(t, versions, len(CertificateMap[osType]))
+
+		latest, ok := store.Certificate(osType, "")
+		require.True(t, ok)
+		assert.Equal(t, CertificateMap[osType][versions[0]], latest)
+	}
+
+	certificate, ok := store.Certificate(OsTypeHpvs, "1.0.28")
+	require.True(t, ok)
+	assert.Equal(t, CertificateMap[OsTypeHpvs]["1.0.28"], certificate)
+
+	_, ok = store.Certificate(OsTypeHpvs, "9.9.9")
+	assert.False(t, ok)
+}
+
+// Testcase to check if certificates can be added from an fs.FS and a directory
+func TestMemoryCertStoreAdd(t *testing.T) {
+	embedded := CertificateMap[OsTypeCcrt]["26.2.0"]
+	require.NotEmpty(t, embedded)
+
+	store := NewCertStore()
+	added, err := store.AddFS(OsTypeCcrt, fstest.MapFS{
+		"ibm-confidential-computing-container-runtime-99.1.0-encrypt.crt": {Data: []byte(embedded)},
+		"README.md": {Data: []byte("not a certificate")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"99.1.0"}, added)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ibm-confidential-computing-container-runtime-99.10.0-encrypt.crt"), []byte(embedded), 0600))
+	added, err = store.AddDir(OsTypeCcrt, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"99.10.0"}, added)
+
+	assert.Equal(t, []string{"99.10.0", "99.1.0"}, store.Versions(OsTypeCcrt))
+	latest, ok := store.Certificate(OsTypeCcrt, "")
+	require.True(t, ok)
+	assert.Equal(t, embedded, latest)
+	assert.Empty(t, store.Versions(OsTypeCcrv))
+}
+
+// Testcase to check if invalid certificates are rejected
+func TestMemoryCertStoreAddInvalid(t *testing.T) {
+	store := NewCertStore()
+
+	assert.EqualError(t, store.Add("linux", "1.0.0", ""), "invalid OS type: linux. Valid types are: ccrt, ccrv, ccco, hpvs")
+	assert.EqualError(t, store.Add(OsTypeCcrt, "", ""), "certificate version is missing")
+	assert.EqualError(t, store.Add(OsTypeCcrt, "1.0.0", "invalid"), "failed to parse certificate 1.0.0 - no PEM encoded certificate found")
+
+	_, err := store.AddFS(OsTypeCcrt, fstest.MapFS{
+		"ibm-confidential-computing-container-runtime-1.0.0-encrypt.crt": {Data: []byte("invalid")},
+	})
+	assert.Error(t, err)
+
+	_, err = store.AddDir(OsTypeCcrt, filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "failed to read certificate directory")
+}
+
+// Testcase to check if AddAll adds no certificate when one of them is invalid
+func TestMemoryCertStoreAddAll(t *testing.T) {
+	embedded := CertificateMap[OsTypeCcrt]["26.2.0"]
+	store := NewCertStore()
+
+	err := store.AddAll([]CertEntry{
+		{OsType: OsTypeCcrt, Version: "99.1.0", Certificate: embedded},
+		{OsType: OsTypeCcrv, Version: "99.1.0", Certificate: "invalid"},
+	})
+	assert.EqualError(t, err, "failed to parse certificate 99.1.0 - no PEM encoded certificate found")
+	assert.Empty(t, store.Versions(OsTypeCcrt))
+
+	require.NoError(t, store.AddAll([]CertEntry{
+		{OsType: OsTypeCcrt, Version: "99.1.0", Certificate: embedded},
+		{OsType: OsTypeCcrv, Version: "99.1.0", Certificate: embedded},
+	}))
+	assert.Equal(t, []string{"99.1.0"}, store.Versions(OsTypeCcrt))
+	assert.Equal(t, []string{"99.1.0"}, store.Versions(OsTypeCcrv))
+}
+
+// Testcase to check if a certificate store can be read and extended concurrently
+func TestMemoryCertStoreConcurrentUse(t *testing.T) {
+	store := NewEmbeddedCertStore()
+	embedded := CertificateMap[OsTypeHpvs]["1.0.28"]
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Go(func() {
+			assert.NoError(t, store.Add(OsTypeHpvs, "2.0."+strconv.Itoa(i), embedded))
+		})
+		wg.Go(func() {
+			_, ok := store.Certificate(OsTypeHpvs, "")
+			assert.True(t, ok)
+			assert.NotEmpty(t, store.Versions(OsTypeHpvs))
+		})
+	}
+	wg.Wait()
+
+	assert.Len(t, store.Versions(OsTypeHpvs), len(CertificateMap[OsTypeHpvs])+8)
+}
+
+// Testcase to check if a store refuses bundles older than the newest bundle loaded before
+func TestMemoryCertStoreRecordBundle(t *testing.T) {
+	store := NewCertStore()
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	require.NoError(t, store.CheckBundle(older))
+	require.NoError(t, store.RecordBundle(newer))
+	require.NoError(t, store.RecordBundle(newer))
+
+	assert.EqualError(t, store.CheckBundle(older), "certificate bundle created at 2026-01-01T00:00:00Z is older than the bundle created at 2026-01-01T01:00:00Z loaded before")
+	assert.Error(t, store.RecordBundle(older))
+	assert.NoError(t, store.CheckBundle(newer.Add(time.Second)))
+	assert.True(t, newer.Equal(store.LastBundle()))
+	assert.True(t, NewCertStore().LastBundle().IsZero())
+}
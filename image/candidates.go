@@ -0,0 +1,236 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	gen "github.com/ibm-hyper-protect/contract-go/v2/common/general"
+)
+
+// ImageCandidate is an image of a supported platform found in an IBM Cloud image list.
+type ImageCandidate struct {
+	// IBM Cloud image ID
+	ID string `json:"id" yaml:"id"`
+	// Full image name
+	Name string `json:"name" yaml:"name"`
+	// SHA256 checksum of the image
+	Checksum string `json:"checksum" yaml:"checksum"`
+	// Version encoded in the image name
+	Version *semver.Version `json:"version" yaml:"version"`
+	// Platform of the image ("ccrt", "ccrv", "hpvs" or the OsType of a custom platform)
+	Platform string `json:"platform" yaml:"platform"`
+	// Image status, e.g. "available" or "deprecated"
+	Status string `json:"status" yaml:"status"`
+	// Image visibility, "public" or "private"
+	Visibility string `json:"visibility" yaml:"visibility"`
+	// Owner of the image, e.g. "provider" or "user"
+	OwnerType string `json:"ownerType" yaml:"ownerType"`
+	// Whether the image is managed by a catalog offering
+	CatalogManaged bool `json:"catalogManaged" yaml:"catalogManaged"`
+	// Creation time of the image; zero if the image list does not include it
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+	// Time the image is or was scheduled to become deprecated; zero if not scheduled
+	DeprecationAt time.Time `json:"deprecationAt" yaml:"deprecationAt"`
+	// Time the image is or was scheduled to become obsolete; zero if not scheduled
+	ObsolescenceAt time.Time `json:"obsolescenceAt" yaml:"obsolescenceAt"`
+	// Whether the image is deprecated or obsolete, by status or by a deprecation time in the past
+	Deprecated bool `json:"deprecated" yaml:"deprecated"`
+}
+
+// WithStatus selects images with one of the given statuses (e.g. "available", "deprecated"). By
+// default only available images are selected.
+func WithStatus(statuses ...string) SelectOption {
+	return func(c *selectConfig) {
+		c.statuses = append(c.statuses, statuses...)
+	}
+}
+
+// WithVisibility selects images with one of the given visibilities ("public", "private"). By default
+// only public images are selected.
+func WithVisibility(visibilities ...string) SelectOption {
+	return func(c *selectConfig) {
+		c.visibilities = append(c.visibilities, visibilities...)
+	}
+}
+
+// WithExcludeDeprecated skips deprecated and obsolete images, including images whose deprecation time
+// has passed while their status is still available.
+func WithExcludeDeprecated() SelectOption {
+	return func(c *selectConfig) {
+		c.excludeDeprecated = true
+	}
+}
+
+// WithCatalogManaged selects only images that are (true) or are not (false) managed by a catalog
+// offering. By default both are selected.
+func WithCatalogManaged(managed bool) SelectOption {
+	return func(c *selectConfig) {
+		c.catalogManaged = &managed
+	}
+}
+
+// WithSelectionTime evaluates deprecation and obsolescence times at the given time instead of now.
+func WithSelectionTime(at time.Time) SelectOption {
+	return func(c *selectConfig) {
+		c.at = at
+	}
+}
+
+// HpcrListImages returns every image of the supported platforms in an IBM Cloud image list that
+// passes the filters, so that callers can apply their own selection policy.
+//
+// The same platforms and filters as for [HpcrSelectImage] apply: by default available public hpvs
+// images. Candidates are grouped by platform in the order the platforms were requested, as versions
+// of different platforms are not comparable. Within a platform, candidates are sorted by version,
+// newest first; images of the same version are sorted by creation time, newest first.
+//
+// Parameters:
+//   - imageJsonData: JSON array of IBM Cloud images (from VPC API, Terraform ibm_is_images
+//     data source, or `ibmcloud is images --json` CLI output)
+//   - versionSpec: Semantic version constraint (e.g., ">=1.1.0"). If empty, all versions are returned.
+//   - opts: Optional filters, e.g. WithPlatform("ccrt"), WithVisibility("public", "private"),
+//     WithStatus("available", "deprecated"), WithExcludeDeprecated() or WithCatalogManaged(true)
+//
+// Returns:
+//   - Image candidates, empty if no image matches
+//   - Error if the JSON is invalid, the version constraint is invalid or a platform is not supported
+func HpcrListImages(imageJsonData, versionSpec string, opts ...SelectOption) ([]ImageCandidate, error) {
+	if gen.CheckIfEmpty(imageJsonData) {
+		return nil, fmt.Errorf(emptyParameterErrStatement)
+	}
+
+	config, err := newSelectConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := listCandidates(imageJsonData, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if versionSpec != "" {
+		constraint, err := semver.NewConstraint(versionSpec)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing target version constraint - %v", err)
+		}
+
+		candidates = slices.DeleteFunc(candidates, func(candidate ImageCandidate) bool {
+			return !constraint.Check(candidate.Version)
+		})
+	}
+
+	platforms := config.platformTypes()
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Platform != candidates[j].Platform {
+			return slices.Index(platforms, candidates[i].Platform) < slices.Index(platforms, candidates[j].Platform)
+		}
+		if !candidates[i].Version.Equal(candidates[j].Version) {
+			return candidates[i].Version.GreaterThan(candidates[j].Version)
+		}
+		return candidates[i].CreatedAt.After(candidates[j].CreatedAt)
+	})
+
+	return candidates, nil
+}
+
+// listCandidates parses an IBM Cloud image list and returns the images that match a requested
+// platform and pass the filters, in the order of the list.
+func listCandidates(imageJsonData string, config *selectConfig) ([]ImageCandidate, error) {
+	images, err := parseImages(imageJsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []ImageCandidate{}
+	for _, image := range images {
+		for _, platform := range config.platforms {
+			version, ok := platform.Version(image)
+			if !ok {
+				continue
+			}
+
+			candidate := newImageCandidate(image, version, platform.OsType, config.at)
+			if config.accept(candidate) {
+				candidates = append(candidates, candidate)
+			}
+			break
+		}
+	}
+
+	return candidates, nil
+}
+
+// newImageCandidate builds the candidate of an image, evaluating its deprecation at the given time.
+func newImageCandidate(image Image, version *semver.Version, platform string, at time.Time) ImageCandidate {
+	candidate := ImageCandidate{
+		ID:             image.ID,
+		Name:           image.Name,
+		Checksum:       image.Checksum,
+		Version:        version,
+		Platform:       platform,
+		Status:         image.Status,
+		Visibility:     image.Visibility,
+		OwnerType:      image.OwnerType,
+		CatalogManaged: image.CatalogManaged,
+		CreatedAt:      parseImageTime(image.CreatedAt),
+		DeprecationAt:  parseImageTime(image.DeprecationAt),
+		ObsolescenceAt: parseImageTime(image.ObsolescenceAt),
+	}
+
+	candidate.Deprecated = image.Status == "deprecated" || image.Status == "obsolete" ||
+		(!candidate.DeprecationAt.IsZero() && !candidate.DeprecationAt.After(at)) ||
+		(!candidate.ObsolescenceAt.IsZero() && !candidate.ObsolescenceAt.After(at))
+
+	return candidate
+}
+
+// accept checks if a candidate passes the status, visibility, deprecation and catalog filters.
+func (c *selectConfig) accept(candidate ImageCandidate) bool {
+	if !slices.ContainsFunc(c.statuses, func(status string) bool { return strings.EqualFold(status, candidate.Status) }) {
+		return false
+	}
+	if !slices.ContainsFunc(c.visibilities, func(visibility string) bool { return strings.EqualFold(visibility, candidate.Visibility) }) {
+		return false
+	}
+	if c.excludeDeprecated && candidate.Deprecated {
+		return false
+	}
+	if c.catalogManaged != nil && *c.catalogManaged != candidate.CatalogManaged {
+		return false
+	}
+	return true
+}
+
+// parseImageTime parses an RFC 3339 time of the IBM Cloud image list, returning the zero time if the
+// value is empty or invalid.
+func parseImageTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
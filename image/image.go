@@ -18,8 +18,8 @@ package image
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 
@@ -28,15 +28,21 @@ import (
 
 type (
 	Image struct {
-		ID           string      `json:"id"`
-		Name         string      `json:"name"`
-		Status       string      `json:"status"`
-		Visibility   string      `json:"visibility"`
-		Architecture string      `json:"architecture"`
-		Os           string      `json:"os"`
-		File         *File       `json:"file,omitempty"`
-		Checksum     string      `json:"checksum"`
-		OSRaw        interface{} `json:"operating_system"`
+		ID             string      `json:"id"`
+		Name           string      `json:"name"`
+		Status         string      `json:"status"`
+		Visibility     string      `json:"visibility"`
+		Architecture   string      `json:"architecture"`
+		Os             string      `json:"os"`
+		File           *File       `json:"file,omitempty"`
+		Checksum       string      `json:"checksum"`
+		OSRaw          interface{} `json:"operating_system"`
+		CreatedAt      string      `json:"created_at"`
+		DeprecationAt  string      `json:"deprecation_at"`
+		ObsolescenceAt string      `json:"obsolescence_at"`
+		OwnerType      string      `json:"owner_type"`
+		CatalogRaw     interface{} `json:"catalog_offering"`
+		CatalogManaged bool        `json:"-"`
 	}
 
 	File struct {
@@ -52,22 +58,19 @@ type (
 		Architecture string `json:"architecture"`
 	}
 
+	CatalogOffering struct {
+		Managed bool `json:"managed"`
+	}
+
 	ImageVersion struct {
 		ID       string
 		Checksum string
 		Name     string
 		Version  *semver.Version
+		Platform string
 	}
 )
 
-var (
-	// reConfidentialComputingOS tests if this is a confidential computing image
-	reConfidentialComputingOS = regexp.MustCompile(`^hyper-protect-[\w-]+-s390x-hpcr$`)
-
-	// reConfidentialComputingName tests if the name references a valid confidential computing version
-	reConfidentialComputingName = regexp.MustCompile(`^ibm-hyper-protect-container-runtime-(\d+)-(\d+)-s390x-(\d+)$`)
-)
-
 const (
 	emptyParameterErrStatement = "required parameter is empty"
 )
@@ -81,35 +84,81 @@ const (
 // valid IBM Confidential Computing images, and returns the latest image matching the version
 // specification.
 //
+// Images are recognised by the naming of their platform (see [Platform]). By default only HPVS images
+// ("ibm-hyper-protect-container-runtime-X-Y-s390x-Z") are selected. Use [WithPlatform] to select
+// CCRT images ("ibm-confidential-computing-container-runtime-X-Y-Z") or CCRV images instead. Versions
+// of different platforms are not comparable, so only one platform can be selected; use [HpcrListImages]
+// to list the images of several platforms.
+//
 // Parameters:
 //   - imageJsonData: JSON array of IBM Cloud images (from VPC API, Terraform ibm_is_images
 //     data source, or `ibmcloud is images --json` CLI output)
 //   - versionSpec: Semantic version constraint (e.g., ">=1.1.0", "~1.1.14", "1.1.15").
 //     If empty, selects the absolute latest available version.
+//   - opts: Optional settings, e.g. WithPlatform("ccrv"), WithVisibility("private") or WithExcludeDeprecated()
 //
 // Returns:
 //   - Image ID from IBM Cloud (used for VSI creation)
 //   - Full image name (e.g., "ibm-hyper-protect-container-runtime-1-1-s390x-15")
 //   - SHA256 checksum of the image (for integrity verification)
 //   - Semantic version string (e.g., "1.1.15")
-//   - Error if no matching image found or JSON is invalid
-func HpcrSelectImage(imageJsonData, versionSpec string) (string, string, string, string, error) {
+//   - Error if no matching image found, JSON is invalid or more than one platform is requested
+func HpcrSelectImage(imageJsonData, versionSpec string, opts ...SelectOption) (string, string, string, string, error) {
 	if gen.CheckIfEmpty(imageJsonData) {
 		return "", "", "", "", fmt.Errorf(emptyParameterErrStatement)
 	}
 
-	var images []Image
+	config, err := newSelectConfig(opts)
+	if err != nil {
+		return "", "", "", "", err
+	}
+	if platforms := config.platformTypes(); len(platforms) > 1 {
+		return "", "", "", "", fmt.Errorf("only one platform can be selected, got: %s - use HpcrListImages to list images of several platforms", strings.Join(platforms, ", "))
+	}
+
+	candidates, err := listCandidates(imageJsonData, config)
+	if err != nil {
+		return "", "", "", "", err
+	}
+
 	var confidentialComputingImages []ImageVersion
+	for _, candidate := range candidates {
+		confidentialComputingImages = append(confidentialComputingImages, ImageVersion{
+			ID:       candidate.ID,
+			Name:     candidate.Name,
+			Checksum: candidate.Checksum,
+			Version:  candidate.Version,
+			Platform: candidate.Platform,
+		})
+	}
 
+	return PickLatestImage(confidentialComputingImages, versionSpec)
+}
+
+// parseImages unmarshals an IBM Cloud image list and fills the architecture, operating system,
+// checksum and catalog state of each image from the nested fields of the API, Terraform and CLI formats.
+func parseImages(imageJsonData string) ([]Image, error) {
+	var images []Image
 	err := json.Unmarshal([]byte(imageJsonData), &images)
 	if err != nil {
-		return "", "", "", "", fmt.Errorf("failed to unmarshal JSON - %v", err)
+		return nil, fmt.Errorf("failed to unmarshal JSON - %v", err)
 	}
 
-	for _, image := range images {
+	for i := range images {
+		image := &images[i]
+
+		var osData interface{}
 		switch data := image.OSRaw.(type) {
 		case map[string]interface{}:
-			osJson, _ := json.Marshal(data)
+			osData = data
+		case []interface{}:
+			if len(data) > 0 {
+				osData = data[0]
+			}
+		}
+
+		if osData != nil {
+			osJson, _ := json.Marshal(osData)
 			var os OperatingSystem
 			_ = json.Unmarshal(osJson, &os)
 
@@ -119,50 +168,52 @@ func HpcrSelectImage(imageJsonData, versionSpec string) (string, string, string,
 			if image.Os == "" {
 				image.Os = os.Name
 			}
-		case []interface{}:
-			if len(data) > 0 {
-				osJson, _ := json.Marshal(data[0])
-				var os OperatingSystem
-				_ = json.Unmarshal(osJson, &os)
-				if image.Architecture == "" {
-					image.Architecture = os.Architecture
-				}
-				if image.Os == "" {
-					image.Os = os.Name
-				}
-			}
 		}
 
 		if image.Checksum == "" && image.File != nil {
 			image.Checksum = image.File.Checksums.Sha256
 		}
 
-		if IsCandidateImage(image) {
-			versionRegex := reConfidentialComputingName.FindStringSubmatch(image.Name)
-			confidentialComputingImages = append(confidentialComputingImages, ImageVersion{
-				ID:       image.ID,
-				Name:     image.Name,
-				Checksum: image.Checksum,
-				Version:  semver.MustParse(fmt.Sprintf("%s.%s.%s", versionRegex[1], versionRegex[2], versionRegex[3])),
-			})
+		var catalogData interface{}
+		switch data := image.CatalogRaw.(type) {
+		case map[string]interface{}:
+			catalogData = data
+		case []interface{}:
+			if len(data) > 0 {
+				catalogData = data[0]
+			}
+		}
+
+		if catalogData != nil {
+			catalogJson, _ := json.Marshal(catalogData)
+			var catalog CatalogOffering
+			_ = json.Unmarshal(catalogJson, &catalog)
+			image.CatalogManaged = catalog.Managed
 		}
 	}
 
-	return PickLatestImage(confidentialComputingImages, versionSpec)
+	return images, nil
 }
 
-// IsCandidateImage checks if an image is a valid IBM Confidential Computing Container Runtime (CCRT) image.
+// IsCandidateImage checks if an image is a valid IBM Hyper Protect Virtual Servers (HPVS) image.
 // It validates that the image meets all requirements: s390x architecture, available status,
-// public visibility, and matches the IBM Confidential Computing OS and naming patterns.
+// public visibility, and matches the OS and naming patterns of the HPVS images. Use
+// Platform("ccrt") and [ImagePlatform.IsCandidate] to check CCRT images, and [HpcrListImages] to
+// select images with other statuses or visibilities.
 //
 // Parameters:
 //   - img: Image structure parsed from IBM Cloud image JSON
 //
 // Returns:
-//   - true if the image is a valid IBM Confidential Computing Container Runtime (CCRT) image, false otherwise
+//   - true if the image is a valid HPVS image, false otherwise
 func IsCandidateImage(img Image) bool {
-	return img.Architecture == "s390x" && img.Status == "available" && img.Visibility == "public" &&
-		reConfidentialComputingOS.MatchString(img.Os) && reConfidentialComputingName.MatchString(img.Name)
+	for _, osType := range defaultImagePlatforms {
+		platform, _ := Platform(osType)
+		if platform.IsCandidate(img) {
+			return true
+		}
+	}
+	return false
 }
 
 // PickLatestImage selects the latest image from a list of IBM Confidential Computing Container
@@ -16,7 +16,10 @@
 package image
 
 import (
+	"regexp"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/stretchr/testify/assert"
@@ -178,3 +181,237 @@ func TestPickLatestImageWithoutVersion(t *testing.T) {
 	assert.Equal(t, imageChecksum, sampleChecksum)
 	assert.Equal(t, imageVersion, sampleVersion)
 }
+
+// sampleMixedImageList contains images of every platform naming in IBM Cloud API format
+const sampleMixedImageList = `[
+  {"id": "r006-hpvs-29", "name": "ibm-hyper-protect-container-runtime-1-0-s390x-29", "status": "available", "visibility": "public",
+   "file": {"checksums": {"sha256": "hpvs29"}}, "operating_system": {"name": "hyper-protect-1-0-s390x-hpcr", "architecture": "s390x"}},
+  {"id": "r006-ccrt-26-5-0", "name": "ibm-confidential-computing-container-runtime-26-5-0", "status": "available", "visibility": "public",
+   "file": {"checksums": {"sha256": "ccrt2650"}}, "operating_system": {"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}},
+  {"id": "r006-ccrt-26-7-2", "name": "ibm-confidential-computing-container-runtime-26-7-2", "status": "available", "visibility": "public",
+   "file": {"checksums": {"sha256": "ccrt2672"}}, "operating_system": {"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}},
+  {"id": "r006-ccrt-26-8-0", "name": "ibm-confidential-computing-container-runtime-26-8-0", "status": "pending", "visibility": "public",
+   "file": {"checksums": {"sha256": "ccrt2680"}}, "operating_system": {"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}},
+  {"id": "r006-ccrv-26-7-1", "name": "ibm-confidential-computing-container-runtime-rhvs-26-7-1", "status": "available", "visibility": "public",
+   "file": {"checksums": {"sha256": "ccrv2671"}}, "operating_system": {"name": "confidential-computing-26-s390x-ccrv", "architecture": "s390x"}},
+  {"id": "r006-other", "name": "ibm-redhat-9-4-minimal-s390x-7", "status": "available", "visibility": "public",
+   "file": {"checksums": {"sha256": "other"}}, "operating_system": {"name": "red-9-s390x-byol", "architecture": "s390x"}}
+]`
+
+// Testcase to check if HpcrSelectImage() selects the latest image of the requested platform
+func TestSelectImagePlatforms(t *testing.T) {
+	// Versions of different platforms are not compared, so only hpvs images are selected by default
+	imageId, _, _, imageVersion, err := HpcrSelectImage(sampleMixedImageList, "")
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-hpvs-29", imageId)
+	assert.Equal(t, "1.0.29", imageVersion)
+
+	imageId, imageName, imageChecksum, imageVersion, err := HpcrSelectImage(sampleMixedImageList, "", WithPlatform("ccrt"))
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-ccrt-26-7-2", imageId)
+	assert.Equal(t, "ibm-confidential-computing-container-runtime-26-7-2", imageName)
+	assert.Equal(t, "ccrt2672", imageChecksum)
+	assert.Equal(t, "26.7.2", imageVersion)
+
+	imageId, _, _, imageVersion, err = HpcrSelectImage(sampleMixedImageList, "~26.5.0", WithPlatform("ccrt"))
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-ccrt-26-5-0", imageId)
+	assert.Equal(t, "26.5.0", imageVersion)
+
+	imageId, _, _, imageVersion, err = HpcrSelectImage(sampleMixedImageList, "", WithPlatform("hpvs"))
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-hpvs-29", imageId)
+	assert.Equal(t, "1.0.29", imageVersion)
+
+	imageId, _, _, imageVersion, err = HpcrSelectImage(sampleMixedImageList, "", WithPlatform("CCRV"))
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-ccrv-26-7-1", imageId)
+	assert.Equal(t, "26.7.1", imageVersion)
+
+	_, _, _, _, err = HpcrSelectImage(sampleMixedImageList, "", WithPlatform("ccco"))
+	assert.EqualError(t, err, "invalid OS type: ccco. Valid types are: ccrt, ccrv, hpvs")
+
+	_, _, _, _, err = HpcrSelectImage(sampleMixedImageList, "~26.5.0")
+	assert.EqualError(t, err, "no Hyper Protect image matching version found")
+
+	_, _, _, _, err = HpcrSelectImage(sampleMixedImageList, ">=27.0.0", WithPlatform("ccrt"))
+	assert.EqualError(t, err, "no Hyper Protect image matching version found")
+
+	// Versions of different platforms are not comparable
+	_, _, _, _, err = HpcrSelectImage(sampleMixedImageList, "", WithPlatform("ccrt", "hpvs"))
+	assert.EqualError(t, err, "only one platform can be selected, got: ccrt, hpvs - use HpcrListImages to list images of several platforms")
+
+	imageId, _, _, _, err = HpcrSelectImage(sampleMixedImageList, "", WithPlatform("ccrt", "CCRT"))
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-ccrt-26-7-2", imageId)
+}
+
+// Testcase to check if HpcrListImages() groups the images of several platforms in the requested order
+func TestListImagesPlatforms(t *testing.T) {
+	candidates, err := HpcrListImages(sampleMixedImageList, "", WithPlatform("hpvs", "ccrt"))
+	if err != nil {
+		t.Errorf("failed to list images - %v", err)
+	}
+
+	var platforms []string
+	for _, candidate := range candidates {
+		if len(platforms) == 0 || platforms[len(platforms)-1] != candidate.Platform {
+			platforms = append(platforms, candidate.Platform)
+		}
+	}
+	assert.Equal(t, []string{"hpvs", "ccrt"}, platforms)
+	assert.Equal(t, "r006-hpvs-29", candidates[0].ID)
+	assert.Equal(t, "r006-ccrt-26-7-2", candidates[slices.IndexFunc(candidates, func(c ImageCandidate) bool { return c.Platform == "ccrt" })].ID)
+}
+
+// Testcase to check if HpcrSelectImage() selects images of a custom platform naming
+func TestSelectImageCustomPlatform(t *testing.T) {
+	custom := ImagePlatform{
+		OsType:       "custom",
+		Architecture: "s390x",
+		NamePattern:  regexp.MustCompile(`^ibm-redhat-(\d+)-(\d+)-minimal-s390x-(\d+)$`),
+	}
+
+	imageId, _, _, imageVersion, err := HpcrSelectImage(sampleMixedImageList, "", WithImagePlatform(custom))
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, "r006-other", imageId)
+	assert.Equal(t, "9.4.7", imageVersion)
+}
+
+// Testcase to check if Platform() returns the image naming of each platform
+func TestPlatform(t *testing.T) {
+	image := Image{
+		Architecture: sampleArchitecture,
+		Name:         "ibm-confidential-computing-container-runtime-26-7-2",
+		Os:           "confidential-computing-26-s390x-ccrt",
+		Status:       sampleStatus,
+		Visibility:   sampleVisibility,
+	}
+
+	ccrt, ok := Platform("ccrt")
+	assert.True(t, ok)
+	version, ok := ccrt.Version(image)
+	assert.True(t, ok)
+	assert.Equal(t, "26.7.2", version.String())
+	assert.False(t, IsCandidateImage(image))
+
+	ccrv, ok := Platform("ccrv")
+	assert.True(t, ok)
+	assert.False(t, ccrv.IsCandidate(image))
+
+	hpvs, ok := Platform("hpvs")
+	assert.True(t, ok)
+	hpvsImage := Image{Architecture: sampleArchitecture, Name: sampleName, Os: sampleOs, Status: sampleStatus, Visibility: sampleVisibility}
+	assert.True(t, hpvs.IsCandidate(hpvsImage))
+	assert.True(t, IsCandidateImage(hpvsImage))
+
+	_, ok = Platform("ccco")
+	assert.False(t, ok)
+}
+
+// sampleLifecycleImageList contains images with different status, visibility, deprecation and catalog state
+const sampleLifecycleImageList = `[
+  {"id": "r006-ccrt-26-2-0", "name": "ibm-confidential-computing-container-runtime-26-2-0", "status": "deprecated", "visibility": "public",
+   "created_at": "2026-02-02T10:00:00Z", "deprecation_at": "2026-06-01T00:00:00Z", "owner_type": "provider", "catalog_offering": {"managed": false},
+   "operating_system": {"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}},
+  {"id": "r006-ccrt-26-5-0", "name": "ibm-confidential-computing-container-runtime-26-5-0", "status": "available", "visibility": "public",
+   "created_at": "2026-05-04T10:00:00Z", "deprecation_at": "2026-09-01T00:00:00Z", "owner_type": "provider", "catalog_offering": {"managed": false},
+   "operating_system": {"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}},
+  {"id": "r006-ccrt-26-7-2", "name": "ibm-confidential-computing-container-runtime-26-7-2", "status": "available", "visibility": "public",
+   "created_at": "2026-07-20T10:00:00.000Z", "owner_type": "provider", "catalog_offering": {"managed": false},
+   "operating_system": {"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}},
+  {"id": "r006-ccrt-26-7-2-catalog", "name": "ibm-confidential-computing-container-runtime-26-7-2", "status": "available", "visibility": "private",
+   "created_at": "2026-07-21T10:00:00Z", "owner_type": "user", "catalog_offering": [{"managed": true}],
+   "operating_system": [{"name": "confidential-computing-26-s390x-ccrt", "architecture": "s390x"}]}
+]`
+
+// Testcase to check if HpcrListImages() returns every candidate with its lifecycle state
+func TestHpcrListImages(t *testing.T) {
+	at := time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	candidates, err := HpcrListImages(sampleLifecycleImageList, "", WithPlatform("ccrt"), WithSelectionTime(at))
+	if err != nil {
+		t.Errorf("failed to list images - %v", err)
+	}
+	assert.Len(t, candidates, 2)
+	assert.Equal(t, "r006-ccrt-26-7-2", candidates[0].ID)
+	assert.Equal(t, "ccrt", candidates[0].Platform)
+	assert.Equal(t, "26.7.2", candidates[0].Version.String())
+	assert.Equal(t, time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC), candidates[0].CreatedAt)
+	assert.False(t, candidates[0].Deprecated)
+	assert.Equal(t, "r006-ccrt-26-5-0", candidates[1].ID)
+	assert.False(t, candidates[1].Deprecated)
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), candidates[1].DeprecationAt)
+
+	candidates, err = HpcrListImages(sampleLifecycleImageList, "", WithPlatform("ccrt"), WithSelectionTime(at), WithStatus("available", "deprecated"), WithVisibility("public", "private"))
+	if err != nil {
+		t.Errorf("failed to list images - %v", err)
+	}
+	assert.Len(t, candidates, 4)
+	assert.Equal(t, "r006-ccrt-26-7-2-catalog", candidates[0].ID)
+	assert.True(t, candidates[0].CatalogManaged)
+	assert.Equal(t, "user", candidates[0].OwnerType)
+	assert.Equal(t, "r006-ccrt-26-2-0", candidates[3].ID)
+	assert.True(t, candidates[3].Deprecated)
+
+	candidates, err = HpcrListImages(sampleLifecycleImageList, "", WithPlatform("ccrt"), WithStatus("available", "deprecated"), WithVisibility("private"), WithCatalogManaged(true))
+	if err != nil {
+		t.Errorf("failed to list images - %v", err)
+	}
+	assert.Len(t, candidates, 1)
+	assert.Equal(t, "r006-ccrt-26-7-2-catalog", candidates[0].ID)
+
+	candidates, err = HpcrListImages(sampleLifecycleImageList, "<26.7.0", WithPlatform("ccrt"), WithSelectionTime(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)),
+		WithStatus("available", "deprecated"), WithExcludeDeprecated())
+	if err != nil {
+		t.Errorf("failed to list images - %v", err)
+	}
+	assert.Empty(t, candidates)
+
+	_, err = HpcrListImages(sampleLifecycleImageList, "invalid")
+	assert.ErrorContains(t, err, "error parsing target version constraint")
+
+	_, err = HpcrListImages("", "")
+	assert.EqualError(t, err, "required parameter is empty")
+}
+
+// Testcase to check if HpcrListImages() and HpcrSelectImage() apply the filters to IBM Cloud CLI output
+func TestHpcrListImagesCli(t *testing.T) {
+	imageJsonList, err := gen.ReadDataFromFile(ibmCloudImageListPathCli)
+	if err != nil {
+		t.Errorf("failed to read data from file - %v", err)
+	}
+
+	candidates, err := HpcrListImages(imageJsonList, "")
+	if err != nil {
+		t.Errorf("failed to list images - %v", err)
+	}
+	assert.Len(t, candidates, 4)
+	assert.Equal(t, sampleName, candidates[0].Name)
+	assert.Equal(t, "1.0.19", candidates[3].Version.String())
+	assert.Equal(t, "provider", candidates[0].OwnerType)
+
+	_, _, _, _, err = HpcrSelectImage(imageJsonList, "", WithVisibility("private"))
+	assert.EqualError(t, err, "no Hyper Protect image matching version found")
+
+	_, imageName, _, imageVersion, err := HpcrSelectImage(imageJsonList, "", WithCatalogManaged(false), WithExcludeDeprecated())
+	if err != nil {
+		t.Errorf("failed to select image - %v", err)
+	}
+	assert.Equal(t, sampleName, imageName)
+	assert.Equal(t, sampleLatestVersion, imageVersion)
+}
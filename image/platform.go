@@ -0,0 +1,213 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package image
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/ibm-hyper-protect/contract-go/v2/encryption"
+)
+
+// ImagePlatform describes how the images of a platform are recognised in an IBM Cloud image list.
+type ImagePlatform struct {
+	// Platform identifier ("ccrt", "ccrv" or "hpvs")
+	OsType string
+	// Architecture of the images
+	Architecture string
+	// Pattern the operating system name of the images must match
+	OsPattern *regexp.Regexp
+	// Pattern the image name must match; the first three submatches are the major, minor and patch version
+	NamePattern *regexp.Regexp
+}
+
+var (
+	// reConfidentialComputingOS tests if this is a confidential computing image
+	reConfidentialComputingOS = regexp.MustCompile(`^(?:hyper-protect|confidential-computing)-[\w-]+-s390x(?:-\w+)?$`)
+
+	// reConfidentialComputingName tests if the name references a valid confidential computing version
+	reConfidentialComputingName = regexp.MustCompile(`^ibm-confidential-computing-container-runtime-(\d+)[.-](\d+)[.-](\d+)(?:-s390x)?$`)
+
+	// reConfidentialComputingRhvsName tests if the name references a valid confidential computing version
+	// for Red Hat Virtualization Solutions
+	reConfidentialComputingRhvsName = regexp.MustCompile(`^ibm-confidential-computing-container-runtime-rhvs-(\d+)[.-](\d+)[.-](\d+)(?:-s390x)?$`)
+
+	// reHyperProtectOS tests if this is a Hyper Protect Virtual Servers image
+	reHyperProtectOS = regexp.MustCompile(`^hyper-protect-[\w-]+-s390x-hpcr$`)
+
+	// reHyperProtectName tests if the name references a valid Hyper Protect Virtual Servers version
+	reHyperProtectName = regexp.MustCompile(`^ibm-hyper-protect-container-runtime-(\d+)-(\d+)-s390x-(\d+)$`)
+)
+
+// imagePlatforms lists the image naming of each supported platform
+var imagePlatforms = []ImagePlatform{
+	{OsType: encryption.OsTypeCcrt, Architecture: "s390x", OsPattern: reConfidentialComputingOS, NamePattern: reConfidentialComputingName},
+	{OsType: encryption.OsTypeCcrv, Architecture: "s390x", OsPattern: reConfidentialComputingOS, NamePattern: reConfidentialComputingRhvsName},
+	{OsType: encryption.OsTypeHpvs, Architecture: "s390x", OsPattern: reHyperProtectOS, NamePattern: reHyperProtectName},
+}
+
+// defaultImagePlatforms are searched when no platform is requested. Versions of different platforms
+// are not comparable, so only the HPVS naming is searched, as before platforms could be requested.
+var defaultImagePlatforms = []string{encryption.OsTypeHpvs}
+
+// Platform returns the image naming of a platform.
+//
+// Parameters:
+//   - osType: Platform identifier ("ccrt", "ccrv" or "hpvs")
+//
+// Returns:
+//   - Image naming of the platform
+//   - false if the platform is not supported
+func Platform(osType string) (ImagePlatform, bool) {
+	for _, platform := range imagePlatforms {
+		if platform.OsType == osType {
+			return platform, true
+		}
+	}
+	return ImagePlatform{}, false
+}
+
+// Version returns the version encoded in the image name if the image belongs to the platform.
+//
+// Parameters:
+//   - img: Image structure parsed from IBM Cloud image JSON
+//
+// Returns:
+//   - Image version
+//   - false if the architecture, operating system or name does not match the platform
+func (p ImagePlatform) Version(img Image) (*semver.Version, bool) {
+	if p.NamePattern == nil || img.Architecture != p.Architecture {
+		return nil, false
+	}
+	if p.OsPattern != nil && !p.OsPattern.MatchString(img.Os) {
+		return nil, false
+	}
+
+	matches := p.NamePattern.FindStringSubmatch(img.Name)
+	if len(matches) < 4 {
+		return nil, false
+	}
+
+	version, err := semver.NewVersion(fmt.Sprintf("%s.%s.%s", matches[1], matches[2], matches[3]))
+	if err != nil {
+		return nil, false
+	}
+	return version, true
+}
+
+// IsCandidate checks if an image is an available public image of the platform.
+//
+// Parameters:
+//   - img: Image structure parsed from IBM Cloud image JSON
+//
+// Returns:
+//   - true if the image is an available public image of the platform, false otherwise
+func (p ImagePlatform) IsCandidate(img Image) bool {
+	if img.Status != "available" || img.Visibility != "public" {
+		return false
+	}
+	_, ok := p.Version(img)
+	return ok
+}
+
+// SelectOption configures image selection.
+type SelectOption func(*selectConfig)
+
+type selectConfig struct {
+	platformNames     []string
+	platforms         []ImagePlatform
+	statuses          []string
+	visibilities      []string
+	excludeDeprecated bool
+	catalogManaged    *bool
+	at                time.Time
+}
+
+// WithPlatform selects images of the given platforms ("ccrt", "ccrv" or "hpvs") only. By default only
+// hpvs images are selected. [HpcrSelectImage] accepts a single platform only.
+func WithPlatform(osTypes ...string) SelectOption {
+	return func(c *selectConfig) {
+		c.platformNames = append(c.platformNames, osTypes...)
+	}
+}
+
+// WithImagePlatform also selects images matching a custom platform naming, e.g. for images that are
+// not yet known to this library.
+func WithImagePlatform(platform ImagePlatform) SelectOption {
+	return func(c *selectConfig) {
+		c.platforms = append(c.platforms, platform)
+	}
+}
+
+// newSelectConfig applies the select options and resolves the requested platforms.
+func newSelectConfig(opts []SelectOption) (*selectConfig, error) {
+	config := &selectConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	names := config.platformNames
+	if len(names) == 0 && len(config.platforms) == 0 {
+		names = defaultImagePlatforms
+	}
+
+	platforms := make([]ImagePlatform, 0, len(names)+len(config.platforms))
+	for _, name := range names {
+		platform, ok := Platform(strings.ToLower(name))
+		if !ok {
+			return nil, fmt.Errorf("invalid OS type: %s. Valid types are: %s", name, strings.Join(platformNames(), ", "))
+		}
+		platforms = append(platforms, platform)
+	}
+	config.platforms = append(platforms, config.platforms...)
+
+	if len(config.statuses) == 0 {
+		config.statuses = []string{"available"}
+	}
+	if len(config.visibilities) == 0 {
+		config.visibilities = []string{"public"}
+	}
+	if config.at.IsZero() {
+		config.at = time.Now()
+	}
+
+	return config, nil
+}
+
+// platformTypes returns the distinct identifiers of the requested platforms in the order they were requested.
+func (c *selectConfig) platformTypes() []string {
+	var types []string
+	for _, platform := range c.platforms {
+		if !slices.Contains(types, platform.OsType) {
+			types = append(types, platform.OsType)
+		}
+	}
+	return types
+}
+
+// platformNames returns the identifiers of the supported platforms.
+func platformNames() []string {
+	names := make([]string, 0, len(imagePlatforms))
+	for _, platform := range imagePlatforms {
+		names = append(names, platform.OsType)
+	}
+	return names
+}
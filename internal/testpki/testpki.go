@@ -0,0 +1,158 @@
+// Copyright (c) 2025 IBM Corp.
+// All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testpki creates RSA certificate chains and CRLs for tests.
+package testpki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// MustGenerateRSAKey generates a 2048 bit RSA key.
+func MustGenerateRSAKey(t testing.TB) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+// CATemplate returns the template of a CA certificate that may sign certificates and CRLs.
+func CATemplate(t testing.TB, commonName string, serial *big.Int, notBefore, notAfter time.Time, crlDP []string, authorityKeyID []byte, maxPathLen int) *x509.Certificate {
+	t.Helper()
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Test Org"}},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		SignatureAlgorithm:    x509.SHA512WithRSA,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        maxPathLen == 0,
+		MaxPathLen:            maxPathLen,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		CRLDistributionPoints: crlDP,
+		SubjectKeyId:          MustRandomBytes(t, 20),
+		AuthorityKeyId:        authorityKeyID,
+	}
+}
+
+// LeafTemplate returns the template of an end-entity certificate valid from two hours before now for a day,
+// or an expired or not yet valid one.
+func LeafTemplate(t testing.TB, commonName string, serial *big.Int, now time.Time, expired, future, includeCRLDP bool, crlDP string, authorityKeyID []byte) *x509.Certificate {
+	t.Helper()
+
+	notBefore := now.Add(-2 * time.Hour)
+	notAfter := now.Add(24 * time.Hour)
+	switch {
+	case expired:
+		notBefore = now.Add(-48 * time.Hour)
+		notAfter = now.Add(-2 * time.Hour)
+	case future:
+		notBefore = now.Add(2 * time.Hour)
+		notAfter = now.Add(48 * time.Hour)
+	}
+
+	var crlDistributionPoints []string
+	if includeCRLDP {
+		crlDistributionPoints = []string{crlDP}
+	}
+
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Test Org"}},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		SignatureAlgorithm:    x509.SHA512WithRSA,
+		BasicConstraintsValid: true,
+		IsCA:                  false,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		CRLDistributionPoints: crlDistributionPoints,
+		AuthorityKeyId:        authorityKeyID,
+		SubjectKeyId:          MustRandomBytes(t, 20),
+	}
+}
+
+// MustCreateCertificate signs template with parentKey and returns the DER certificate.
+func MustCreateCertificate(t testing.TB, template, parent *x509.Certificate, publicKey *rsa.PublicKey, parentKey *rsa.PrivateKey) []byte {
+	t.Helper()
+	certDER, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	require.NoError(t, err)
+	return certDER
+}
+
+// MustParseCertificate parses a DER certificate.
+func MustParseCertificate(t testing.TB, certDER []byte) *x509.Certificate {
+	t.Helper()
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+	return cert
+}
+
+// MustCreateCRL returns a DER CRL issued by issuer that revokes the given serials and expires a day after now.
+func MustCreateCRL(t testing.TB, issuer *x509.Certificate, signer *rsa.PrivateKey, revokedSerials []*big.Int, now time.Time) []byte {
+	t.Helper()
+
+	entries := make([]x509.RevocationListEntry, 0, len(revokedSerials))
+	for _, serial := range revokedSerials {
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: now.Add(-30 * time.Minute),
+		})
+	}
+
+	template := &x509.RevocationList{
+		Number:                    big.NewInt(1),
+		ThisUpdate:                now.Add(-1 * time.Hour),
+		NextUpdate:                now.Add(24 * time.Hour),
+		SignatureAlgorithm:        x509.SHA512WithRSA,
+		RevokedCertificateEntries: entries,
+	}
+
+	crlDER, err := x509.CreateRevocationList(rand.Reader, template, issuer, signer)
+	require.NoError(t, err)
+	return crlDER
+}
+
+// PEMEncodeCert encodes a DER certificate as PEM.
+func PEMEncodeCert(certDER []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certDER,
+	}))
+}
+
+// MustRandomBytes returns size random bytes.
+func MustRandomBytes(t testing.TB, size int) []byte {
+	t.Helper()
+	buffer := make([]byte, size)
+	_, err := rand.Read(buffer)
+	require.NoError(t, err)
+	return buffer
+}
+
+// FileURL returns the file URL of a local path, e.g. to reference a CRL written by a test.
+func FileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}